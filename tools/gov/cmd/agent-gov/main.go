@@ -3,7 +3,7 @@ package main
 import (
 	"os"
 
-	"agent-governance-strategy/tools/gov/internal/cli"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/cli"
 )
 
 func main() {
@@ -1,4 +1,4 @@
-module agent-governance-strategy/tools/gov
+module github.com/BennettSmith/agent-governance-strategy/tools/gov
 
 go 1.22
 
@@ -0,0 +1,216 @@
+// Package govblocks reads and updates the managed blocks that agent-gov writes into
+// governed files, so other Go programs can interoperate with them.
+//
+// A managed block is delimited by BEGIN and END marker comments:
+//
+//	<!-- GOV:BEGIN id=doc-constitution version=v1.2.0 sha256=... -->
+//	...profile-owned content...
+//	<!-- GOV:END id=doc-constitution -->
+//
+// Markers may also be written as `# ...`, `// ...` or `/* ... */` comments; ParseFile only
+// recognises the style that suits the file's type. Text outside the blocks belongs to the
+// project and is never modified by this package.
+package govblocks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/managedblocks"
+)
+
+// DefaultPrefix is the marker namespace agent-gov uses unless configured otherwise.
+const DefaultPrefix = "GOV"
+
+// Errors returned for malformed markers wrap one of these sentinels in a *MarkerError;
+// test with errors.Is or errors.As.
+var (
+	ErrMissingID       = managedblocks.ErrMissingID
+	ErrDuplicateBegin  = managedblocks.ErrDuplicateBegin
+	ErrEndWithoutBegin = managedblocks.ErrEndWithoutBegin
+	ErrUnclosedBlock   = managedblocks.ErrUnclosedBlock
+)
+
+// Errors returned by lookups, updates and verification.
+var (
+	// ErrBlockNotFound is returned when the document has no block with the requested id.
+	ErrBlockNotFound = managedblocks.ErrBlockNotFound
+	// ErrDuplicateBlock is returned when more than one block has the requested id.
+	ErrDuplicateBlock = managedblocks.ErrDuplicateBlock
+	// ErrChecksumMismatch is returned by Verify when content and recorded sha256 differ.
+	ErrChecksumMismatch = managedblocks.ErrChecksumMismatch
+)
+
+// MarkerError reports a malformed marker: its kind ("BEGIN" or "END"), block id (if any),
+// 1-based line, and the sentinel error (Err) describing the problem.
+type MarkerError = managedblocks.MarkerError
+
+// Block is one managed block of a Document.
+type Block struct {
+	ID string
+	// Meta holds every field of the BEGIN marker, including id and sha256.
+	Meta map[string]string
+	// Syntax is the marker comment style: "html", "hash", "slash" or "block".
+	Syntax string
+	// BeginLine and EndLine are the 1-based lines of the BEGIN and END markers.
+	BeginLine int
+	EndLine   int
+	// Content is the text between the markers, without a trailing newline.
+	Content string
+}
+
+// Document is a parsed file containing managed blocks. The zero value is not usable;
+// create one with Parse or ParseFile.
+type Document struct {
+	prefix string
+	syntax managedblocks.Syntax
+	text   string
+	blocks []Block
+}
+
+// Parse parses text, validating that every marker with the given prefix pairs up. Markers
+// may be written in any comment style; use ParseFile when the file's type is known. An
+// empty prefix means DefaultPrefix. Malformed markers are reported as a *MarkerError.
+func Parse(text, prefix string) (*Document, error) {
+	return parse(text, prefix, managedblocks.Syntax{})
+}
+
+// ParseFile is like Parse, but only recognises markers in the comment style agent-gov uses
+// for a file with name's extension (`<!-- -->` for Markdown, `#` for YAML, `//` for Go, ...).
+// Marker-like lines in another style, such as a `# GOV:BEGIN` line in a Markdown code
+// sample, are left as text.
+func ParseFile(name, text, prefix string) (*Document, error) {
+	return parse(text, prefix, managedblocks.SyntaxForPath(name))
+}
+
+func parse(text, prefix string, syntax managedblocks.Syntax) (*Document, error) {
+	if strings.TrimSpace(prefix) == "" {
+		prefix = DefaultPrefix
+	}
+	d := &Document{prefix: prefix, syntax: syntax}
+	if err := d.load(text); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Document) load(text string) error {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	found, err := managedblocks.FindBlocks(lines, d.syntax, d.prefix)
+	if err != nil {
+		return err
+	}
+	blocks := make([]Block, 0, len(found))
+	for _, b := range found {
+		blocks = append(blocks, Block{
+			ID:        b.ID,
+			Meta:      copyMeta(b.Meta),
+			Syntax:    b.Syntax.Name,
+			BeginLine: b.BeginLineIdx + 1,
+			EndLine:   b.EndLineIdx + 1,
+			Content:   strings.Join(lines[b.BeginLineIdx+1:b.EndLineIdx], "\n"),
+		})
+	}
+	d.text, d.blocks = text, blocks
+	return nil
+}
+
+// Prefix returns the marker namespace the document was parsed with.
+func (d *Document) Prefix() string { return d.prefix }
+
+// Blocks returns the document's blocks in file order.
+func (d *Document) Blocks() []Block {
+	out := make([]Block, len(d.blocks))
+	for i, b := range d.blocks {
+		out[i] = b
+		out[i].Meta = copyMeta(b.Meta)
+	}
+	return out
+}
+
+// IDs returns the block ids in file order.
+func (d *Document) IDs() []string {
+	ids := make([]string, 0, len(d.blocks))
+	for _, b := range d.blocks {
+		ids = append(ids, b.ID)
+	}
+	return ids
+}
+
+// Block returns the block with the given id.
+func (d *Document) Block(id string) (Block, error) {
+	var match *Block
+	for i := range d.blocks {
+		if d.blocks[i].ID != id {
+			continue
+		}
+		if match != nil {
+			return Block{}, fmt.Errorf("%w with id %q", ErrDuplicateBlock, id)
+		}
+		match = &d.blocks[i]
+	}
+	if match == nil {
+		return Block{}, fmt.Errorf("%w: %q", ErrBlockNotFound, id)
+	}
+	b := *match
+	b.Meta = copyMeta(match.Meta)
+	return b, nil
+}
+
+// Content returns the content of the block with the given id.
+func (d *Document) Content(id string) (string, error) {
+	b, err := d.Block(id)
+	if err != nil {
+		return "", err
+	}
+	return b.Content, nil
+}
+
+// Replace sets the content of the block with the given id and merges meta into its BEGIN
+// marker; an empty value removes a field. The sha256 field is recomputed (keeping the
+// block's hash normalisation) and the id cannot be changed. Text outside the block is
+// left untouched.
+func (d *Document) Replace(id, content string, meta map[string]string) error {
+	b, err := d.Block(id)
+	if err != nil {
+		return err
+	}
+	updates := map[string]string{}
+	for k, v := range meta {
+		if k == "id" || k == "sha256" || k == managedblocks.HashMetaKey {
+			continue
+		}
+		updates[k] = v
+	}
+	text, err := managedblocks.ReplaceBlock(d.text, d.syntax, managedblocks.ReplaceOptions{
+		Prefix:        d.prefix,
+		BlockID:       id,
+		NewContent:    content,
+		MetaUpdates:   updates,
+		Normalization: managedblocks.Normalization(b.Meta[managedblocks.HashMetaKey]),
+	})
+	if err != nil {
+		return err
+	}
+	return d.load(text)
+}
+
+// Verify checks that the block's content matches the sha256 recorded in its BEGIN
+// marker. A mismatch wraps ErrChecksumMismatch.
+func (d *Document) Verify(id string) error {
+	if _, err := d.Block(id); err != nil {
+		return err
+	}
+	return managedblocks.VerifyBlockSHA256(d.text, d.syntax, d.prefix, id)
+}
+
+// Render returns the document text including any replacements.
+func (d *Document) Render() string { return d.text }
+
+func copyMeta(meta map[string]string) map[string]string {
+	out := make(map[string]string, len(meta))
+	for k, v := range meta {
+		out[k] = v
+	}
+	return out
+}
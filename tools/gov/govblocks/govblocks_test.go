@@ -0,0 +1,126 @@
+package govblocks
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const sample = `# Project rules
+<!-- GOV:BEGIN id=doc-rules version=v1 sha256=4dfbd6b3d1aca8ba8d1e1a1d8f5b5f0e2c1b6e5c1f3b0a9e8d7c6b5a4f3e2d1c sourceRepo="/Users/x/My Repos/gov" -->
+- one
+- two
+<!-- GOV:END id=doc-rules -->
+
+## Local Addenda (project-owned)
+
+local text
+
+# GOV:BEGIN id=cfg sha256=x
+lint: strict
+# GOV:END id=cfg
+`
+
+func TestParse_ListsBlocksWithMetadata(t *testing.T) {
+	d, err := Parse(sample, "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if d.Prefix() != "GOV" || strings.Join(d.IDs(), ",") != "doc-rules,cfg" {
+		t.Fatalf("unexpected document: prefix=%s ids=%v", d.Prefix(), d.IDs())
+	}
+	blocks := d.Blocks()
+	b := blocks[0]
+	if b.Syntax != "html" || b.BeginLine != 2 || b.EndLine != 5 || b.Content != "- one\n- two" {
+		t.Fatalf("unexpected block: %+v", b)
+	}
+	if b.Meta["version"] != "v1" || b.Meta["sourceRepo"] != "/Users/x/My Repos/gov" {
+		t.Fatalf("unexpected meta: %#v", b.Meta)
+	}
+	if blocks[1].Syntax != "hash" || blocks[1].Content != "lint: strict" {
+		t.Fatalf("unexpected block: %+v", blocks[1])
+	}
+
+	// Returned metadata is a copy.
+	b.Meta["version"] = "changed"
+	if got, _ := d.Block("doc-rules"); got.Meta["version"] != "v1" {
+		t.Fatalf("Blocks must not expose internal state")
+	}
+}
+
+func TestReplace_UpdatesOnlyTheBlock(t *testing.T) {
+	d, err := Parse(sample, "GOV")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if err := d.Replace("doc-rules", "- one\n- two\n- three\n", map[string]string{"version": "v2", "id": "ignored", "sourceRepo": ""}); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	out := d.Render()
+	if !strings.HasPrefix(out, "# Project rules\n<!-- GOV:BEGIN id=doc-rules version=v2 sha256=") ||
+		!strings.Contains(out, "-->\n- one\n- two\n- three\n<!-- GOV:END id=doc-rules -->\n\n## Local Addenda (project-owned)\n\nlocal text\n") ||
+		strings.Contains(out, "sourceRepo") {
+		t.Fatalf("unexpected render:\n%s", out)
+	}
+	if got, _ := d.Content("doc-rules"); got != "- one\n- two\n- three" {
+		t.Fatalf("unexpected content: %q", got)
+	}
+	if err := d.Verify("doc-rules"); err != nil {
+		t.Fatalf("Verify after Replace: %v", err)
+	}
+	if err := d.Verify("cfg"); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if err := d.Replace("missing", "x", nil); !errors.Is(err, ErrBlockNotFound) {
+		t.Fatalf("expected ErrBlockNotFound, got %v", err)
+	}
+}
+
+func TestParse_ReportsMalformedMarkers(t *testing.T) {
+	cases := []struct {
+		text string
+		want error
+		kind string
+		id   string
+		line int
+	}{
+		{text: "<!-- GOV:BEGIN version=v1 -->\n<!-- GOV:END id=a -->\n", want: ErrMissingID, kind: "BEGIN", line: 1},
+		{text: "x\n<!-- GOV:BEGIN id=a -->\n<!-- GOV:BEGIN id=a -->\n", want: ErrDuplicateBegin, kind: "BEGIN", id: "a", line: 3},
+		{text: "<!-- GOV:END id=a -->\n", want: ErrEndWithoutBegin, kind: "END", id: "a", line: 1},
+		{text: "a\nb\n# GOV:BEGIN id=b\n<!-- GOV:BEGIN id=a -->\n", want: ErrUnclosedBlock, kind: "BEGIN", id: "b", line: 3},
+	}
+	for _, c := range cases {
+		_, err := Parse(c.text, "GOV")
+		if !errors.Is(err, c.want) {
+			t.Fatalf("Parse(%q): expected %v, got %v", c.text, c.want, err)
+		}
+		var me *MarkerError
+		if !errors.As(err, &me) || me.Kind != c.kind || me.ID != c.id || me.Line != c.line {
+			t.Fatalf("Parse(%q): unexpected marker error %#v", c.text, me)
+		}
+	}
+}
+
+func TestParseFile_OnlyRecognisesTheFileSyntax(t *testing.T) {
+	d, err := ParseFile("docs/Rules.md", sample, "")
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if strings.Join(d.IDs(), ",") != "doc-rules" {
+		t.Fatalf("expected only the HTML block in a Markdown file, got %v", d.IDs())
+	}
+	if err := d.Replace("doc-rules", "- three", nil); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	if !strings.HasSuffix(d.Render(), "# GOV:BEGIN id=cfg sha256=x\nlint: strict\n# GOV:END id=cfg\n") {
+		t.Fatalf("text outside the Markdown block changed:\n%s", d.Render())
+	}
+
+	d, err = ParseFile("config.yaml", sample, "")
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if strings.Join(d.IDs(), ",") != "cfg" {
+		t.Fatalf("expected only the hash block in a YAML file, got %v", d.IDs())
+	}
+}
@@ -0,0 +1,191 @@
+package govkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/builder"
+)
+
+// FileDiff is the change Sync would make to one file. Old is empty for a new file.
+type FileDiff struct {
+	Path string
+	Old  string
+	New  string
+}
+
+// DiffResult lists the files Sync would change, sorted by path.
+type DiffResult struct {
+	Files []FileDiff
+}
+
+// Changed reports whether Sync would change anything.
+func (r DiffResult) Changed() bool { return len(r.Files) > 0 }
+
+// Diff reports what Sync would change without writing anything.
+func Diff(ctx context.Context, opts ...Option) (DiffResult, error) {
+	o, err := resolve(opts)
+	if err != nil {
+		return DiffResult{}, err
+	}
+	rec := &recordingFS{base: o.fsys, writes: map[string][]byte{}}
+	if rec.base == nil {
+		rec.base = DirFS(o.repoRoot)
+	}
+	so := o.syncOptions()
+	so.FS = rec
+	if _, err := builder.Sync(ctx, so); err != nil {
+		return DiffResult{}, err
+	}
+
+	var out DiffResult
+	for name, data := range rec.writes {
+		old, err := rec.base.ReadFile(name)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return DiffResult{}, err
+		}
+		if bytes.Equal(old, data) {
+			continue
+		}
+		out.Files = append(out.Files, FileDiff{Path: name, Old: string(old), New: string(data)})
+	}
+	sort.Slice(out.Files, func(i, j int) bool { return out.Files[i].Path < out.Files[j].Path })
+	return out, nil
+}
+
+// recordingFS reads through base and keeps writes in memory.
+type recordingFS struct {
+	base   FS
+	writes map[string][]byte
+}
+
+func (r *recordingFS) Open(name string) (fs.File, error) { return r.base.Open(name) }
+
+func (r *recordingFS) ReadFile(name string) ([]byte, error) {
+	if data, ok := r.writes[name]; ok {
+		return append([]byte(nil), data...), nil
+	}
+	return r.base.ReadFile(name)
+}
+
+func (r *recordingFS) WriteFile(name string, data []byte, _ fs.FileMode) error {
+	r.writes[name] = append([]byte(nil), data...)
+	return nil
+}
+
+func (r *recordingFS) MkdirAll(string, fs.FileMode) error { return nil }
+
+// Unified renders the change as a unified diff with three lines of context.
+func (d FileDiff) Unified() string {
+	const context = 3
+	a, b := splitLines(d.Old), splitLines(d.New)
+	ops := diffLines(a, b)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", d.Path, d.Path)
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk while changes are at most 2*context lines apart.
+		start := max(i-context, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				break
+			}
+			end = run
+		}
+		stop := min(end+context, len(ops))
+
+		aStart, bStart, aLen, bLen := ops[start].a, ops[start].b, 0, 0
+		for _, op := range ops[start:stop] {
+			if op.kind != '+' {
+				aLen++
+			}
+			if op.kind != '-' {
+				bLen++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+		for _, op := range ops[start:stop] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.text)
+			sb.WriteByte('\n')
+		}
+		i = stop
+	}
+	return sb.String()
+}
+
+type lineOp struct {
+	kind byte // ' ', '-' or '+'
+	text string
+	// a and b are the 0-based line indexes in the old and new text at this op.
+	a, b int
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes a line diff via the longest common subsequence.
+func diffLines(a, b []string) []lineOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var ops []lineOp
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			ops = append(ops, lineOp{' ', a[i], i, j})
+			i, j = i+1, j+1
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, lineOp{'-', a[i], i, j})
+			i++
+		default:
+			ops = append(ops, lineOp{'+', b[j], i, j})
+			j++
+		}
+	}
+	return ops
+}
+
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if n == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
@@ -0,0 +1,291 @@
+// Package govkit applies governance profiles to a repository: it renders the profile's
+// documents into managed blocks (Init), updates them in place (Sync), checks them (Verify),
+// previews updates (Diff) and fixes damaged block markers (Repair). LoadProfile,
+// ListProfiles and ValidateProfiles inspect the profiles themselves. It is the library
+// behind the agent-gov CLI.
+//
+//	res, err := govkit.Sync(ctx,
+//		govkit.WithSource("https://example.com/governance.git", "v1.2.0", "backend-go-hex"),
+//		govkit.WithRepoRoot("/src/service"),
+//	)
+//
+// The filesystem and git access are injectable (WithFS, WithGit), so the operations can
+// run against in-memory files and pre-fetched sources.
+package govkit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/builder"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/conditions"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/managedblocks"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/source"
+)
+
+// InitResult reports what Init wrote.
+type InitResult struct {
+	DocsWritten  int
+	FilesWritten int
+	SourceCommit string
+}
+
+// Init writes the profile's documents, templates and playbooks into the repository.
+// Markdown documents are (re)created with an empty addenda section; managed blocks in
+// other files are inserted or updated in place.
+func Init(ctx context.Context, opts ...Option) (InitResult, error) {
+	o, err := resolve(opts)
+	if err != nil {
+		return InitResult{}, err
+	}
+	res, err := builder.Init(ctx, builder.InitOptions{
+		RepoRoot:          o.repoRoot,
+		DocsRoot:          o.docsRoot,
+		FS:                o.fsys,
+		CacheDir:          o.cacheDir,
+		SourceRepo:        o.repo,
+		SourceRef:         o.ref,
+		ProfileID:         o.profileID,
+		Fetch:             o.fetcher(),
+		MarkerPrefix:      o.markerPrefix,
+		AddendaHeading:    o.addendaHeading,
+		HashNormalization: managedblocks.Normalization(o.hash),
+		Variables:         o.variables,
+		Facts:             conditions.Facts(o.facts),
+		Parameters:        o.parameters,
+	})
+	if err != nil {
+		return InitResult{}, err
+	}
+	return InitResult{DocsWritten: res.DocsWritten, FilesWritten: res.ExtraFilesWritten, SourceCommit: res.SourceCommit}, nil
+}
+
+// BuildResult reports what Build wrote.
+type BuildResult struct {
+	DocsWritten  int
+	FilesWritten int
+	SourceCommit string
+}
+
+// Build assembles the governance bundle into outDir (or into the WithFS filesystem,
+// in which case outDir may be empty).
+func Build(ctx context.Context, outDir string, opts ...Option) (BuildResult, error) {
+	o, err := resolve(opts)
+	if err != nil {
+		return BuildResult{}, err
+	}
+	res, err := builder.Build(ctx, builder.BuildOptions{
+		OutDir:            outDir,
+		DocsRoot:          o.docsRoot,
+		FS:                o.fsys,
+		CacheDir:          o.cacheDir,
+		SourceRepo:        o.repo,
+		SourceRef:         o.ref,
+		ProfileID:         o.profileID,
+		Fetch:             o.fetcher(),
+		MarkerPrefix:      o.markerPrefix,
+		AddendaHeading:    o.addendaHeading,
+		HashNormalization: managedblocks.Normalization(o.hash),
+		Variables:         o.variables,
+		Facts:             conditions.Facts(o.facts),
+		Parameters:        o.parameters,
+	})
+	if err != nil {
+		return BuildResult{}, err
+	}
+	return BuildResult{DocsWritten: res.DocsWritten, FilesWritten: res.ExtraFilesWritten, SourceCommit: res.SourceCommit}, nil
+}
+
+// SyncResult reports what Sync updated.
+type SyncResult struct {
+	DocsUpdated int
+}
+
+// Sync updates the managed blocks of every governed document in place. Text outside the
+// blocks is preserved. No file changes unless every document renders; on disk the new
+// contents are staged next to their targets and renamed into place, with a journal so
+// an interrupted run can be finished (ResumeSync) or undone (RevertSync).
+func Sync(ctx context.Context, opts ...Option) (SyncResult, error) {
+	o, err := resolve(opts)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	res, err := builder.Sync(ctx, o.syncOptions())
+	if err != nil {
+		return SyncResult{}, err
+	}
+	return SyncResult{DocsUpdated: res.DocsUpdated}, nil
+}
+
+// SyncError reports the applications SyncAll could not render. Errs is indexed like the
+// applications, nil for each one that rendered.
+type SyncError = builder.SyncError
+
+// SyncAll syncs several profile applications, one option list each, into the same
+// repository as a single commit: every application's documents are rendered first, and
+// no file changes unless all of them render. The applications must share WithRepoRoot
+// and WithFS, and must not write the same files (ErrOverlappingOutputs).
+func SyncAll(ctx context.Context, apps ...[]Option) ([]SyncResult, error) {
+	all := make([]builder.SyncOptions, 0, len(apps))
+	for _, opts := range apps {
+		o, err := resolve(opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, o.syncOptions())
+	}
+	res, err := builder.SyncApplications(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SyncResult, len(res))
+	for i, r := range res {
+		out[i] = SyncResult{DocsUpdated: r.DocsUpdated}
+	}
+	return out, nil
+}
+
+// ErrOverlappingOutputs is returned (wrapped) by SyncAll when more than one application
+// would write the same file; nothing is written.
+var ErrOverlappingOutputs = builder.ErrOverlappingOutputs
+
+// ErrInterruptedSync is returned by Sync, SyncAll and Diff when an earlier sync was
+// interrupted while replacing files; call ResumeSync or RevertSync first.
+var ErrInterruptedSync = builder.ErrInterruptedSync
+
+// ResumeSync finishes a Sync that was interrupted after all its documents were staged,
+// and returns how many files it replaced. It needs only WithRepoRoot or WithFS.
+func ResumeSync(opts ...Option) (int, error) {
+	return builder.ResumeSync(targetOf(opts))
+}
+
+// RevertSync restores the files an interrupted Sync had started to replace, and returns
+// how many it restored. It needs only WithRepoRoot or WithFS.
+func RevertSync(opts ...Option) (int, error) {
+	return builder.RevertSync(targetOf(opts))
+}
+
+func targetOf(opts []Option) FS {
+	o := options{repoRoot: "."}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.fsys != nil {
+		return o.fsys
+	}
+	return DirFS(o.repoRoot)
+}
+
+// Issue is a verification problem with one document, or one section of it.
+type Issue struct {
+	// Document is the output path, with "#section" for a named section.
+	Document string
+	Message  string
+}
+
+func (i Issue) String() string { return i.Document + ": " + i.Message }
+
+// VerifyResult reports whether every managed block matches the profile.
+type VerifyResult struct {
+	OK     bool
+	Issues []Issue
+}
+
+// Verify checks every governed document's managed blocks against their recorded hashes
+// and against the variables, conditions and fragments they were rendered from.
+func Verify(ctx context.Context, opts ...Option) (VerifyResult, error) {
+	o, err := resolve(opts)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	so := o.syncOptions()
+	res, err := builder.Verify(ctx, builder.VerifyOptions{
+		RepoRoot:          so.RepoRoot,
+		DocsRoot:          so.DocsRoot,
+		FS:                so.FS,
+		CacheDir:          so.CacheDir,
+		SourceRepo:        so.SourceRepo,
+		SourceRef:         so.SourceRef,
+		ProfileID:         so.ProfileID,
+		Fetch:             so.Fetch,
+		MarkerPrefix:      so.MarkerPrefix,
+		HashNormalization: so.HashNormalization,
+		Variables:         so.Variables,
+		Facts:             so.Facts,
+		Parameters:        so.Parameters,
+	})
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	out := VerifyResult{OK: res.OK}
+	for _, issue := range res.Issues {
+		out.Issues = append(out.Issues, Issue{Document: issue.Document, Message: issue.Message})
+	}
+	return out, nil
+}
+
+func resolve(opts []Option) (options, error) {
+	o, err := resolveDefaults(opts)
+	if err != nil {
+		return options{}, err
+	}
+	if strings.TrimSpace(o.repo) == "" || strings.TrimSpace(o.ref) == "" || strings.TrimSpace(o.profileID) == "" {
+		return options{}, errors.New("govkit: WithSource(repo, ref, profile) is required")
+	}
+	return o, nil
+}
+
+// resolveDefaults applies opts over the defaults without requiring a source.
+func resolveDefaults(opts []Option) (options, error) {
+	o := options{repoRoot: ".", docsRoot: "."}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if _, err := managedblocks.ParseNormalization(o.hash); err != nil {
+		return options{}, err
+	}
+	if strings.TrimSpace(o.cacheDir) == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			return options{}, err
+		}
+		o.cacheDir = filepath.Join(base, "govbuilder")
+	}
+	if o.git == nil {
+		o.git = CLIGit()
+	}
+	return o, nil
+}
+
+// fetcher adapts the configured Git to the builder's source fetcher.
+func (o options) fetcher() source.Fetcher {
+	git := o.git
+	return func(ctx context.Context, opts source.FetchOptions) (source.ResolvedSource, error) {
+		c, err := git.Fetch(ctx, opts.RepoURL, opts.Ref, opts.CacheDir)
+		if err != nil {
+			return source.ResolvedSource{}, err
+		}
+		return source.ResolvedSource{CheckoutDir: c.Dir, SourceRepo: c.Repo, SourceRef: c.Ref, SourceCommit: c.Commit, FS: c.FS}, nil
+	}
+}
+
+func (o options) syncOptions() builder.SyncOptions {
+	return builder.SyncOptions{
+		RepoRoot:          o.repoRoot,
+		DocsRoot:          o.docsRoot,
+		FS:                o.fsys,
+		CacheDir:          o.cacheDir,
+		SourceRepo:        o.repo,
+		SourceRef:         o.ref,
+		ProfileID:         o.profileID,
+		Fetch:             o.fetcher(),
+		MarkerPrefix:      o.markerPrefix,
+		HashNormalization: managedblocks.Normalization(o.hash),
+		Variables:         o.variables,
+		Facts:             conditions.Facts(o.facts),
+		Parameters:        o.parameters,
+	}
+}
@@ -0,0 +1,226 @@
+package govkit
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// memFS is an in-memory FS for tests.
+type memFS struct{ fstest.MapFS }
+
+func (m memFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.MapFS[name] = &fstest.MapFile{Data: append([]byte(nil), data...), Mode: perm}
+	return nil
+}
+
+func (m memFS) MkdirAll(string, fs.FileMode) error { return nil }
+
+// fakeGit serves one plain directory per ref, without running git.
+type fakeGit map[string]string
+
+func (g fakeGit) Fetch(_ context.Context, repo, ref, _ string) (Checkout, error) {
+	return Checkout{Dir: g[ref], Repo: repo, Ref: ref, Commit: "commit-" + ref}, nil
+}
+
+func writeSource(t *testing.T, dir, profileFragment string) {
+	t.Helper()
+	files := map[string]string{
+		"Governance/Core/Rules.Core.md":            "CORE\n",
+		"Governance/Profiles/svc/Rules.Profile.md": profileFragment,
+		"Governance/Profiles/svc/profile.yaml": "schemaVersion: 1\nid: svc\ndocuments:\n" +
+			"  - output: Rules.md\n    fragments:\n      - ../../Core/Rules.Core.md\n      - ./Rules.Profile.md\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func TestInitDiffSyncVerify_WithInjectedFSAndGit(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	v1, v2 := filepath.Join(tmp, "v1"), filepath.Join(tmp, "v2")
+	writeSource(t, v1, "PROFILE v1\n")
+	writeSource(t, v2, "PROFILE v2\n")
+
+	fsys := memFS{fstest.MapFS{}}
+	opts := func(ref string) []Option {
+		return []Option{
+			WithSource("https://example.com/gov.git", ref, "svc"),
+			WithCacheDir(filepath.Join(tmp, "cache")),
+			WithFS(fsys),
+			WithGit(fakeGit{"v1": v1, "v2": v2}),
+		}
+	}
+
+	ir, err := Init(ctx, opts("v1")...)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if ir.DocsWritten != 1 || ir.SourceCommit != "commit-v1" {
+		t.Fatalf("unexpected init result: %+v", ir)
+	}
+	doc := string(fsys.MapFS["Rules.md"].Data)
+	if !strings.Contains(doc, "PROFILE v1") || !strings.Contains(doc, "sourceCommit=commit-v1") {
+		t.Fatalf("unexpected document:\n%s", doc)
+	}
+	if _, err := os.Stat("Rules.md"); err == nil {
+		t.Fatalf("Init must not write to the working directory")
+	}
+
+	// Project-owned text below the block.
+	fsys.MapFS["Rules.md"].Data = append(fsys.MapFS["Rules.md"].Data, []byte("\n- local rule\n")...)
+	before := string(fsys.MapFS["Rules.md"].Data)
+
+	dr, err := Diff(ctx, opts("v1")...)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if dr.Changed() {
+		t.Fatalf("expected no changes, got %+v", dr.Files)
+	}
+
+	dr, err = Diff(ctx, opts("v2")...)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if len(dr.Files) != 1 || dr.Files[0].Path != "Rules.md" || dr.Files[0].Old != before {
+		t.Fatalf("unexpected diff: %+v", dr.Files)
+	}
+	u := dr.Files[0].Unified()
+	if !strings.HasPrefix(u, "--- a/Rules.md\n+++ b/Rules.md\n@@ ") ||
+		!strings.Contains(u, "\n-PROFILE v1\n+PROFILE v2\n") || strings.Contains(u, "local rule") {
+		t.Fatalf("unexpected unified diff:\n%s", u)
+	}
+	if string(fsys.MapFS["Rules.md"].Data) != before {
+		t.Fatalf("Diff must not write")
+	}
+
+	sr, err := Sync(ctx, opts("v2")...)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if sr.DocsUpdated != 1 || string(fsys.MapFS["Rules.md"].Data) != dr.Files[0].New {
+		t.Fatalf("Sync should write what Diff previewed: %+v\n%s", sr, fsys.MapFS["Rules.md"].Data)
+	}
+	if vr, err := Verify(ctx, opts("v2")...); err != nil || !vr.OK {
+		t.Fatalf("Verify after sync: %+v, %v", vr, err)
+	}
+
+	fsys.MapFS["Rules.md"].Data = []byte(strings.Replace(dr.Files[0].New, "PROFILE v2", "edited", 1))
+	vr, err := Verify(ctx, opts("v2")...)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if vr.OK || len(vr.Issues) != 1 || vr.Issues[0].Document != "Rules.md" || !strings.Contains(vr.Issues[0].String(), "Rules.md: ") {
+		t.Fatalf("expected edited block to fail verification, got %+v", vr)
+	}
+}
+
+func TestOptions_Validation(t *testing.T) {
+	ctx := context.Background()
+	if _, err := Sync(ctx); err == nil || !strings.Contains(err.Error(), "WithSource") {
+		t.Fatalf("expected missing source error, got %v", err)
+	}
+	_, err := Verify(ctx, WithSource("r", "v1", "p"), WithHashNormalization("fuzzy"))
+	if err == nil || !strings.Contains(err.Error(), "fuzzy") {
+		t.Fatalf("expected hash normalization error, got %v", err)
+	}
+}
+
+func TestFileDiff_Unified(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	d := FileDiff{Path: "x.md", Old: old, New: strings.Replace(strings.Replace(old, "b\n", "B\n", 1), "k\n", "", 1)}
+	want := "--- a/x.md\n+++ b/x.md\n" +
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+		"@@ -8,5 +8,4 @@\n h\n i\n j\n-k\n l\n"
+	if got := d.Unified(); got != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	created := FileDiff{Path: "new.md", New: "x\n"}
+	if got := created.Unified(); got != "--- a/new.md\n+++ b/new.md\n@@ -0,0 +1 @@\n+x\n" {
+		t.Fatalf("unexpected diff for new file:\n%s", got)
+	}
+}
+
+func TestLoadListAndValidateProfiles(t *testing.T) {
+	ctx := context.Background()
+	src := t.TempDir()
+	writeSource(t, src, "PROFILE\n")
+	opts := []Option{WithCacheDir(filepath.Join(t.TempDir(), "cache")), WithGit(fakeGit{"v1": src})}
+
+	m, co, err := LoadProfile(ctx, append(opts, WithSource("gov.git", "v1", "svc"))...)
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	if m.ID != "svc" || len(m.Documents) != 1 || co.Commit != "commit-v1" {
+		t.Fatalf("unexpected profile: %+v %+v", m, co)
+	}
+
+	list, co, err := ListProfiles(ctx, append(opts, WithSource("gov.git", "v1", ""))...)
+	if err != nil {
+		t.Fatalf("ListProfiles: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != "svc" || list[0].Err != nil || co.Dir != src {
+		t.Fatalf("unexpected listing: %+v %+v", list, co)
+	}
+
+	if err := os.Remove(filepath.Join(src, "Governance", "Profiles", "svc", "Rules.Profile.md")); err != nil {
+		t.Fatal(err)
+	}
+	res, err := ValidateProfiles(ctx, Checkout{Dir: src}, nil, opts...)
+	if err != nil {
+		t.Fatalf("ValidateProfiles: %v", err)
+	}
+	if strings.Join(res.IDs, ",") != "svc" || len(res.Problems) != 1 || !strings.Contains(res.Problems[0].String(), "missing Governance/Profiles/svc/Rules.Profile.md") {
+		t.Fatalf("unexpected validation: %+v", res)
+	}
+}
+
+func TestRepair_ProposesAndApplies(t *testing.T) {
+	ctx := context.Background()
+	src := t.TempDir()
+	writeSource(t, src, "PROFILE\n")
+	fsys := memFS{fstest.MapFS{}}
+	opts := []Option{
+		WithSource("gov.git", "v1", "svc"),
+		WithCacheDir(filepath.Join(t.TempDir(), "cache")),
+		WithFS(fsys),
+		WithGit(fakeGit{"v1": src}),
+	}
+	if _, err := Init(ctx, opts...); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	var kept []string
+	for _, line := range strings.SplitAfter(string(fsys.MapFS["Rules.md"].Data), "\n") {
+		if !strings.Contains(line, "GOV:END") {
+			kept = append(kept, line)
+		}
+	}
+	fsys.MapFS["Rules.md"].Data = []byte(strings.Join(kept, ""))
+
+	res, err := Repair(ctx, opts...)
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+	if len(res.Files) != 1 || len(res.Files[0].Fixes) != 1 || !strings.Contains(res.Files[0].Content, "GOV:END") {
+		t.Fatalf("unexpected repair: %+v", res)
+	}
+	if err := ApplyRepair(res.Files[0]); err != nil {
+		t.Fatalf("ApplyRepair: %v", err)
+	}
+	if vr, err := Verify(ctx, opts...); err != nil || !vr.OK {
+		t.Fatalf("Verify after repair: %+v, %v", vr, err)
+	}
+}
@@ -0,0 +1,150 @@
+package govkit
+
+import (
+	"context"
+	"io"
+	"io/fs"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/builder"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/source"
+)
+
+// FS is the filesystem governed files are read from and written to. Names are
+// slash-separated and relative to the repository root.
+type FS interface {
+	fs.ReadFileFS
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(name string, perm fs.FileMode) error
+}
+
+// DirFS returns an FS for the directory root on disk. It is the default, rooted at the
+// repository root (WithRepoRoot).
+func DirFS(root string) FS { return builder.DirFS(root) }
+
+// NewMemFS returns an empty in-memory FS, e.g. to preview Init or Build output. Read it
+// back with ReadFile or fs.WalkDir.
+func NewMemFS() FS { return builder.NewMemFS() }
+
+// ArchiveFormat is the container of a Bundle.
+type ArchiveFormat = builder.ArchiveFormat
+
+const (
+	ArchiveTarGz = builder.ArchiveTarGz
+	ArchiveZip   = builder.ArchiveZip
+)
+
+// ArchiveFormatFor returns the format implied by an output name's extension
+// (.tar.gz, .tgz or .zip).
+func ArchiveFormatFor(name string) (ArchiveFormat, bool) { return builder.ArchiveFormatFor(name) }
+
+// Bundle is an FS that Build writes into and that, on Close, writes a reproducible
+// archive: entries sorted by name, a fixed mtime and mode, and a manifest
+// (agent-gov-bundle.json) listing each Record-ed profile and the sha256 of every file.
+type Bundle = builder.Bundle
+
+// BundleProfile is the provenance of one Build in a Bundle.
+type BundleProfile = builder.BundleProfile
+
+// NewBundle returns a Bundle that writes format to w when closed.
+func NewBundle(w io.Writer, format ArchiveFormat) *Bundle { return builder.NewBundle(w, format) }
+
+// Checkout is a governance source repository checked out at a commit.
+type Checkout struct {
+	// Dir is the local directory containing the checkout (with Governance/Profiles).
+	Dir    string
+	Repo   string
+	Ref    string
+	Commit string
+	// FS, if set, serves the checkout's files (names relative to Dir) instead of the
+	// files on disk: profile manifests, fragments and templates.
+	FS fs.FS
+}
+
+// Git fetches governance sources. Cross-repository profile bases are fetched with it too.
+type Git interface {
+	// Fetch checks out repo at ref, caching below cacheDir if the implementation caches.
+	Fetch(ctx context.Context, repo, ref, cacheDir string) (Checkout, error)
+}
+
+// CLIGit returns the default Git, which runs the git executable and keeps one checkout
+// per repository and ref in the cache dir.
+func CLIGit() Git { return cliGit{} }
+
+type cliGit struct{}
+
+func (cliGit) Fetch(ctx context.Context, repo, ref, cacheDir string) (Checkout, error) {
+	src, err := source.Fetch(ctx, source.FetchOptions{RepoURL: repo, Ref: ref, CacheDir: cacheDir})
+	if err != nil {
+		return Checkout{}, err
+	}
+	return Checkout{Dir: src.CheckoutDir, Repo: src.SourceRepo, Ref: src.SourceRef, Commit: src.SourceCommit}, nil
+}
+
+// Option configures an operation.
+type Option func(*options)
+
+type options struct {
+	repoRoot string
+	docsRoot string
+	cacheDir string
+
+	repo      string
+	ref       string
+	profileID string
+
+	markerPrefix   string
+	addendaHeading string
+	hash           string
+
+	variables  map[string]any
+	parameters map[string]any
+	facts      map[string]bool
+
+	fsys FS
+	git  Git
+}
+
+// WithSource selects the governance source repository, ref and profile. Required.
+func WithSource(repo, ref, profileID string) Option {
+	return func(o *options) { o.repo, o.ref, o.profileID = repo, ref, profileID }
+}
+
+// WithRepoRoot sets the target repository directory (default "."). It is ignored for
+// reads and writes when WithFS is given.
+func WithRepoRoot(dir string) Option { return func(o *options) { o.repoRoot = dir } }
+
+// WithDocsRoot sets where documents go inside the repository (default ".").
+func WithDocsRoot(dir string) Option { return func(o *options) { o.docsRoot = dir } }
+
+// WithCacheDir sets where sources are cached (default: the user cache dir).
+func WithCacheDir(dir string) Option { return func(o *options) { o.cacheDir = dir } }
+
+// WithMarkerPrefix sets the managed-block marker namespace (default "GOV").
+func WithMarkerPrefix(prefix string) Option { return func(o *options) { o.markerPrefix = prefix } }
+
+// WithAddendaHeading sets the heading of the project-owned section Init adds to Markdown
+// documents (default "Local Addenda (project-owned)").
+func WithAddendaHeading(heading string) Option {
+	return func(o *options) { o.addendaHeading = heading }
+}
+
+// WithHashNormalization sets how block content is hashed: "exact" (default),
+// "normalize-eol" or "normalize-whitespace".
+func WithHashNormalization(mode string) Option { return func(o *options) { o.hash = mode } }
+
+// WithVariables overrides the profile's fragment variables.
+func WithVariables(vars map[string]any) Option { return func(o *options) { o.variables = vars } }
+
+// WithParameters sets values for the profile's typed parameters.
+func WithParameters(params map[string]any) Option {
+	return func(o *options) { o.parameters = params }
+}
+
+// WithFacts sets the facts `when` conditions are evaluated against (e.g. {"go": true}).
+func WithFacts(facts map[string]bool) Option { return func(o *options) { o.facts = facts } }
+
+// WithFS reads and writes governed files through fsys instead of the repository on disk.
+func WithFS(fsys FS) Option { return func(o *options) { o.fsys = fsys } }
+
+// WithGit fetches governance sources through git instead of the git executable.
+func WithGit(git Git) Option { return func(o *options) { o.git = git } }
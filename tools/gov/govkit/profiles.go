@@ -0,0 +1,89 @@
+package govkit
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/builder"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/profile"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/source"
+)
+
+// Manifest is a resolved profile: its documents, templates, playbooks, parameters,
+// gates and coverage policy, merged over everything it extends.
+type Manifest = profile.Manifest
+
+// ProfileProblem is one issue ValidateProfiles found in a profile.
+type ProfileProblem = profile.Problem
+
+// ProfileValidation reports the profiles ValidateProfiles checked (IDs) and the Problems
+// it found.
+type ProfileValidation = builder.ProfileValidation
+
+// ProfileListing is one profile of a governance source: its manifest, or the error that
+// kept it from loading.
+type ProfileListing = builder.ProfileListing
+
+// LoadProfile fetches the governance source and resolves the WithSource profile. Only
+// WithSource, WithCacheDir and WithGit are used.
+func LoadProfile(ctx context.Context, opts ...Option) (Manifest, Checkout, error) {
+	o, err := resolve(opts)
+	if err != nil {
+		return Manifest{}, Checkout{}, err
+	}
+	m, src, err := builder.LoadProfile(ctx, o.profileOptions())
+	if err != nil {
+		return Manifest{}, Checkout{}, err
+	}
+	return m, checkoutOf(src), nil
+}
+
+// ListProfiles fetches the governance source and loads every profile in it, sorted by ID.
+// The WithSource profile may be empty; only WithSource, WithCacheDir and WithGit are used.
+func ListProfiles(ctx context.Context, opts ...Option) ([]ProfileListing, Checkout, error) {
+	o, err := resolveDefaults(opts)
+	if err != nil {
+		return nil, Checkout{}, err
+	}
+	if strings.TrimSpace(o.repo) == "" || strings.TrimSpace(o.ref) == "" {
+		return nil, Checkout{}, errors.New("govkit: WithSource(repo, ref, profile) is required")
+	}
+	list, src, err := builder.ListProfiles(ctx, o.profileOptions())
+	if err != nil {
+		return nil, Checkout{}, err
+	}
+	return list, checkoutOf(src), nil
+}
+
+// ValidateProfiles checks profiles of a governance source that is already checked out
+// (src.Dir, read through src.FS if set) for missing and escaping files. With no ids every
+// profile is checked, and files under the shared directories that no profile references
+// are reported too. Cross-repository extends are fetched with WithGit into WithCacheDir.
+func ValidateProfiles(ctx context.Context, src Checkout, ids []string, opts ...Option) (ProfileValidation, error) {
+	o, err := resolveDefaults(opts)
+	if err != nil {
+		return ProfileValidation{}, err
+	}
+	return builder.ValidateProfiles(ctx, source.ResolvedSource{
+		CheckoutDir:  src.Dir,
+		SourceRepo:   src.Repo,
+		SourceRef:    src.Ref,
+		SourceCommit: src.Commit,
+		FS:           src.FS,
+	}, ids, o.profileOptions())
+}
+
+func checkoutOf(src source.ResolvedSource) Checkout {
+	return Checkout{Dir: src.CheckoutDir, Repo: src.SourceRepo, Ref: src.SourceRef, Commit: src.SourceCommit, FS: src.FS}
+}
+
+func (o options) profileOptions() builder.ProfileOptions {
+	return builder.ProfileOptions{
+		CacheDir:   o.cacheDir,
+		SourceRepo: o.repo,
+		SourceRef:  o.ref,
+		ProfileID:  o.profileID,
+		Fetch:      o.fetcher(),
+	}
+}
@@ -0,0 +1,45 @@
+package govkit
+
+import (
+	"context"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/builder"
+)
+
+// FileRepair is the proposed fix for one governed document: the Fixes made, the Problems
+// that could not be fixed, and the repaired Content.
+type FileRepair = builder.FileRepair
+
+// RepairResult lists the documents with fixes or problems; intact documents are omitted.
+type RepairResult = builder.RepairResult
+
+// Repair locates damaged managed-block markers (a missing BEGIN or END, duplicates) in the
+// governed documents and proposes fixed documents, locating blocks with a missing marker
+// by their upstream content. Nothing is written; see ApplyRepair.
+func Repair(ctx context.Context, opts ...Option) (RepairResult, error) {
+	o, err := resolve(opts)
+	if err != nil {
+		return RepairResult{}, err
+	}
+	so := o.syncOptions()
+	return builder.Repair(ctx, builder.RepairOptions{
+		RepoRoot:          so.RepoRoot,
+		DocsRoot:          so.DocsRoot,
+		FS:                so.FS,
+		CacheDir:          so.CacheDir,
+		SourceRepo:        so.SourceRepo,
+		SourceRef:         so.SourceRef,
+		ProfileID:         so.ProfileID,
+		Fetch:             so.Fetch,
+		MarkerPrefix:      so.MarkerPrefix,
+		HashNormalization: so.HashNormalization,
+		Variables:         so.Variables,
+		Facts:             so.Facts,
+		Parameters:        so.Parameters,
+	})
+}
+
+// ApplyRepair writes a proposed repair back to the filesystem Repair read it from. Like
+// Sync it stages the file and renames it into place, keeping its mode; it returns
+// ErrInterruptedSync while an interrupted sync awaits ResumeSync or RevertSync.
+func ApplyRepair(fr FileRepair) error { return builder.ApplyRepair(fr) }
@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/profile"
+)
+
+// ErrOverlappingOutputs is returned (wrapped) when more than one application would write
+// the same file.
+var ErrOverlappingOutputs = errors.New("overlapping outputs")
+
+// Application is one profile applied to a docs root of the target repo.
+type Application struct {
+	Name     string
+	DocsRoot string
+	// Manifest is the application's loaded profile.
+	Manifest profile.Manifest
+}
+
+// CheckApplicationOutputs reports outputs that more than one application would write.
+// Conditional templates and playbooks count even when their condition is currently false,
+// so toggling a feature cannot introduce an overlap.
+func CheckApplicationOutputs(apps []Application) error {
+	owner := map[string]int{}
+	var overlaps []string
+	for i, app := range apps {
+		m := app.Manifest
+		outputs := make([]string, 0, len(m.Documents)+len(m.Templates)+len(m.Playbooks))
+		for _, d := range m.Documents {
+			outputs = append(outputs, d.Output)
+		}
+		for _, f := range append(m.Templates, m.Playbooks...) {
+			outputs = append(outputs, f.Output)
+		}
+		docsRoot := app.DocsRoot
+		if strings.TrimSpace(docsRoot) == "" {
+			docsRoot = "."
+		}
+		for _, out := range outputs {
+			key := filepath.ToSlash(filepath.Clean(filepath.Join(docsRoot, out)))
+			if prev, ok := owner[key]; ok && prev != i {
+				overlaps = append(overlaps, fmt.Sprintf("%s is written by both %s and %s", key, apps[prev].Name, app.Name))
+				continue
+			}
+			owner[key] = i
+		}
+	}
+	if len(overlaps) > 0 {
+		return fmt.Errorf("%w: %s", ErrOverlappingOutputs, strings.Join(overlaps, "; "))
+	}
+	return nil
+}
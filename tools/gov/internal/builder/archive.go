@@ -0,0 +1,110 @@
+package builder
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ArchiveFormat selects the container ArchiveFS writes.
+type ArchiveFormat string
+
+const (
+	ArchiveTarGz ArchiveFormat = "tar.gz"
+	ArchiveZip   ArchiveFormat = "zip"
+)
+
+// ArchiveFormatFor returns the format implied by an output name's extension
+// (.tar.gz, .tgz or .zip).
+func ArchiveFormatFor(name string) (ArchiveFormat, bool) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return ArchiveTarGz, true
+	case strings.HasSuffix(lower, ".zip"):
+		return ArchiveZip, true
+	}
+	return "", false
+}
+
+// archiveModTime is the modification time of every archive entry; zip cannot represent
+// times before 1980.
+var archiveModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ArchiveFS is an FS that collects the written files and, on Close, writes them to w as
+// an archive. Entries are sorted by name and carry a fixed mtime and mode 0644, so the
+// same files always produce the same bytes.
+type ArchiveFS struct {
+	*MemFS
+	w      io.Writer
+	format ArchiveFormat
+}
+
+// NewArchiveFS returns an ArchiveFS writing format to w.
+func NewArchiveFS(w io.Writer, format ArchiveFormat) *ArchiveFS {
+	return &ArchiveFS{MemFS: NewMemFS(), w: w, format: format}
+}
+
+// Close writes the archive. It does not close the underlying writer.
+func (a *ArchiveFS) Close() error {
+	switch a.format {
+	case ArchiveTarGz:
+		return a.writeTarGz()
+	case ArchiveZip:
+		return a.writeZip()
+	}
+	return fmt.Errorf("unsupported archive format %q", a.format)
+}
+
+func (a *ArchiveFS) writeTarGz() error {
+	gz := gzip.NewWriter(a.w)
+	tw := tar.NewWriter(gz)
+	for _, name := range a.Names() {
+		data, err := a.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(data)),
+			ModTime:  archiveModTime,
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func (a *ArchiveFS) writeZip() error {
+	zw := zip.NewWriter(a.w)
+	for _, name := range a.Names() {
+		data, err := a.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		hdr := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: archiveModTime}
+		hdr.SetMode(0o644)
+		f, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
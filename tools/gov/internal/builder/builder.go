@@ -2,21 +2,28 @@ package builder
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
 	"path/filepath"
 	"strings"
 
-	"agent-governance-strategy/tools/gov/internal/managedblocks"
-	"agent-governance-strategy/tools/gov/internal/profile"
-	"agent-governance-strategy/tools/gov/internal/source"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/conditions"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/managedblocks"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/profile"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/render"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/source"
 )
 
 type BuildOptions struct {
 	OutDir   string
 	DocsRoot string
+	// FS receives the output; nil writes below OutDir on disk.
+	FS FS
 
 	CacheDir string
+	// Fetch resolves the governance source; nil uses source.Fetch (the git CLI).
+	Fetch source.Fetcher
 
 	SourceRepo string
 	SourceRef  string
@@ -24,6 +31,15 @@ type BuildOptions struct {
 
 	MarkerPrefix   string
 	AddendaHeading string
+	// HashNormalization is how block content is canonicalised before hashing (default exact).
+	HashNormalization managedblocks.Normalization
+
+	// Variables override the profile's default fragment variables.
+	Variables map[string]any
+	// Facts evaluate fragment and file `when` conditions.
+	Facts conditions.Facts
+	// Parameters are values for the profile's typed parameters schema.
+	Parameters map[string]any
 }
 
 type BuildResult struct {
@@ -33,7 +49,7 @@ type BuildResult struct {
 }
 
 func Build(ctx context.Context, opts BuildOptions) (BuildResult, error) {
-	if strings.TrimSpace(opts.OutDir) == "" {
+	if strings.TrimSpace(opts.OutDir) == "" && opts.FS == nil {
 		return BuildResult{}, fmt.Errorf("out dir is required")
 	}
 	if strings.TrimSpace(opts.DocsRoot) == "" {
@@ -46,62 +62,98 @@ func Build(ctx context.Context, opts BuildOptions) (BuildResult, error) {
 		opts.AddendaHeading = "Local Addenda (project-owned)"
 	}
 
-	src, err := source.Fetch(ctx, source.FetchOptions{
-		RepoURL:  opts.SourceRepo,
-		Ref:      opts.SourceRef,
-		CacheDir: opts.CacheDir,
+	m, src, err := LoadProfile(ctx, ProfileOptions{
+		CacheDir:   opts.CacheDir,
+		SourceRepo: opts.SourceRepo,
+		SourceRef:  opts.SourceRef,
+		ProfileID:  opts.ProfileID,
+		Fetch:      opts.Fetch,
 	})
 	if err != nil {
 		return BuildResult{}, err
 	}
 
-	manifestPath := filepath.Join(src.CheckoutDir, "Governance", "Profiles", opts.ProfileID, "profile.yaml")
-	m, err := profile.LoadManifest(manifestPath)
-	if err != nil {
+	fsys := targetFS(opts.FS, opts.OutDir)
+	if err := fsys.MkdirAll(targetName(opts.DocsRoot, "."), 0o755); err != nil {
 		return BuildResult{}, err
 	}
 
-	baseOut := filepath.Clean(filepath.Join(opts.OutDir, opts.DocsRoot))
-	if err := os.MkdirAll(baseOut, 0o755); err != nil {
+	rc, err := newRenderContext(m, src, opts.Variables, opts.Parameters, opts.Facts)
+	if err != nil {
 		return BuildResult{}, err
 	}
 
 	var res BuildResult
 	for _, doc := range m.Documents {
-		content, err := assembleFragments(doc.Fragments)
-		if err != nil {
-			return BuildResult{}, fmt.Errorf("assemble %s: %w", doc.Output, err)
-		}
-		blockID := managedBlockIDForDoc(doc.Output)
-		meta := map[string]string{
-			"id":           blockID,
-			"version":      opts.SourceRef,
-			"sourceRepo":   src.SourceRepo,
-			"sourceRef":    src.SourceRef,
-			"sourceCommit": src.SourceCommit,
-			"sha256":       managedblocks.SHA256Hex(content),
-		}
-
-		outDoc := strings.Join([]string{
-			"<!--",
-			"Generated by agent-gov. Edits inside managed blocks may be overwritten by sync.",
-			"Local addenda below is project-owned and will not be overwritten.",
-			"-->",
-			managedblocks.FormatBeginMarker(opts.MarkerPrefix, meta),
-			content,
-			managedblocks.FormatEndMarker(opts.MarkerPrefix, blockID),
-			"",
-			"## " + opts.AddendaHeading,
-			"",
-			"<!-- Project-owned notes, exceptions, and platform-specific adaptations go here. -->",
-			"",
-		}, "\n")
-
-		outPath := filepath.Join(baseOut, doc.Output)
-		if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
-			return BuildResult{}, err
+		outPath := targetName(opts.DocsRoot, doc.Output)
+		syntax, markdown := doc.CommentSyntax(), isMarkdown(doc.Output)
+		var existing []byte
+		if !markdown {
+			// Config, markup and source files stay project-owned outside the blocks: insert or
+			// update the blocks in an existing file rather than overwriting it.
+			existing, err = fsys.ReadFile(outPath)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return BuildResult{}, err
+			}
+		}
+		outDoc := string(existing)
+		var blockLines []string
+		for _, sec := range doc.Blocks() {
+			a, err := rc.assemble(sec.Fragments)
+			if err != nil {
+				return BuildResult{}, fmt.Errorf("assemble %s: %w", sectionLabel(doc.Output, sec.Name), err)
+			}
+			blockID := sectionBlockID(doc.Output, sec.Name)
+			meta := a.meta()
+			meta["version"] = opts.SourceRef
+			meta["sourceRepo"] = src.SourceRepo
+			meta["sourceRef"] = src.SourceRef
+			meta["sourceCommit"] = src.SourceCommit
+
+			if !markdown {
+				outDoc, err = managedblocks.UpsertBlock(outDoc, syntax, managedblocks.ReplaceOptions{
+					Prefix:        opts.MarkerPrefix,
+					BlockID:       blockID,
+					NewContent:    a.Content,
+					MetaUpdates:   meta,
+					Normalization: opts.HashNormalization,
+					Placement:     doc.BlockPlacement(),
+				})
+				if err != nil {
+					return BuildResult{}, fmt.Errorf("update %s: %w", outPath, err)
+				}
+				continue
+			}
+			meta["id"] = blockID
+			meta[managedblocks.HashMetaKey] = opts.HashNormalization.Tag()
+			meta["sha256"] = managedblocks.ContentHash(a.Content, opts.HashNormalization)
+			if len(blockLines) > 0 {
+				blockLines = append(blockLines, "")
+			}
+			blockLines = append(blockLines,
+				managedblocks.FormatBeginMarker(opts.MarkerPrefix, meta),
+				a.Content,
+				managedblocks.FormatEndMarker(opts.MarkerPrefix, blockID),
+			)
+		}
+		if markdown {
+			lines := []string{
+				"<!--",
+				"Generated by agent-gov. Edits inside managed blocks may be overwritten by sync.",
+				"Local addenda below is project-owned and will not be overwritten.",
+				"-->",
+			}
+			lines = append(lines, blockLines...)
+			lines = append(lines,
+				"",
+				"## "+opts.AddendaHeading,
+				"",
+				"<!-- Project-owned notes, exceptions, and platform-specific adaptations go here. -->",
+				"",
+			)
+			outDoc = strings.Join(lines, "\n")
 		}
-		if err := os.WriteFile(outPath, []byte(outDoc), 0o644); err != nil {
+		if err := writeTarget(fsys, outPath, []byte(outDoc)); err != nil {
 			return BuildResult{}, err
 		}
 		res.DocsWritten++
@@ -109,15 +161,18 @@ func Build(ctx context.Context, opts BuildOptions) (BuildResult, error) {
 
 	// Templates and playbooks are extra files.
 	for _, t := range append(m.Templates, m.Playbooks...) {
-		b, err := os.ReadFile(t.Source)
+		include, err := conditions.Eval(t.When, opts.Facts)
 		if err != nil {
-			return BuildResult{}, fmt.Errorf("read %s: %w", t.Source, err)
+			return BuildResult{}, fmt.Errorf("%s: %w", t.Output, err)
 		}
-		outPath := filepath.Join(baseOut, t.Output)
-		if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
-			return BuildResult{}, err
+		if !include {
+			continue
+		}
+		b, err := rc.files.ReadFile(t.Source)
+		if err != nil {
+			return BuildResult{}, fmt.Errorf("read %s: %w", t.Source, err)
 		}
-		if err := os.WriteFile(outPath, b, 0o644); err != nil {
+		if err := writeTarget(fsys, targetName(opts.DocsRoot, t.Output), b); err != nil {
 			return BuildResult{}, err
 		}
 		res.ExtraFilesWritten++
@@ -127,21 +182,222 @@ func Build(ctx context.Context, opts BuildOptions) (BuildResult, error) {
 	return res, nil
 }
 
-func assembleFragments(fragmentPaths []string) (string, error) {
-	var parts []string
-	for _, p := range fragmentPaths {
-		b, err := os.ReadFile(p)
+type ProfileOptions struct {
+	CacheDir string
+
+	SourceRepo string
+	SourceRef  string
+	ProfileID  string
+
+	// Fetch resolves sources, including cross-repository bases; nil uses source.Fetch.
+	Fetch source.Fetcher
+}
+
+// LoadProfile fetches the governance source and loads the resolved profile manifest.
+func LoadProfile(ctx context.Context, opts ProfileOptions) (profile.Manifest, source.ResolvedSource, error) {
+	fetch := opts.Fetch
+	if fetch == nil {
+		fetch = source.Fetch
+	}
+	src, err := fetch(ctx, source.FetchOptions{
+		RepoURL:  opts.SourceRepo,
+		Ref:      opts.SourceRef,
+		CacheDir: opts.CacheDir,
+	})
+	if err != nil {
+		return profile.Manifest{}, source.ResolvedSource{}, err
+	}
+	m, err := profile.LoadManifestWith(profile.ManifestPath(src.CheckoutDir, opts.ProfileID), profile.LoadOptions{
+		Resolve: fetchResolver(ctx, opts.CacheDir, fetch),
+		Root:    src.CheckoutDir,
+		FS:      src.FS,
+	})
+	if err != nil {
+		return profile.Manifest{}, source.ResolvedSource{}, err
+	}
+	return m, src, nil
+}
+
+// FetchResolver resolves cross-repository extends by fetching them into cacheDir.
+func FetchResolver(ctx context.Context, cacheDir string) profile.Resolver {
+	return fetchResolver(ctx, cacheDir, source.Fetch)
+}
+
+func fetchResolver(ctx context.Context, cacheDir string, fetch source.Fetcher) profile.Resolver {
+	return func(repo, ref string) (profile.Source, error) {
+		src, err := fetch(ctx, source.FetchOptions{RepoURL: repo, Ref: ref, CacheDir: cacheDir})
+		if err != nil {
+			return profile.Source{}, err
+		}
+		return profile.Source{Repo: src.SourceRepo, Ref: src.SourceRef, Commit: src.SourceCommit, Dir: src.CheckoutDir, FS: src.FS}, nil
+	}
+}
+
+// recordSources formats the cross-repository bases as `repo@commit,...` for block metadata.
+func recordSources(sources []profile.Source) string {
+	parts := make([]string, 0, len(sources))
+	for _, s := range sources {
+		parts = append(parts, s.Repo+"@"+s.Commit)
+	}
+	return strings.Join(parts, ",")
+}
+
+// isMarkdown reports whether output is a Markdown document, which Build writes whole with
+// a banner and an addenda section; every other file only has its blocks upserted.
+func isMarkdown(output string) bool {
+	switch strings.ToLower(filepath.Ext(output)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// renderContext holds what a document's managed content depends on besides its fragments.
+type renderContext struct {
+	vars  map[string]any
+	facts conditions.Facts
+	// sourceRoot is the governance checkout; recorded fragment paths are relative to it.
+	sourceRoot string
+	// files reads fragments and templates from the checkout and the profile's bases.
+	files sourceFiles
+	// baseSources records the cross-repository bases the profile was resolved from.
+	baseSources string
+}
+
+// newRenderContext merges config variables over the profile defaults and exposes the
+// validated parameters as `.Params`.
+func newRenderContext(m profile.Manifest, src source.ResolvedSource, variables, parameters map[string]any, facts conditions.Facts) (renderContext, error) {
+	params, err := m.ResolveParameters(parameters)
+	if err != nil {
+		return renderContext{}, err
+	}
+	vars := render.Merge(m.Variables, variables)
+	if len(params) > 0 {
+		vars = render.Merge(vars, map[string]any{"Params": params})
+	}
+	return renderContext{
+		vars:        vars,
+		facts:       facts,
+		sourceRoot:  src.CheckoutDir,
+		files:       newSourceFiles(src, m.Sources),
+		baseSources: recordSources(m.Sources),
+	}, nil
+}
+
+// assembled is a document's rendered managed-block content.
+type assembled struct {
+	Content string
+	// Vars is the digest of the variables the included fragments reference, set when any
+	// of them used placeholders.
+	Vars string
+	// Conditions records the evaluated facts referenced by fragment conditions (empty when none).
+	Conditions string
+	// Fragments lists the included fragments (relative to the source root), set when the
+	// document uses glob or directory includes.
+	Fragments string
+	// BaseSources lists `repo@commit` for each cross-repository base (empty when none).
+	BaseSources string
+}
+
+// meta returns the BEGIN marker fields that make the rendering reproducible; empty values
+// clear fields left over from a previous sync.
+func (a assembled) meta() map[string]string {
+	return map[string]string{"vars": a.Vars, "when": a.Conditions, "fragments": a.Fragments, "baseSources": a.BaseSources}
+}
+
+// assemble renders and joins the fragments whose conditions hold.
+func (rc renderContext) assemble(fragments []profile.Fragment) (assembled, error) {
+	a := assembled{BaseSources: rc.baseSources}
+	var parts, names, included []string
+	var used map[string]any
+	expanded, templated := false, false
+	for _, f := range fragments {
+		include, err := conditions.Eval(f.When, rc.facts)
 		if err != nil {
-			return "", err
+			return assembled{}, fmt.Errorf("%s: %w", f.Path, err)
+		}
+		n, _ := conditions.Names(f.When)
+		names = append(names, n...)
+		if f.From != "" {
+			expanded = true
+		}
+		if !include {
+			continue
 		}
-		parts = append(parts, strings.TrimRight(string(b), "\n"))
+		b, err := rc.files.ReadFile(f.Path)
+		if err != nil {
+			return assembled{}, err
+		}
+		text := string(b)
+		if f.From != "" {
+			// Included files may carry `order:` frontmatter; it is not part of the content.
+			if _, body, ok := profile.SplitFrontmatter(text); ok {
+				text = strings.TrimLeft(body, "\n")
+			}
+		}
+		if render.IsTemplate(text) {
+			templated = true
+			refs, err := render.Used(filepath.Base(f.Path), text, rc.vars)
+			if err != nil {
+				return assembled{}, fmt.Errorf("%s: %w", f.Path, err)
+			}
+			used = render.Merge(used, refs)
+			if text, err = render.Fragment(filepath.Base(f.Path), text, rc.vars); err != nil {
+				return assembled{}, fmt.Errorf("%s: %w", f.Path, err)
+			}
+		}
+		parts = append(parts, strings.TrimRight(text, "\n"))
+		included = append(included, rc.relToSource(f.Path))
+	}
+	if templated {
+		digest, err := render.Digest(used)
+		if err != nil {
+			return assembled{}, err
+		}
+		a.Vars = digest
+	}
+	a.Content = strings.Join(parts, "\n\n")
+	a.Conditions = conditions.Record(names, rc.facts)
+	if expanded {
+		a.Fragments = strings.Join(included, ",")
+	}
+	return a, nil
+}
+
+func (rc renderContext) relToSource(path string) string {
+	if rc.sourceRoot == "" {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(rc.sourceRoot, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// sectionBlockID returns the block ID of a document section; the unnamed section of a
+// document without sections uses the document's ID.
+func sectionBlockID(output, section string) string {
+	if section == "" {
+		return managedBlockIDForDoc(output)
 	}
-	return strings.Join(parts, "\n\n"), nil
+	return managedBlockIDForDoc(output) + "-" + section
+}
+
+// sectionLabel names a document section in messages, e.g. "Constitution.md#agents".
+func sectionLabel(output, section string) string {
+	if section == "" {
+		return output
+	}
+	return output + "#" + section
 }
 
 func managedBlockIDForDoc(output string) string {
 	base := output
-	base = strings.TrimSuffix(base, filepath.Ext(base))
+	// Dotfiles such as .gitignore are all extension; keep their name.
+	if ext := filepath.Ext(base); ext != filepath.Base(base) {
+		base = strings.TrimSuffix(base, ext)
+	}
 	base = strings.ToLower(base)
 	base = strings.ReplaceAll(base, "_", "-")
 	base = strings.ReplaceAll(base, " ", "-")
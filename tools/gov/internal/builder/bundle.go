@@ -0,0 +1,83 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+)
+
+// BundleManifestName is the manifest's name inside a bundle archive.
+const BundleManifestName = "agent-gov-bundle.json"
+
+// BundleManifest describes a bundle: what it was built from and the sha256 of every
+// other file in it.
+type BundleManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Profiles      []BundleProfile `json:"profiles"`
+	Files         []BundleFile    `json:"files"`
+}
+
+// BundleProfile is the provenance of one profile application in a bundle.
+type BundleProfile struct {
+	// Name is the application name; empty for a single-profile config.
+	Name         string `json:"name,omitempty"`
+	Profile      string `json:"profile"`
+	SourceRepo   string `json:"sourceRepo"`
+	SourceRef    string `json:"sourceRef"`
+	SourceCommit string `json:"sourceCommit"`
+	DocsRoot     string `json:"docsRoot,omitempty"`
+}
+
+type BundleFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// Bundle is an ArchiveFS that adds a BundleManifest when closed. Build into it once per
+// profile application and Record each one.
+type Bundle struct {
+	*ArchiveFS
+	profiles []BundleProfile
+}
+
+// NewBundle returns a Bundle writing format to w.
+func NewBundle(w io.Writer, format ArchiveFormat) *Bundle {
+	return &Bundle{ArchiveFS: NewArchiveFS(w, format)}
+}
+
+// Record adds a profile application to the manifest.
+func (b *Bundle) Record(p BundleProfile) { b.profiles = append(b.profiles, p) }
+
+// Manifest returns the manifest for the files written so far.
+func (b *Bundle) Manifest() (BundleManifest, error) {
+	m := BundleManifest{SchemaVersion: 1, Profiles: append([]BundleProfile{}, b.profiles...), Files: []BundleFile{}}
+	for _, name := range b.Names() {
+		if name == BundleManifestName {
+			continue
+		}
+		data, err := b.ReadFile(name)
+		if err != nil {
+			return BundleManifest{}, err
+		}
+		sum := sha256.Sum256(data)
+		m.Files = append(m.Files, BundleFile{Path: name, SHA256: hex.EncodeToString(sum[:])})
+	}
+	return m, nil
+}
+
+// Close writes the manifest and the archive. It does not close the underlying writer.
+func (b *Bundle) Close() error {
+	m, err := b.Manifest()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := b.WriteFile(BundleManifestName, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return b.ArchiveFS.Close()
+}
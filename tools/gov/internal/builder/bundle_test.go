@@ -0,0 +1,69 @@
+package builder
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+func TestBundle_IsReproducibleWithManifest(t *testing.T) {
+	ctx := context.Background()
+	build := func() []byte {
+		var buf bytes.Buffer
+		b := NewBundle(&buf, ArchiveZip)
+		res, err := Build(ctx, BuildOptions{FS: b, SourceRepo: "mem", SourceRef: "v1", ProfileID: "p", Fetch: memSourceFetch(t)})
+		if err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+		b.Record(BundleProfile{Profile: "p", SourceRepo: "mem", SourceRef: "v1", SourceCommit: res.SourceCommit})
+		if err := b.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		return buf.Bytes()
+	}
+	first := build()
+	if !bytes.Equal(first, build()) {
+		t.Fatalf("expected identical archives from identical builds")
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(first), int64(len(first)))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	contents := map[string][]byte{}
+	var names []string
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[f.Name], _ = io.ReadAll(r)
+		r.Close()
+		names = append(names, f.Name)
+	}
+	if len(names) != 3 || names[0] != "Plans/Template.md" || names[1] != "Rules.md" || names[2] != BundleManifestName {
+		t.Fatalf("unexpected entries: %v", names)
+	}
+
+	var m BundleManifest
+	if err := json.Unmarshal(contents[BundleManifestName], &m); err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	if m.SchemaVersion != 1 || len(m.Profiles) != 1 || m.Profiles[0].SourceCommit != "c1" || m.Profiles[0].Profile != "p" {
+		t.Fatalf("unexpected manifest profiles: %+v", m.Profiles)
+	}
+	if len(m.Files) != 2 {
+		t.Fatalf("unexpected manifest files: %+v", m.Files)
+	}
+	for _, f := range m.Files {
+		sum := sha256.Sum256(contents[f.Path])
+		if f.SHA256 != hex.EncodeToString(sum[:]) {
+			t.Fatalf("%s: manifest hash does not match the archived file", f.Path)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package builder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/profile"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/source"
+)
+
+// FS is the filesystem governed files are read from and written to. Names are
+// slash-separated and relative to its root (the target repo, or the build output dir).
+type FS interface {
+	fs.ReadFileFS
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(name string, perm fs.FileMode) error
+}
+
+// DirFS returns an FS rooted at the OS directory root.
+func DirFS(root string) FS { return dirFS(root) }
+
+type dirFS string
+
+func (d dirFS) path(name string) string {
+	return filepath.Join(string(d), filepath.FromSlash(name))
+}
+
+func (d dirFS) Open(name string) (fs.File, error) { return os.Open(d.path(name)) }
+
+func (d dirFS) ReadFile(name string) ([]byte, error) { return os.ReadFile(d.path(name)) }
+
+func (d dirFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(d.path(name), data, perm)
+}
+
+func (d dirFS) MkdirAll(name string, perm fs.FileMode) error {
+	return os.MkdirAll(d.path(name), perm)
+}
+
+func (d dirFS) Rename(oldname, newname string) error {
+	return os.Rename(d.path(oldname), d.path(newname))
+}
+
+func (d dirFS) Remove(name string) error { return os.Remove(d.path(name)) }
+
+// targetFS returns fsys, or the OS directory root when fsys is nil.
+func targetFS(fsys FS, root string) FS {
+	if fsys != nil {
+		return fsys
+	}
+	return DirFS(root)
+}
+
+// targetName is the FS name of a profile output below docsRoot.
+func targetName(docsRoot, output string) string {
+	return path.Join(filepath.ToSlash(strings.TrimSpace(docsRoot)), output)
+}
+
+// writeTarget creates name's parent directories and writes data.
+func writeTarget(fsys FS, name string, data []byte) error {
+	if err := fsys.MkdirAll(path.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return fsys.WriteFile(name, data, 0o644)
+}
+
+// MemFS is an in-memory FS, e.g. for previews and tests. Directories exist implicitly
+// through the files below them. The zero value is not usable; create one with NewMemFS.
+type MemFS struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// NewMemFS returns an empty MemFS.
+func NewMemFS() *MemFS { return &MemFS{files: map[string][]byte{}} }
+
+// Names returns the names of all files, sorted.
+func (m *MemFS) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte, _ fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[name] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *MemFS) MkdirAll(name string, _ fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return nil
+}
+
+func (m *MemFS) Rename(oldname, newname string) error {
+	if !fs.ValidPath(newname) || newname == "." {
+		return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrInvalid}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[oldname]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: oldname, Err: fs.ErrNotExist}
+	}
+	delete(m.files, oldname)
+	m.files[newname] = data
+	return nil
+}
+
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[name]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+func (m *MemFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if data, ok := m.files[name]; ok {
+		info := memInfo{name: path.Base(name), size: int64(len(data)), mode: 0o644}
+		return &memFile{info: info, Reader: bytes.NewReader(data)}, nil
+	}
+
+	// A directory: list its direct children.
+	prefix := ""
+	if name != "." {
+		prefix = name + "/"
+	}
+	children := map[string]memInfo{}
+	for file, data := range m.files {
+		rest, ok := strings.CutPrefix(file, prefix)
+		if !ok {
+			continue
+		}
+		if child, _, isDir := strings.Cut(rest, "/"); isDir {
+			children[child] = memInfo{name: child, mode: fs.ModeDir | 0o755}
+		} else {
+			children[child] = memInfo{name: child, size: int64(len(data)), mode: 0o644}
+		}
+	}
+	if len(children) == 0 && name != "." {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, info := range children {
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return &memDir{info: memInfo{name: path.Base(name), mode: fs.ModeDir | 0o755}, entries: entries}, nil
+}
+
+type memInfo struct {
+	name string
+	size int64
+	mode fs.FileMode
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) Mode() fs.FileMode  { return i.mode }
+func (i memInfo) ModTime() time.Time { return time.Time{} }
+func (i memInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memInfo) Sys() any           { return nil }
+
+type memFile struct {
+	info memInfo
+	*bytes.Reader
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+type memDir struct {
+	info    memInfo
+	entries []fs.DirEntry
+	off     int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.off:]
+	if n <= 0 {
+		d.off = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	d.off += n
+	return rest[:n], nil
+}
+
+// sourceFiles reads governance source files, named by their paths on disk, through the
+// fs.FS of the checkout (or profile base) that contains them. A path outside every
+// root is an error, never a read from the host disk.
+type sourceFiles []sourceRoot
+
+type sourceRoot struct {
+	dir  string
+	fsys fs.FS
+}
+
+func newSourceFiles(src source.ResolvedSource, bases []profile.Source) sourceFiles {
+	var files sourceFiles
+	if src.CheckoutDir != "" {
+		files = append(files, sourceRoot{dir: src.CheckoutDir, fsys: checkoutFS(src)})
+	}
+	for _, b := range bases {
+		if b.Dir != "" && b.Dir != src.CheckoutDir {
+			fsys := b.FS
+			if fsys == nil {
+				fsys = os.DirFS(b.Dir)
+			}
+			files = append(files, sourceRoot{dir: b.Dir, fsys: fsys})
+		}
+	}
+	// Nested roots (a base cached below the checkout) take precedence.
+	sort.SliceStable(files, func(i, j int) bool { return len(files[i].dir) > len(files[j].dir) })
+	return files
+}
+
+func (s sourceFiles) ReadFile(name string) ([]byte, error) {
+	for _, root := range s {
+		rel, err := filepath.Rel(root.dir, name)
+		if err == nil && filepath.IsLocal(rel) {
+			return fs.ReadFile(root.fsys, filepath.ToSlash(rel))
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: errOutsideSource}
+}
+
+var errOutsideSource = errors.New("outside the governance source")
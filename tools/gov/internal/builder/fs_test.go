@@ -0,0 +1,148 @@
+package builder
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/source"
+)
+
+func TestMemFS_IsAValidFS(t *testing.T) {
+	m := NewMemFS()
+	for name, data := range map[string]string{"a.md": "A", "docs/b.md": "B", "docs/sub/c.md": "C"} {
+		if err := writeTarget(m, name, []byte(data)); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := fstest.TestFS(m, "a.md", "docs/b.md", "docs/sub/c.md"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile("../escape.md", nil, 0o644); err == nil {
+		t.Fatalf("expected invalid path error")
+	}
+}
+
+// memSourceFetch serves a profile that lives only in memory; its checkout dir does not exist.
+func memSourceFetch(t *testing.T) source.Fetcher {
+	dir := filepath.Join(t.TempDir(), "checkout")
+	files := fstest.MapFS{
+		"Governance/Profiles/p/profile.yaml": {Data: []byte(strings.TrimSpace(`
+schemaVersion: 1
+id: p
+documents:
+  - output: Rules.md
+    fragments:
+      - ./Rules.md
+templates:
+  - source: ./tpl/Plan.md
+    output: Plans/Template.md
+`) + "\n")},
+		"Governance/Profiles/p/Rules.md":    {Data: []byte("RULES from memory\n")},
+		"Governance/Profiles/p/tpl/Plan.md": {Data: []byte("PLAN\n")},
+	}
+	return func(_ context.Context, opts source.FetchOptions) (source.ResolvedSource, error) {
+		return source.ResolvedSource{CheckoutDir: dir, SourceRepo: opts.RepoURL, SourceRef: opts.Ref, SourceCommit: "c1", FS: files}, nil
+	}
+}
+
+func TestBuild_IntoMemFSAndArchives(t *testing.T) {
+	ctx := context.Background()
+	opts := BuildOptions{SourceRepo: "mem", SourceRef: "v1", ProfileID: "p", DocsRoot: "gov", Fetch: memSourceFetch(t)}
+
+	m := NewMemFS()
+	opts.FS = m
+	res, err := Build(ctx, opts)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if res.DocsWritten != 1 || res.ExtraFilesWritten != 1 || strings.Join(m.Names(), ",") != "gov/Plans/Template.md,gov/Rules.md" {
+		t.Fatalf("unexpected build: %+v %v", res, m.Names())
+	}
+	doc, _ := m.ReadFile("gov/Rules.md")
+	if !strings.Contains(string(doc), "RULES from memory") {
+		t.Fatalf("fragment not read through the source FS:\n%s", doc)
+	}
+
+	var tgz bytes.Buffer
+	a := NewArchiveFS(&tgz, ArchiveTarGz)
+	opts.FS = a
+	if _, err := Build(ctx, opts); err != nil {
+		t.Fatalf("Build tar.gz: %v", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	gz, err := gzip.NewReader(&tgz)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("tar: %v", err)
+		}
+		if !hdr.ModTime.Equal(archiveModTime) || hdr.Mode != 0o644 {
+			t.Fatalf("unexpected header: %+v", hdr)
+		}
+		data, _ := io.ReadAll(tr)
+		if want, _ := m.ReadFile(hdr.Name); !bytes.Equal(data, want) {
+			t.Fatalf("%s: archive content differs from the MemFS build", hdr.Name)
+		}
+		names = append(names, hdr.Name)
+	}
+	if strings.Join(names, ",") != strings.Join(m.Names(), ",") {
+		t.Fatalf("unexpected tar entries: %v", names)
+	}
+
+	var zbuf bytes.Buffer
+	a = NewArchiveFS(&zbuf, ArchiveZip)
+	opts.FS = a
+	if _, err := Build(ctx, opts); err != nil {
+		t.Fatalf("Build zip: %v", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(zbuf.Bytes()), int64(zbuf.Len()))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	if len(zr.File) != 2 || zr.File[0].Name != "gov/Plans/Template.md" || zr.File[1].Mode() != 0o644 {
+		t.Fatalf("unexpected zip entries: %+v", zr.File)
+	}
+}
+
+func TestArchiveFormatFor(t *testing.T) {
+	for name, want := range map[string]ArchiveFormat{"b.tar.gz": ArchiveTarGz, "B.TGZ": ArchiveTarGz, "b.zip": ArchiveZip, "out": ""} {
+		if got, ok := ArchiveFormatFor(name); got != want || ok != (want != "") {
+			t.Fatalf("ArchiveFormatFor(%q) = %q, %v", name, got, ok)
+		}
+	}
+}
+
+func TestSourceFiles_RejectsPathsOutsideEveryRoot(t *testing.T) {
+	dir := t.TempDir()
+	checkout := filepath.Join(dir, "checkout")
+	writeFile(t, filepath.Join(dir, "host.md"), "HOST\n")
+	files := newSourceFiles(source.ResolvedSource{CheckoutDir: checkout, FS: fstest.MapFS{"a.md": {Data: []byte("A\n")}}}, nil)
+
+	if got, err := files.ReadFile(filepath.Join(checkout, "a.md")); err != nil || string(got) != "A\n" {
+		t.Fatalf("ReadFile in checkout: %q %v", got, err)
+	}
+	if _, err := files.ReadFile(filepath.Join(dir, "host.md")); err == nil || !strings.Contains(err.Error(), "outside the governance source") {
+		t.Fatalf("expected outside-source error, got %v", err)
+	}
+}
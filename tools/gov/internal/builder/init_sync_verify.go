@@ -2,59 +2,88 @@ package builder
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
 	"path/filepath"
 	"strings"
 
-	"agent-governance-strategy/tools/gov/internal/managedblocks"
-	"agent-governance-strategy/tools/gov/internal/profile"
-	"agent-governance-strategy/tools/gov/internal/source"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/conditions"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/managedblocks"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/profile"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/source"
 )
 
 type InitOptions struct {
 	RepoRoot string
 	DocsRoot string
+	// FS is the target repo; nil uses RepoRoot on disk.
+	FS FS
 
 	CacheDir   string
 	SourceRepo string
 	SourceRef  string
 	ProfileID  string
+	// Fetch resolves the governance source; nil uses source.Fetch.
+	Fetch source.Fetcher
 
-	MarkerPrefix   string
-	AddendaHeading string
+	MarkerPrefix      string
+	AddendaHeading    string
+	HashNormalization managedblocks.Normalization
+
+	Variables map[string]any
+	Facts     conditions.Facts
+
+	Parameters map[string]any
 }
 
 type InitResult struct {
 	DocsWritten       int
 	ExtraFilesWritten int
+	SourceCommit      string
 }
 
 func Init(ctx context.Context, opts InitOptions) (InitResult, error) {
 	outDir := filepath.Clean(opts.RepoRoot)
 	res, err := Build(ctx, BuildOptions{
-		OutDir:         outDir,
-		DocsRoot:       opts.DocsRoot,
-		CacheDir:       opts.CacheDir,
-		SourceRepo:     opts.SourceRepo,
-		SourceRef:      opts.SourceRef,
-		ProfileID:      opts.ProfileID,
-		MarkerPrefix:   opts.MarkerPrefix,
-		AddendaHeading: opts.AddendaHeading,
+		OutDir:            outDir,
+		DocsRoot:          opts.DocsRoot,
+		FS:                opts.FS,
+		CacheDir:          opts.CacheDir,
+		SourceRepo:        opts.SourceRepo,
+		SourceRef:         opts.SourceRef,
+		ProfileID:         opts.ProfileID,
+		Fetch:             opts.Fetch,
+		MarkerPrefix:      opts.MarkerPrefix,
+		AddendaHeading:    opts.AddendaHeading,
+		HashNormalization: opts.HashNormalization,
+		Variables:         opts.Variables,
+		Facts:             opts.Facts,
+		Parameters:        opts.Parameters,
 	})
-	return InitResult{DocsWritten: res.DocsWritten, ExtraFilesWritten: res.ExtraFilesWritten}, err
+	return InitResult{DocsWritten: res.DocsWritten, ExtraFilesWritten: res.ExtraFilesWritten, SourceCommit: res.SourceCommit}, err
 }
 
 type SyncOptions struct {
 	RepoRoot string
 	DocsRoot string
+	// FS is the target repo; nil uses RepoRoot on disk.
+	FS FS
 
 	CacheDir   string
 	SourceRepo string
 	SourceRef  string
 	ProfileID  string
+	// Fetch resolves the governance source; nil uses source.Fetch.
+	Fetch source.Fetcher
+
+	MarkerPrefix      string
+	HashNormalization managedblocks.Normalization
 
-	MarkerPrefix string
+	Variables map[string]any
+	Facts     conditions.Facts
+
+	Parameters map[string]any
 }
 
 type SyncResult struct {
@@ -62,9 +91,85 @@ type SyncResult struct {
 }
 
 func Sync(ctx context.Context, opts SyncOptions) (SyncResult, error) {
-	if strings.TrimSpace(opts.RepoRoot) == "" {
-		opts.RepoRoot = "."
+	res, err := SyncApplications(ctx, []SyncOptions{opts})
+	if err != nil {
+		var se *SyncError
+		if errors.As(err, &se) {
+			return SyncResult{}, se.Errs[0]
+		}
+		return SyncResult{}, err
 	}
+	return res[0], nil
+}
+
+// SyncError reports the applications SyncApplications could not render. Errs is indexed
+// like the applications, nil for each one that rendered.
+type SyncError struct {
+	Errs []error
+}
+
+func (e *SyncError) Error() string {
+	var msgs []string
+	for _, err := range e.Errs {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// SyncApplications syncs several profile applications into one repository as a single
+// commit: every application's documents are rendered first, and nothing is written
+// unless all of them render. The applications must share RepoRoot and FS, and must not
+// write the same files (ErrOverlappingOutputs).
+func SyncApplications(ctx context.Context, apps []SyncOptions) ([]SyncResult, error) {
+	if len(apps) == 0 {
+		return nil, nil
+	}
+	root := func(o SyncOptions) string { return filepath.Clean(strings.TrimSpace(o.RepoRoot)) }
+	for _, o := range apps[1:] {
+		if root(o) != root(apps[0]) {
+			return nil, fmt.Errorf("sync applications: repo roots %s and %s differ", apps[0].RepoRoot, o.RepoRoot)
+		}
+	}
+	fsys := targetFS(apps[0].FS, root(apps[0]))
+	if err := checkNoJournal(fsys); err != nil {
+		return nil, err
+	}
+	// Every document is rendered before any is written, so a failure leaves the repo as it was.
+	outputs := map[string][]byte{}
+	results := make([]SyncResult, len(apps))
+	errs := make([]error, len(apps))
+	checked := make([]Application, len(apps))
+	failed := false
+	for i, opts := range apps {
+		opts.FS = fsys
+		out, updated, m, err := renderSync(ctx, opts)
+		if err != nil {
+			errs[i], failed = err, true
+			continue
+		}
+		checked[i] = Application{Name: opts.ProfileID, DocsRoot: opts.DocsRoot, Manifest: m}
+		for name, data := range out {
+			outputs[name] = data
+		}
+		results[i] = SyncResult{DocsUpdated: updated}
+	}
+	if failed {
+		return nil, &SyncError{Errs: errs}
+	}
+	if err := CheckApplicationOutputs(checked); err != nil {
+		return nil, err
+	}
+	if err := commitOutputs(fsys, outputs); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// renderSync renders the updated contents of one application's documents in opts.FS and
+// returns those that changed, with the number of documents synced and the loaded profile.
+func renderSync(ctx context.Context, opts SyncOptions) (map[string][]byte, int, profile.Manifest, error) {
 	if strings.TrimSpace(opts.DocsRoot) == "" {
 		opts.DocsRoot = "."
 	}
@@ -72,73 +177,119 @@ func Sync(ctx context.Context, opts SyncOptions) (SyncResult, error) {
 		opts.MarkerPrefix = "GOV"
 	}
 
-	src, err := source.Fetch(ctx, source.FetchOptions{
-		RepoURL:  opts.SourceRepo,
-		Ref:      opts.SourceRef,
-		CacheDir: opts.CacheDir,
+	m, src, err := LoadProfile(ctx, ProfileOptions{
+		CacheDir:   opts.CacheDir,
+		SourceRepo: opts.SourceRepo,
+		SourceRef:  opts.SourceRef,
+		ProfileID:  opts.ProfileID,
+		Fetch:      opts.Fetch,
 	})
 	if err != nil {
-		return SyncResult{}, err
+		return nil, 0, profile.Manifest{}, err
 	}
-	manifestPath := filepath.Join(src.CheckoutDir, "Governance", "Profiles", opts.ProfileID, "profile.yaml")
-	m, err := profile.LoadManifest(manifestPath)
+
+	rc, err := newRenderContext(m, src, opts.Variables, opts.Parameters, opts.Facts)
 	if err != nil {
-		return SyncResult{}, err
+		return nil, 0, profile.Manifest{}, err
 	}
 
-	targetBase := filepath.Clean(filepath.Join(opts.RepoRoot, opts.DocsRoot))
+	outputs := map[string][]byte{}
 	updated := 0
 	for _, doc := range m.Documents {
-		targetPath := filepath.Join(targetBase, doc.Output)
-		existing, err := os.ReadFile(targetPath)
-		if err != nil {
-			return SyncResult{}, fmt.Errorf("read target doc %s: %w", targetPath, err)
+		targetPath := targetName(opts.DocsRoot, doc.Output)
+		markdown := isMarkdown(doc.Output)
+		existing, err := opts.FS.ReadFile(targetPath)
+		// Like Build, sync adds blocks to non-Markdown files (creating them if need be);
+		// a Markdown document must have been created by init.
+		if err != nil && (markdown || !errors.Is(err, fs.ErrNotExist)) {
+			return nil, 0, profile.Manifest{}, fmt.Errorf("read target doc %s: %w", targetPath, err)
 		}
 
-		newContent, err := assembleFragments(doc.Fragments)
-		if err != nil {
-			return SyncResult{}, fmt.Errorf("assemble %s: %w", doc.Output, err)
+		out := string(existing)
+		syntax := doc.CommentSyntax()
+		prevID := ""
+		for _, sec := range doc.Blocks() {
+			a, err := rc.assemble(sec.Fragments)
+			if err != nil {
+				return nil, 0, profile.Manifest{}, fmt.Errorf("assemble %s: %w", sectionLabel(doc.Output, sec.Name), err)
+			}
+			blockID := sectionBlockID(doc.Output, sec.Name)
+			metaUpdates := a.meta()
+			metaUpdates["version"] = src.SourceRef
+			metaUpdates["sourceRepo"] = src.SourceRepo
+			metaUpdates["sourceRef"] = src.SourceRef
+			metaUpdates["sourceCommit"] = src.SourceCommit
+			replace := managedblocks.ReplaceOptions{
+				Prefix:        opts.MarkerPrefix,
+				BlockID:       blockID,
+				NewContent:    a.Content,
+				MetaUpdates:   metaUpdates,
+				Normalization: opts.HashNormalization,
+				Placement:     doc.BlockPlacement(),
+			}
+			// A section added upstream is inserted after the previous section's block.
+			if _, err := managedblocks.BlockMeta(out, syntax, opts.MarkerPrefix, blockID); errors.Is(err, managedblocks.ErrBlockNotFound) && prevID != "" {
+				out, err = managedblocks.InsertBlockAfter(out, syntax, prevID, replace)
+				if err != nil {
+					return nil, 0, profile.Manifest{}, fmt.Errorf("update %s: %w", targetPath, err)
+				}
+				prevID = blockID
+				continue
+			}
+			if markdown {
+				out, err = managedblocks.ReplaceBlock(out, syntax, replace)
+			} else {
+				out, err = managedblocks.UpsertBlock(out, syntax, replace)
+			}
+			if err != nil {
+				return nil, 0, profile.Manifest{}, fmt.Errorf("update %s: %w", targetPath, err)
+			}
+			prevID = blockID
 		}
-		blockID := managedBlockIDForDoc(doc.Output)
-		out, err := managedblocks.ReplaceBlock(string(existing), managedblocks.ReplaceOptions{
-			Prefix:     opts.MarkerPrefix,
-			BlockID:    blockID,
-			NewContent: newContent,
-			MetaUpdates: map[string]string{
-				"version":      src.SourceRef,
-				"sourceRepo":   src.SourceRepo,
-				"sourceRef":    src.SourceRef,
-				"sourceCommit": src.SourceCommit,
-			},
-		})
-		if err != nil {
-			return SyncResult{}, fmt.Errorf("update %s: %w", targetPath, err)
-		}
-		if err := os.WriteFile(targetPath, []byte(out), 0o644); err != nil {
-			return SyncResult{}, fmt.Errorf("write %s: %w", targetPath, err)
+		if out != string(existing) {
+			outputs[targetPath] = []byte(out)
 		}
 		updated++
 	}
-	return SyncResult{DocsUpdated: updated}, nil
+	return outputs, updated, m, nil
 }
 
 type VerifyOptions struct {
 	RepoRoot string
 	DocsRoot string
+	// FS is the target repo; nil uses RepoRoot on disk.
+	FS FS
 
 	CacheDir   string
 	SourceRepo string
 	SourceRef  string
 	ProfileID  string
+	// Fetch resolves the governance source; nil uses source.Fetch.
+	Fetch source.Fetcher
+
+	MarkerPrefix      string
+	HashNormalization managedblocks.Normalization
 
-	MarkerPrefix string
+	Variables map[string]any
+	Facts     conditions.Facts
+
+	Parameters map[string]any
 }
 
 type VerifyResult struct {
 	OK     bool
-	Issues []string
+	Issues []Issue
+}
+
+// Issue is a verification problem with one document (or document section).
+type Issue struct {
+	// Document is the output path, with "#section" for a named section.
+	Document string
+	Message  string
 }
 
+func (i Issue) String() string { return i.Document + ": " + i.Message }
+
 func Verify(ctx context.Context, opts VerifyOptions) (VerifyResult, error) {
 	if strings.TrimSpace(opts.RepoRoot) == "" {
 		opts.RepoRoot = "."
@@ -150,33 +301,107 @@ func Verify(ctx context.Context, opts VerifyOptions) (VerifyResult, error) {
 		opts.MarkerPrefix = "GOV"
 	}
 
-	src, err := source.Fetch(ctx, source.FetchOptions{
-		RepoURL:  opts.SourceRepo,
-		Ref:      opts.SourceRef,
-		CacheDir: opts.CacheDir,
+	m, src, err := LoadProfile(ctx, ProfileOptions{
+		CacheDir:   opts.CacheDir,
+		SourceRepo: opts.SourceRepo,
+		SourceRef:  opts.SourceRef,
+		ProfileID:  opts.ProfileID,
+		Fetch:      opts.Fetch,
 	})
 	if err != nil {
 		return VerifyResult{}, err
 	}
-	manifestPath := filepath.Join(src.CheckoutDir, "Governance", "Profiles", opts.ProfileID, "profile.yaml")
-	m, err := profile.LoadManifest(manifestPath)
+
+	rc, err := newRenderContext(m, src, opts.Variables, opts.Parameters, opts.Facts)
 	if err != nil {
 		return VerifyResult{}, err
 	}
 
-	targetBase := filepath.Clean(filepath.Join(opts.RepoRoot, opts.DocsRoot))
-	var issues []string
+	fsys := targetFS(opts.FS, opts.RepoRoot)
+	var issues []Issue
 	for _, doc := range m.Documents {
-		targetPath := filepath.Join(targetBase, doc.Output)
-		existing, err := os.ReadFile(targetPath)
+		existing, err := fsys.ReadFile(targetName(opts.DocsRoot, doc.Output))
 		if err != nil {
-			issues = append(issues, fmt.Sprintf("%s: missing or unreadable (%v)", doc.Output, err))
+			issues = append(issues, Issue{doc.Output, fmt.Sprintf("missing or unreadable (%v)", err)})
 			continue
 		}
-		blockID := managedBlockIDForDoc(doc.Output)
-		if err := managedblocks.VerifyBlockSHA256(string(existing), opts.MarkerPrefix, blockID); err != nil {
-			issues = append(issues, fmt.Sprintf("%s: %v", doc.Output, err))
+		for _, sec := range doc.Blocks() {
+			issues = append(issues, verifyBlock(rc, string(existing), doc.CommentSyntax(), opts.MarkerPrefix, opts.HashNormalization, doc.Output, sec)...)
 		}
 	}
 	return VerifyResult{OK: len(issues) == 0, Issues: issues}, nil
 }
+
+// verifyBlock checks one section's block in a document's existing content.
+func verifyBlock(rc renderContext, existing string, syntax managedblocks.Syntax, prefix string, hash managedblocks.Normalization, output string, sec profile.SectionSpec) []Issue {
+	label := sectionLabel(output, sec.Name)
+	blockID := sectionBlockID(output, sec.Name)
+	if err := managedblocks.VerifyBlockSHA256(existing, syntax, prefix, blockID); err != nil {
+		return []Issue{{label, err.Error()}}
+	}
+	// The rendering is only reproducible if variables and conditions match those recorded at sync time.
+	a, err := rc.assemble(sec.Fragments)
+	if err != nil {
+		return []Issue{{label, err.Error()}}
+	}
+	meta, err := managedblocks.BlockMeta(existing, syntax, prefix, blockID)
+	if err != nil {
+		return []Issue{{label, err.Error()}}
+	}
+	var issues []Issue
+	if meta[managedblocks.HashMetaKey] != hash.Tag() {
+		issues = append(issues, Issue{label, fmt.Sprintf("hash normalization changed since the block was rendered (recorded %s, now %s; run sync)", normalizationName(meta[managedblocks.HashMetaKey]), normalizationName(hash.Tag()))})
+	}
+	if meta["vars"] != a.Vars {
+		issues = append(issues, Issue{label, "variables changed since the block was rendered (run sync)"})
+	}
+	if meta["when"] != a.Conditions {
+		issues = append(issues, Issue{label, fmt.Sprintf("conditions changed since the block was rendered (recorded %q, now %q; run sync)", meta["when"], a.Conditions)})
+	}
+	if meta["fragments"] != a.Fragments {
+		issues = append(issues, Issue{label, fmt.Sprintf("included fragments changed since the block was rendered (%s; run sync)", describeFragmentChange(meta["fragments"], a.Fragments))})
+	}
+	return issues
+}
+
+// normalizationName names the mode for a recorded hash tag.
+func normalizationName(tag string) string {
+	if tag == "" {
+		return string(managedblocks.Exact)
+	}
+	return tag
+}
+
+// describeFragmentChange explains the difference between two recorded fragment lists.
+func describeFragmentChange(recorded, current string) string {
+	split := func(s string) []string {
+		if s == "" {
+			return nil
+		}
+		return strings.Split(s, ",")
+	}
+	was, now := split(recorded), split(current)
+	in := func(list []string, v string) bool {
+		for _, x := range list {
+			if x == v {
+				return true
+			}
+		}
+		return false
+	}
+	var parts []string
+	for _, f := range now {
+		if !in(was, f) {
+			parts = append(parts, "added "+f)
+		}
+	}
+	for _, f := range was {
+		if !in(now, f) {
+			parts = append(parts, "removed "+f)
+		}
+	}
+	if len(parts) == 0 {
+		return "reordered"
+	}
+	return strings.Join(parts, ", ")
+}
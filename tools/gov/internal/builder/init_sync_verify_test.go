@@ -2,10 +2,16 @@ package builder
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/conditions"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/managedblocks"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/source"
 )
 
 func TestInitSyncVerify_PreservesLocalAddenda(t *testing.T) {
@@ -366,3 +372,614 @@ documents:
 		t.Fatalf("expected issues")
 	}
 }
+
+func TestBuildSyncVerify_RendersFragmentVariables(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+	target := filepath.Join(tmp, "target")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Core", "Quality.Core.md"), "{{ .Project.Name }} keeps coverage at or above {{ .Coverage.Threshold }}%.\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Core", "Plain.md"), "PLAIN\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: p
+variables:
+  Project:
+    Name: unnamed
+  Coverage:
+    Threshold: 85
+documents:
+  - output: Quality.md
+    fragments: [../../Core/Quality.Core.md]
+  - output: Plain.md
+    fragments: [../../Core/Plain.md]
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+
+	vars := map[string]any{"Project": map[string]any{"Name": "payments"}}
+	if _, err := Init(ctx, InitOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p", Variables: vars}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	quality, err := os.ReadFile(filepath.Join(target, "Quality.md"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(quality), "payments keeps coverage at or above 85%.") || !strings.Contains(string(quality), " vars=") {
+		t.Fatalf("expected rendered content with vars digest, got:\n%s", quality)
+	}
+	plain, _ := os.ReadFile(filepath.Join(target, "Plain.md"))
+	if strings.Contains(string(plain), " vars=") {
+		t.Fatalf("expected no vars digest for plain doc, got:\n%s", plain)
+	}
+
+	verifyOpts := VerifyOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p", Variables: vars}
+	if vr, err := Verify(ctx, verifyOpts); err != nil || !vr.OK {
+		t.Fatalf("expected verify ok, got %+v err=%v", vr, err)
+	}
+
+	// Variables the fragments do not reference are not part of the digest.
+	verifyOpts.Variables = map[string]any{"Project": map[string]any{"Name": "payments", "Owner": "team-b"}, "Unused": 1}
+	if vr, err := Verify(ctx, verifyOpts); err != nil || !vr.OK {
+		t.Fatalf("expected verify ok with unrelated variables changed, got %+v err=%v", vr, err)
+	}
+
+	// Changing a variable without syncing is reported as drift.
+	verifyOpts.Variables = map[string]any{"Project": map[string]any{"Name": "ledger"}}
+	vr, err := Verify(ctx, verifyOpts)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if vr.OK || len(vr.Issues) != 1 || !strings.Contains(vr.Issues[0].String(), "Quality.md: variables changed") {
+		t.Fatalf("expected variables drift issue, got %+v", vr)
+	}
+
+	if _, err := Sync(ctx, SyncOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p", Variables: verifyOpts.Variables}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	quality, _ = os.ReadFile(filepath.Join(target, "Quality.md"))
+	if !strings.Contains(string(quality), "ledger keeps coverage") {
+		t.Fatalf("expected re-rendered content, got:\n%s", quality)
+	}
+	if vr, err := Verify(ctx, verifyOpts); err != nil || !vr.OK {
+		t.Fatalf("expected verify ok after sync, got %+v err=%v", vr, err)
+	}
+
+	// Undefined variables fail the render.
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Core", "Plain.md"), "{{ .Project.Module }}\n")
+	mustRun(t, srcRepo, "git", "commit", "-am", "v2")
+	_, err = Sync(ctx, SyncOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p", Variables: vars})
+	if err == nil || !strings.Contains(err.Error(), "Module") {
+		t.Fatalf("expected undefined variable error, got %v", err)
+	}
+}
+
+func TestBuildSyncVerify_AppliesConditions(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+	target := filepath.Join(tmp, "target")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Core", "Quality.md"), "QUALITY\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Core", "OpenAPI.md"), "OPENAPI-RULE\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Core", "GitLab.md"), "GITLAB\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: p
+documents:
+  - output: Quality.md
+    fragments:
+      - ../../Core/Quality.md
+      - path: ../../Core/OpenAPI.md
+        when: go && openapi
+playbooks:
+  - source: ../../Core/GitLab.md
+    output: Docs/Playbooks/GitLab.md
+    when: gitlab
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+
+	facts := conditions.Facts{"go": true, "openapi": false}
+	if _, err := Init(ctx, InitOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p", Facts: facts}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	quality, err := os.ReadFile(filepath.Join(target, "Quality.md"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if strings.Contains(string(quality), "OPENAPI-RULE") || !strings.Contains(string(quality), " when=go:true,openapi:false ") {
+		t.Fatalf("expected excluded fragment and recorded conditions, got:\n%s", quality)
+	}
+	if _, err := os.Stat(filepath.Join(target, "Docs", "Playbooks", "GitLab.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected GitLab playbook to be skipped, got err=%v", err)
+	}
+
+	verifyOpts := VerifyOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p", Facts: facts}
+	if vr, err := Verify(ctx, verifyOpts); err != nil || !vr.OK {
+		t.Fatalf("expected verify ok, got %+v err=%v", vr, err)
+	}
+
+	verifyOpts.Facts = conditions.Facts{"go": true, "openapi": true}
+	vr, err := Verify(ctx, verifyOpts)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if vr.OK || !strings.Contains(fmt.Sprint(vr.Issues), "conditions changed") {
+		t.Fatalf("expected conditions drift, got %+v", vr)
+	}
+
+	if _, err := Sync(ctx, SyncOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p", Facts: verifyOpts.Facts}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	quality, _ = os.ReadFile(filepath.Join(target, "Quality.md"))
+	if !strings.Contains(string(quality), "QUALITY\n\nOPENAPI-RULE") || !strings.Contains(string(quality), " when=go:true,openapi:true ") {
+		t.Fatalf("expected included fragment after sync, got:\n%s", quality)
+	}
+	if vr, err := Verify(ctx, verifyOpts); err != nil || !vr.OK {
+		t.Fatalf("expected verify ok after sync, got %+v err=%v", vr, err)
+	}
+}
+
+func TestBuild_ExposesValidatedParameters(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Core", "Quality.md"), "Coverage >= {{ .Params.coverageThreshold }}% on {{ .Params.ciProvider }}.\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: p
+parameters:
+  coverageThreshold:
+    type: int
+    min: 0
+    max: 100
+    default: 85
+  ciProvider:
+    type: enum
+    values: [github, gitlab]
+documents:
+  - output: Quality.md
+    fragments: [../../Core/Quality.md]
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+
+	opts := BuildOptions{OutDir: filepath.Join(tmp, "out"), CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p",
+		Parameters: map[string]any{"ciProvider": "gitlab"}}
+	if _, err := Build(ctx, opts); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(tmp, "out", "Quality.md"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(b), "Coverage >= 85% on gitlab.") {
+		t.Fatalf("expected rendered parameters, got:\n%s", b)
+	}
+
+	opts.Parameters = map[string]any{"ciProvider": "gitlab", "coverageThreshold": 101}
+	if _, err := Build(ctx, opts); err == nil || !strings.Contains(err.Error(), "parameter coverageThreshold: 101 is above max 100") {
+		t.Fatalf("expected parameter error, got %v", err)
+	}
+}
+
+func TestInitVerify_RecordsExpandedFragments(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+	target := filepath.Join(tmp, "target")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Core", "Rules", "b.md"), "RULE-B\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Core", "Rules", "a.md"), "---\norder: 1\n---\n\nRULE-A\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: p
+documents:
+  - output: Rules.md
+    fragments:
+      - ../../Core/Rules
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+	mustRun(t, srcRepo, "git", "tag", "v1")
+
+	if _, err := Init(ctx, InitOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "v1", ProfileID: "p"}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(target, "Rules.md"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	doc := string(b)
+	if !strings.Contains(doc, "-->\nRULE-B\n\nRULE-A\n<!-- GOV:END") || strings.Contains(doc, "order:") {
+		t.Fatalf("expected ordered fragments without frontmatter, got:\n%s", doc)
+	}
+	if !strings.Contains(doc, " fragments=Governance/Core/Rules/b.md,Governance/Core/Rules/a.md ") {
+		t.Fatalf("expected recorded fragment list, got:\n%s", doc)
+	}
+
+	// A new upstream rule file is explained by verify before the next sync.
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Core", "Rules", "c.md"), "RULE-C\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v2")
+	vr, err := Verify(ctx, VerifyOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p"})
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if vr.OK || len(vr.Issues) != 1 || !strings.Contains(vr.Issues[0].String(), "included fragments changed since the block was rendered (added Governance/Core/Rules/c.md; run sync)") {
+		t.Fatalf("expected fragment change issue, got %+v", vr)
+	}
+}
+
+func TestDescribeFragmentChange(t *testing.T) {
+	if got := describeFragmentChange("a,b", "b,c"); got != "added c, removed a" {
+		t.Fatalf("unexpected: %q", got)
+	}
+	if got := describeFragmentChange("a,b", "b,a"); got != "reordered" {
+		t.Fatalf("unexpected: %q", got)
+	}
+	if got := describeFragmentChange("", "a"); got != "added a" {
+		t.Fatalf("unexpected: %q", got)
+	}
+}
+
+func TestInitSync_ResolvesCrossRepoExtendsAndRecordsCommits(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	pubRepo := filepath.Join(tmp, "public")
+	orgRepo := filepath.Join(tmp, "org")
+	cache := filepath.Join(tmp, "cache")
+	target := filepath.Join(tmp, "target")
+
+	for _, repo := range []string{pubRepo, orgRepo} {
+		mustRun(t, tmp, "git", "init", repo)
+		mustRun(t, repo, "git", "config", "user.email", "test@example.com")
+		mustRun(t, repo, "git", "config", "user.name", "Test")
+	}
+	writeFile(t, filepath.Join(pubRepo, "Governance", "Profiles", "base", "Rules.md"), "PUBLIC\n")
+	writeFile(t, filepath.Join(pubRepo, "Governance", "Profiles", "base", "profile.yaml"), "schemaVersion: 1\nid: base\ndocuments:\n  - output: Rules.md\n    fragments: [./Rules.md]\n")
+	mustRun(t, pubRepo, "git", "add", ".")
+	mustRun(t, pubRepo, "git", "commit", "-m", "public")
+	mustRun(t, pubRepo, "git", "tag", "v1")
+
+	writeFile(t, filepath.Join(orgRepo, "Governance", "Profiles", "org", "Rules.Org.md"), "ORG\n")
+	writeFile(t, filepath.Join(orgRepo, "Governance", "Profiles", "org", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: org
+extends: ["`+pubRepo+`@v1#Governance/Profiles/base"]
+documents:
+  - output: Rules.md
+    merge: append-fragments
+    fragments: [./Rules.Org.md]
+`)+"\n")
+	mustRun(t, orgRepo, "git", "add", ".")
+	mustRun(t, orgRepo, "git", "commit", "-m", "org")
+	mustRun(t, orgRepo, "git", "tag", "v1")
+
+	if _, err := Init(ctx, InitOptions{RepoRoot: target, CacheDir: cache, SourceRepo: orgRepo, SourceRef: "v1", ProfileID: "org"}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(target, "Rules.md"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	doc := string(b)
+	if !strings.Contains(doc, "-->\nPUBLIC\n\nORG\n<!-- GOV:END") {
+		t.Fatalf("expected public base and org fragments, got:\n%s", doc)
+	}
+	pubCommit := strings.TrimSpace(gitOutput(t, pubRepo, "rev-parse", "HEAD"))
+	if !strings.Contains(doc, " baseSources="+pubRepo+"@"+pubCommit+" ") {
+		t.Fatalf("expected recorded base source commit, got:\n%s", doc)
+	}
+
+	// A new public commit is picked up on sync when the org profile moves its pin.
+	writeFile(t, filepath.Join(pubRepo, "Governance", "Profiles", "base", "Rules.md"), "PUBLIC v2\n")
+	mustRun(t, pubRepo, "git", "commit", "-am", "public v2")
+	mustRun(t, pubRepo, "git", "tag", "v2")
+	writeFile(t, filepath.Join(orgRepo, "Governance", "Profiles", "org", "profile.yaml"), strings.Replace(readFile(t, filepath.Join(orgRepo, "Governance", "Profiles", "org", "profile.yaml")), "@v1#", "@v2#", 1))
+	mustRun(t, orgRepo, "git", "commit", "-am", "bump public")
+	if _, err := Sync(ctx, SyncOptions{RepoRoot: target, CacheDir: cache, SourceRepo: orgRepo, SourceRef: "HEAD", ProfileID: "org"}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	doc = readFile(t, filepath.Join(target, "Rules.md"))
+	pubCommit = strings.TrimSpace(gitOutput(t, pubRepo, "rev-parse", "HEAD"))
+	if !strings.Contains(doc, "PUBLIC v2\n\nORG") || !strings.Contains(doc, " baseSources="+pubRepo+"@"+pubCommit+" ") {
+		t.Fatalf("expected synced public v2, got:\n%s", doc)
+	}
+}
+
+func TestInitSyncVerify_ManagesBlocksInConfigFiles(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+	target := filepath.Join(tmp, "target")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "make.mk"), "gov-verify:\n\tagent-gov verify\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "lint.yml"), "linters:\n  enable: [errcheck]\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "header.txt"), "Copyright Example\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "pom.xml"), "<properties><coverage>85</coverage></properties>\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: p
+documents:
+  - output: Makefile
+    fragments: [./make.mk]
+  - output: .golangci.yml
+    fragments: [./lint.yml]
+  - output: LICENSE.header
+    comment: slash
+    fragments: [./header.txt]
+  - output: pom.xml
+    fragments: [./pom.xml]
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+	mustRun(t, srcRepo, "git", "tag", "v1")
+
+	// The project's own Makefile content must survive init.
+	writeFile(t, filepath.Join(target, "Makefile"), "build:\n\tgo build ./...\n")
+	pom := "<?xml version=\"1.0\"?>\n<project>\n  <artifactId>svc</artifactId>\n</project>\n"
+	writeFile(t, filepath.Join(target, "pom.xml"), pom)
+
+	if _, err := Init(ctx, InitOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "v1", ProfileID: "p"}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	makefile := readFile(t, filepath.Join(target, "Makefile"))
+	if !strings.HasPrefix(makefile, "build:\n\tgo build ./...\n\n# GOV:BEGIN id=doc-makefile ") || !strings.HasSuffix(makefile, "\ngov-verify:\n\tagent-gov verify\n# GOV:END id=doc-makefile\n") {
+		t.Fatalf("unexpected Makefile:\n%s", makefile)
+	}
+	lint := readFile(t, filepath.Join(target, ".golangci.yml"))
+	if !strings.HasPrefix(lint, "# GOV:BEGIN id=doc-golangci ") || strings.Contains(lint, "Local Addenda") {
+		t.Fatalf("unexpected .golangci.yml:\n%s", lint)
+	}
+	if header := readFile(t, filepath.Join(target, "LICENSE.header")); !strings.HasPrefix(header, "// GOV:BEGIN id=doc-license ") {
+		t.Fatalf("expected slash markers from the manifest setting, got:\n%s", header)
+	}
+	// HTML-comment files other than Markdown keep their content too; only the block is added.
+	if got := readFile(t, filepath.Join(target, "pom.xml")); !strings.HasPrefix(got, pom+"\n<!-- GOV:BEGIN id=doc-pom ") || strings.Contains(got, "Local Addenda") || strings.Contains(got, "Generated by agent-gov") {
+		t.Fatalf("unexpected pom.xml:\n%s", got)
+	}
+
+	// Upstream changes are synced into the block; project lines after it are kept.
+	writeFile(t, filepath.Join(target, "Makefile"), makefile+"\ntest:\n\tgo test ./...\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "make.mk"), "gov-verify:\n\tagent-gov verify --config .governance/config.yaml\n")
+	mustRun(t, srcRepo, "git", "commit", "-am", "v2")
+	if _, err := Sync(ctx, SyncOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p"}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	makefile = readFile(t, filepath.Join(target, "Makefile"))
+	if !strings.Contains(makefile, "\tagent-gov verify --config .governance/config.yaml\n# GOV:END id=doc-makefile\n\ntest:\n") || !strings.HasPrefix(makefile, "build:\n") {
+		t.Fatalf("unexpected synced Makefile:\n%s", makefile)
+	}
+	vr, err := Verify(ctx, VerifyOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p"})
+	if err != nil || !vr.OK {
+		t.Fatalf("Verify: %+v %v", vr, err)
+	}
+}
+
+func TestInitSyncVerify_SectionsInterleaveWithProjectText(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+	target := filepath.Join(tmp, "target")
+	profileDir := filepath.Join(srcRepo, "Governance", "Profiles", "p")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(profileDir, "gates.md"), "GATES v1\n")
+	writeFile(t, filepath.Join(profileDir, "agents.md"), "AGENTS v1\n")
+	writeFile(t, filepath.Join(profileDir, "releases.md"), "RELEASES\n")
+	writeFile(t, filepath.Join(profileDir, "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: p
+documents:
+  - output: Constitution.md
+    sections:
+      - name: quality-gates
+        fragments: [./gates.md]
+      - name: agents
+        fragments: [./agents.md]
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+	mustRun(t, srcRepo, "git", "tag", "v1")
+
+	if _, err := Init(ctx, InitOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "v1", ProfileID: "p"}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	docPath := filepath.Join(target, "Constitution.md")
+	doc := readFile(t, docPath)
+	if !strings.Contains(doc, "GATES v1\n<!-- GOV:END id=doc-constitution-quality-gates -->\n\n<!-- GOV:BEGIN id=doc-constitution-agents ") {
+		t.Fatalf("expected two consecutive blocks, got:\n%s", doc)
+	}
+
+	// Project-owned text between the governed sections survives sync.
+	doc = strings.Replace(doc, "<!-- GOV:END id=doc-constitution-quality-gates -->\n", "<!-- GOV:END id=doc-constitution-quality-gates -->\n\n## Exceptions\n\nLOCAL\n", 1)
+	writeFile(t, docPath, doc)
+
+	writeFile(t, filepath.Join(profileDir, "agents.md"), "AGENTS v2\n")
+	writeFile(t, filepath.Join(profileDir, "profile.yaml"), readFile(t, filepath.Join(profileDir, "profile.yaml"))+"      - name: releases\n        fragments: [./releases.md]\n")
+	mustRun(t, srcRepo, "git", "commit", "-am", "v2")
+	if _, err := Sync(ctx, SyncOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p"}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	doc = readFile(t, docPath)
+	for _, want := range []string{
+		"GATES v1\n<!-- GOV:END id=doc-constitution-quality-gates -->\n\n## Exceptions\n\nLOCAL\n",
+		"AGENTS v2\n<!-- GOV:END id=doc-constitution-agents -->\n\n<!-- GOV:BEGIN id=doc-constitution-releases ",
+		"RELEASES\n<!-- GOV:END id=doc-constitution-releases -->\n\n## Local Addenda (project-owned)",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Fatalf("expected %q in synced doc:\n%s", want, doc)
+		}
+	}
+
+	verify := func() VerifyResult {
+		vr, err := Verify(ctx, VerifyOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p"})
+		if err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+		return vr
+	}
+	if vr := verify(); !vr.OK {
+		t.Fatalf("expected verify ok, got %+v", vr)
+	}
+	writeFile(t, docPath, strings.Replace(doc, "AGENTS v2", "EDITED", 1))
+	if vr := verify(); vr.OK || len(vr.Issues) != 1 || !strings.HasPrefix(vr.Issues[0].String(), "Constitution.md#agents: block \"doc-constitution-agents\" sha256 mismatch") {
+		t.Fatalf("expected agents section issue, got %+v", vr)
+	}
+}
+
+func TestInitSyncVerify_HashNormalizationSurvivesReformatting(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+	target := filepath.Join(tmp, "target")
+	profileDir := filepath.Join(srcRepo, "Governance", "Profiles", "p")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(profileDir, "rules.md"), "# Rules\n\n- one\n- two\n")
+	writeFile(t, filepath.Join(profileDir, "profile.yaml"), "schemaVersion: 1\nid: p\ndocuments:\n  - output: Rules.md\n    fragments: [./rules.md]\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+
+	ws := managedblocks.NormalizeWhitespace
+	if _, err := Init(ctx, InitOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p", HashNormalization: ws}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	docPath := filepath.Join(target, "Rules.md")
+	doc := readFile(t, docPath)
+	if !strings.Contains(doc, " hash=normalize-whitespace ") {
+		t.Fatalf("expected hash tag in marker:\n%s", doc)
+	}
+	// A Windows checkout plus an editor adding trailing spaces and blank lines.
+	reformatted := strings.NewReplacer("# Rules\n", "# Rules\n\n", "- one\n", "- one  \n").Replace(doc)
+	reformatted = strings.ReplaceAll(reformatted, "\n", "\r\n")
+	writeFile(t, docPath, reformatted)
+
+	verify := func(n managedblocks.Normalization) VerifyResult {
+		vr, err := Verify(ctx, VerifyOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p", HashNormalization: n})
+		if err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+		return vr
+	}
+	if vr := verify(ws); !vr.OK {
+		t.Fatalf("expected reformatted doc to verify, got %+v", vr)
+	}
+	vr := verify(managedblocks.Exact)
+	if vr.OK || len(vr.Issues) != 1 || !strings.Contains(vr.Issues[0].String(), "hash normalization changed since the block was rendered (recorded normalize-whitespace, now exact; run sync)") {
+		t.Fatalf("expected normalization change issue, got %+v", vr)
+	}
+
+	if _, err := Sync(ctx, SyncOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p"}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if strings.Contains(readFile(t, docPath), "hash=") {
+		t.Fatalf("exact sync should drop the hash tag")
+	}
+	if vr := verify(managedblocks.Exact); !vr.OK {
+		t.Fatalf("expected verify ok after exact sync, got %+v", vr)
+	}
+}
+
+func TestSyncVerify_IgnoresMarkersOfOtherSyntaxesInMarkdown(t *testing.T) {
+	ctx := context.Background()
+	fsys := NewMemFS()
+	opts := SyncOptions{FS: fsys, SourceRepo: "mem", SourceRef: "v1", ProfileID: "p", Fetch: twoDocFetch(t)}
+	if _, err := Init(ctx, InitOptions{FS: fsys, SourceRepo: "mem", SourceRef: "v1", ProfileID: "p", Fetch: twoDocFetch(t)}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	// A code sample in the project-owned addenda shows how config files are governed.
+	sample := "\n```yaml\n# GOV:BEGIN id=doc-a\nlint: strict\n```\n"
+	a, _ := fsys.ReadFile("A.md")
+	if err := fsys.WriteFile("A.md", append(a, sample...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts.SourceRef = "v2"
+	if _, err := Sync(ctx, opts); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	got, _ := fsys.ReadFile("A.md")
+	if !strings.Contains(string(got), "\nA v2\n") || !strings.HasSuffix(string(got), sample) {
+		t.Fatalf("unexpected A.md:\n%s", got)
+	}
+	res, err := Verify(ctx, VerifyOptions{FS: fsys, SourceRepo: "mem", SourceRef: "v2", ProfileID: "p", Fetch: twoDocFetch(t)})
+	if err != nil || !res.OK {
+		t.Fatalf("Verify: %+v %v", res, err)
+	}
+}
+
+func TestSync_AddsBlocksToNonMarkdownFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "checkout")
+	// v2 adds a Go source header and a Makefile block to the profile.
+	fetch := func(_ context.Context, opts source.FetchOptions) (source.ResolvedSource, error) {
+		manifest := "schemaVersion: 1\nid: p\ndocuments:\n  - output: Rules.md\n    fragments: [./Rules.md]\n"
+		if opts.Ref == "v2" {
+			manifest += "  - output: main.go\n    placement: top\n    fragments: [./header.txt]\n  - output: Makefile\n    fragments: [./make.mk]\n"
+		}
+		files := fstest.MapFS{
+			"Governance/Profiles/p/profile.yaml": {Data: []byte(manifest)},
+			"Governance/Profiles/p/Rules.md":     {Data: []byte("RULES\n")},
+			"Governance/Profiles/p/header.txt":   {Data: []byte("Copyright Example\n")},
+			"Governance/Profiles/p/make.mk":      {Data: []byte("gov-verify:\n\tagent-gov verify\n")},
+		}
+		return source.ResolvedSource{CheckoutDir: dir, SourceRepo: opts.RepoURL, SourceRef: opts.Ref, SourceCommit: opts.Ref, FS: files}, nil
+	}
+	fsys := NewMemFS()
+	if _, err := Init(ctx, InitOptions{FS: fsys, SourceRepo: "mem", SourceRef: "v1", ProfileID: "p", Fetch: fetch}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := fsys.WriteFile("main.go", []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Sync(ctx, SyncOptions{FS: fsys, SourceRepo: "mem", SourceRef: "v2", ProfileID: "p", Fetch: fetch})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if res.DocsUpdated != 3 {
+		t.Fatalf("expected 3 documents, got %+v", res)
+	}
+	main, _ := fsys.ReadFile("main.go")
+	if !strings.HasPrefix(string(main), "// GOV:BEGIN id=doc-main ") || !strings.HasSuffix(string(main), "// GOV:END id=doc-main\n\npackage main\n\nfunc main() {}\n") {
+		t.Fatalf("expected the header above the package clause, got:\n%s", main)
+	}
+	if mk, _ := fsys.ReadFile("Makefile"); !strings.HasPrefix(string(mk), "# GOV:BEGIN id=doc-makefile ") {
+		t.Fatalf("expected a new Makefile with the block, got:\n%s", mk)
+	}
+	vr, err := Verify(ctx, VerifyOptions{FS: fsys, SourceRepo: "mem", SourceRef: "v2", ProfileID: "p", Fetch: fetch})
+	if err != nil || !vr.OK {
+		t.Fatalf("Verify: %+v %v", vr, err)
+	}
+}
@@ -0,0 +1,91 @@
+package builder
+
+import (
+	"context"
+	"io/fs"
+	"os"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/profile"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/source"
+)
+
+// ProfileListing is one profile of a governance source: its resolved manifest, or the
+// error that kept it from loading.
+type ProfileListing struct {
+	ID       string
+	Manifest profile.Manifest
+	Err      error
+}
+
+// ListProfiles fetches the governance source and loads every profile in it, sorted by ID.
+// ProfileID is ignored.
+func ListProfiles(ctx context.Context, opts ProfileOptions) ([]ProfileListing, source.ResolvedSource, error) {
+	fetch := opts.Fetch
+	if fetch == nil {
+		fetch = source.Fetch
+	}
+	src, err := fetch(ctx, source.FetchOptions{
+		RepoURL:  opts.SourceRepo,
+		Ref:      opts.SourceRef,
+		CacheDir: opts.CacheDir,
+	})
+	if err != nil {
+		return nil, source.ResolvedSource{}, err
+	}
+	fsys := checkoutFS(src)
+	ids, err := profile.ListIDs(fsys)
+	if err != nil {
+		return nil, source.ResolvedSource{}, err
+	}
+	loadOpts := profile.LoadOptions{Resolve: fetchResolver(ctx, opts.CacheDir, fetch), Root: src.CheckoutDir, FS: fsys}
+	out := make([]ProfileListing, 0, len(ids))
+	for _, id := range ids {
+		m, err := profile.LoadManifestWith(profile.ManifestPath(src.CheckoutDir, id), loadOpts)
+		out = append(out, ProfileListing{ID: id, Manifest: m, Err: err})
+	}
+	return out, src, nil
+}
+
+// ProfileValidation reports the profiles ValidateProfiles checked and what it found.
+type ProfileValidation struct {
+	IDs      []string
+	Problems []profile.Problem
+}
+
+// ValidateProfiles checks the profiles ids of the governance source src (a local checkout,
+// not fetched) for missing and escaping files. With no ids every profile is checked, and
+// shared files no profile references are reported too. Only CacheDir and Fetch of opts
+// are used, to resolve cross-repository extends.
+func ValidateProfiles(ctx context.Context, src source.ResolvedSource, ids []string, opts ProfileOptions) (ProfileValidation, error) {
+	fetch := opts.Fetch
+	if fetch == nil {
+		fetch = source.Fetch
+	}
+	fsys := checkoutFS(src)
+	all := len(ids) == 0
+	if all {
+		var err error
+		if ids, err = profile.ListIDs(fsys); err != nil {
+			return ProfileValidation{}, err
+		}
+	}
+	problems, err := profile.Validate(profile.ValidateOptions{
+		Root:        src.CheckoutDir,
+		FS:          fsys,
+		IDs:         ids,
+		CheckUnused: all,
+		Resolve:     fetchResolver(ctx, opts.CacheDir, fetch),
+	})
+	if err != nil {
+		return ProfileValidation{}, err
+	}
+	return ProfileValidation{IDs: ids, Problems: problems}, nil
+}
+
+// checkoutFS serves src's files: its FS, or the checkout on disk.
+func checkoutFS(src source.ResolvedSource) fs.FS {
+	if src.FS != nil {
+		return src.FS
+	}
+	return os.DirFS(src.CheckoutDir)
+}
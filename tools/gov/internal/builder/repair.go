@@ -0,0 +1,145 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/conditions"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/managedblocks"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/source"
+)
+
+type RepairOptions struct {
+	RepoRoot string
+	DocsRoot string
+	FS       FS
+
+	CacheDir   string
+	SourceRepo string
+	SourceRef  string
+	ProfileID  string
+	Fetch      source.Fetcher
+
+	MarkerPrefix      string
+	HashNormalization managedblocks.Normalization
+
+	Variables map[string]any
+	Facts     conditions.Facts
+
+	Parameters map[string]any
+}
+
+// FileRepair is the proposed fix for one governed document.
+type FileRepair struct {
+	// Path is the document's path (below RepoRoot); Output is its profile output name.
+	Path   string
+	Output string
+	// Fixes describe each repaired block, e.g. "Out#rules: missing END marker; block now spans lines 4-20".
+	Fixes []string
+	// Problems are blocks whose markers are damaged but could not be repaired.
+	Problems []string
+	// Content is the repaired document; it is only meaningful when Fixes is non-empty.
+	Content string
+
+	fsys FS
+	name string
+}
+
+type RepairResult struct {
+	// Files lists documents with fixes or problems; intact documents are omitted.
+	Files []FileRepair
+}
+
+// Repair locates damaged managed-block markers (a missing BEGIN or END, duplicates) in the
+// governed documents and proposes fixed documents. Where a marker is missing, the block is
+// located by fuzzy-matching the upstream content, so local addenda stay outside the block.
+// Nothing is written; see ApplyRepair.
+func Repair(ctx context.Context, opts RepairOptions) (RepairResult, error) {
+	if strings.TrimSpace(opts.RepoRoot) == "" {
+		opts.RepoRoot = "."
+	}
+	if strings.TrimSpace(opts.DocsRoot) == "" {
+		opts.DocsRoot = "."
+	}
+	if strings.TrimSpace(opts.MarkerPrefix) == "" {
+		opts.MarkerPrefix = "GOV"
+	}
+
+	m, src, err := LoadProfile(ctx, ProfileOptions{
+		CacheDir:   opts.CacheDir,
+		SourceRepo: opts.SourceRepo,
+		SourceRef:  opts.SourceRef,
+		ProfileID:  opts.ProfileID,
+		Fetch:      opts.Fetch,
+	})
+	if err != nil {
+		return RepairResult{}, err
+	}
+
+	rc, err := newRenderContext(m, src, opts.Variables, opts.Parameters, opts.Facts)
+	if err != nil {
+		return RepairResult{}, err
+	}
+
+	fsys := targetFS(opts.FS, opts.RepoRoot)
+	var res RepairResult
+	for _, doc := range m.Documents {
+		name := targetName(opts.DocsRoot, doc.Output)
+		existing, err := fsys.ReadFile(name)
+		if err != nil {
+			// Missing documents are for init, not repair.
+			continue
+		}
+		fr := FileRepair{Path: filepath.Join(opts.RepoRoot, filepath.FromSlash(name)), Output: doc.Output, fsys: fsys, name: name}
+		out := string(existing)
+		for _, sec := range doc.Blocks() {
+			label := sectionLabel(doc.Output, sec.Name)
+			a, err := rc.assemble(sec.Fragments)
+			if err != nil {
+				return RepairResult{}, fmt.Errorf("assemble %s: %w", label, err)
+			}
+			meta := a.meta()
+			meta["version"] = src.SourceRef
+			meta["sourceRepo"] = src.SourceRepo
+			meta["sourceRef"] = src.SourceRef
+			meta["sourceCommit"] = src.SourceCommit
+			meta[managedblocks.HashMetaKey] = opts.HashNormalization.Tag()
+			r, err := managedblocks.RepairBlock(out, managedblocks.RepairOptions{
+				Prefix:   opts.MarkerPrefix,
+				BlockID:  sectionBlockID(doc.Output, sec.Name),
+				Expected: a.Content,
+				Syntax:   doc.CommentSyntax(),
+				Meta:     meta,
+			})
+			if err != nil {
+				fr.Problems = append(fr.Problems, fmt.Sprintf("%s: %v", label, err))
+				continue
+			}
+			if r.Changed {
+				out = r.Doc
+				fr.Fixes = append(fr.Fixes, fmt.Sprintf("%s: %s", label, r.Description))
+			}
+		}
+		if len(fr.Fixes) == 0 && len(fr.Problems) == 0 {
+			continue
+		}
+		fr.Content = out
+		res.Files = append(res.Files, fr)
+	}
+	return res, nil
+}
+
+// ApplyRepair writes a proposed repair back to the filesystem it was read from. It commits
+// like Sync (staged, journaled and renamed into place), so the file keeps its mode and an
+// interruption can be resumed or reverted.
+func ApplyRepair(fr FileRepair) error {
+	if len(fr.Fixes) == 0 {
+		return nil
+	}
+	if err := checkNoJournal(fr.fsys); err != nil {
+		return err
+	}
+	return commitOutputs(fr.fsys, map[string][]byte{fr.name: []byte(fr.Content)})
+}
@@ -0,0 +1,121 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRepair_RestoresMissingEndMarkerAndKeepsAddenda(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+	target := filepath.Join(tmp, "target")
+	profileDir := filepath.Join(srcRepo, "Governance", "Profiles", "p")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(profileDir, "rules.md"), "# Rules\n\n- keep gates green\n- review every change\n")
+	writeFile(t, filepath.Join(profileDir, "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: p
+documents:
+  - output: Constitution.md
+    fragments: [./rules.md]
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+
+	if _, err := Init(ctx, InitOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p"}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	docPath := filepath.Join(target, "Constitution.md")
+	doc := readFile(t, docPath) + "- review every change, twice\n"
+	damaged := strings.Replace(doc, "<!-- GOV:END id=doc-constitution -->\n", "", 1)
+	writeFile(t, docPath, damaged)
+
+	if _, err := Sync(ctx, SyncOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p"}); err == nil {
+		t.Fatalf("expected sync to fail on the damaged document")
+	}
+
+	opts := RepairOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p"}
+	res, err := Repair(ctx, opts)
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+	if len(res.Files) != 1 || len(res.Files[0].Fixes) != 1 || len(res.Files[0].Problems) != 0 {
+		t.Fatalf("unexpected repair result: %+v", res)
+	}
+	if !strings.HasPrefix(res.Files[0].Fixes[0], "Constitution.md: missing END marker; block now spans lines ") {
+		t.Fatalf("unexpected fix: %q", res.Files[0].Fixes[0])
+	}
+	// Proposing a repair does not write anything.
+	if readFile(t, docPath) != damaged {
+		t.Fatalf("Repair must not modify files")
+	}
+	// Like sync, a repair waits for an interrupted sync to be resumed or reverted.
+	journalPath := filepath.Join(target, filepath.FromSlash(SyncJournal))
+	writeFile(t, journalPath, `{"ready":false,"files":[]}`)
+	if err := ApplyRepair(res.Files[0]); !errors.Is(err, ErrInterruptedSync) {
+		t.Fatalf("expected ErrInterruptedSync, got %v", err)
+	}
+	if err := os.Remove(journalPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(docPath, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyRepair(res.Files[0]); err != nil {
+		t.Fatalf("ApplyRepair: %v", err)
+	}
+	if got := readFile(t, docPath); got != doc {
+		t.Fatalf("unexpected repaired doc:\n%s\nwant:\n%s", got, doc)
+	}
+	if info, err := os.Stat(docPath); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected the repaired doc to keep mode 0600, got %v %v", info.Mode(), err)
+	}
+	if _, err := os.Stat(journalPath); !os.IsNotExist(err) {
+		t.Fatalf("expected the commit journal to be removed, got %v", err)
+	}
+
+	vr, err := Verify(ctx, VerifyOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p"})
+	if err != nil || !vr.OK {
+		t.Fatalf("expected verify ok after repair, got %+v err=%v", vr, err)
+	}
+	res, err = Repair(ctx, opts)
+	if err != nil || len(res.Files) != 0 {
+		t.Fatalf("expected nothing left to repair, got %+v err=%v", res, err)
+	}
+}
+
+func TestRepair_ReportsBlocksThatCannotBeLocated(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+	target := filepath.Join(tmp, "target")
+	profileDir := filepath.Join(srcRepo, "Governance", "Profiles", "p")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(profileDir, "rules.md"), "RULES\n")
+	writeFile(t, filepath.Join(profileDir, "profile.yaml"), "schemaVersion: 1\nid: p\ndocuments:\n  - output: Constitution.md\n    fragments: [./rules.md]\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+
+	writeFile(t, filepath.Join(target, "Constitution.md"), "# Our constitution\n\nNothing governed here.\n")
+	res, err := Repair(ctx, RepairOptions{RepoRoot: target, CacheDir: cache, SourceRepo: srcRepo, SourceRef: "HEAD", ProfileID: "p"})
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+	if len(res.Files) != 1 || len(res.Files[0].Fixes) != 0 || len(res.Files[0].Problems) != 1 ||
+		!strings.Contains(res.Files[0].Problems[0], "could not locate the block's content") {
+		t.Fatalf("unexpected repair result: %+v", res)
+	}
+}
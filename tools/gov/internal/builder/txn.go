@@ -0,0 +1,246 @@
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+)
+
+// SyncJournal is where Sync records an in-progress commit, relative to the repo root.
+const SyncJournal = ".governance/sync-journal.json"
+
+const (
+	stagedSuffix = ".gov-sync-new"
+	backupSuffix = ".gov-sync-bak"
+)
+
+// ErrInterruptedSync is returned by Sync when a previous run left its journal behind.
+var ErrInterruptedSync = errors.New("a previous sync was interrupted")
+
+// renameFS is an FS whose files can be renamed and removed. Sync commits through it
+// atomically; other filesystems get the outputs written once every document succeeded.
+type renameFS interface {
+	FS
+	Rename(oldname, newname string) error
+	Remove(name string) error
+}
+
+// journal lists the files of a sync commit. Each target has its new content staged next
+// to it and its previous content backed up, so the commit can be finished or undone. It
+// is written before anything is staged, so every file a run leaves behind is listed.
+type journal struct {
+	// Ready is set once every file is staged; until then the sync can only be reverted.
+	Ready bool           `json:"ready"`
+	Files []journalEntry `json:"files"`
+}
+
+type journalEntry struct {
+	Path   string `json:"path"`
+	Staged string `json:"staged"`
+	Backup string `json:"backup"`
+	// New marks a target that did not exist before the sync.
+	New bool `json:"new,omitempty"`
+}
+
+// commitOutputs writes outputs (name -> content) to fsys all-or-nothing as far as the
+// filesystem allows: nothing is replaced until every file is staged, a failed replace is
+// rolled back, and an interruption leaves SyncJournal for ResumeSync or RevertSync.
+func commitOutputs(fsys FS, outputs map[string][]byte) error {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rfs, ok := fsys.(renameFS)
+	if !ok {
+		for _, name := range names {
+			if err := writeTarget(fsys, name, outputs[name]); err != nil {
+				return fmt.Errorf("write %s: %w", name, err)
+			}
+		}
+		return nil
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	var j journal
+	for _, name := range names {
+		j.Files = append(j.Files, journalEntry{Path: name, Staged: name + stagedSuffix, Backup: name + backupSuffix, New: !exists(rfs, name)})
+	}
+	abort := func(err error) error {
+		for _, e := range j.Files {
+			_ = rfs.Remove(e.Staged)
+			_ = rfs.Remove(e.Backup)
+		}
+		_ = rfs.Remove(SyncJournal)
+		return err
+	}
+	if err := writeJournal(rfs, j); err != nil {
+		return abort(err)
+	}
+	for _, e := range j.Files {
+		// Both copies keep the target's mode, so renaming either over it preserves it.
+		perm := fs.FileMode(0o644)
+		old, err := rfs.ReadFile(e.Path)
+		if err == nil {
+			var info fs.FileInfo
+			if info, err = fs.Stat(rfs, e.Path); err == nil {
+				perm = info.Mode().Perm()
+				err = stageFile(rfs, e.Backup, old, perm)
+			}
+		} else if errors.Is(err, fs.ErrNotExist) {
+			err = nil
+		}
+		if err == nil {
+			err = stageFile(rfs, e.Staged, outputs[e.Path], perm)
+		}
+		if err != nil {
+			return abort(fmt.Errorf("stage %s: %w", e.Path, err))
+		}
+	}
+	j.Ready = true
+	if err := writeJournal(rfs, j); err != nil {
+		return abort(err)
+	}
+
+	for _, e := range j.Files {
+		if err := rfs.Rename(e.Staged, e.Path); err != nil {
+			err = fmt.Errorf("replace %s: %w", e.Path, err)
+			if _, rerr := RevertSync(rfs); rerr != nil {
+				return fmt.Errorf("%w; rollback failed: %v (see %s)", err, rerr, SyncJournal)
+			}
+			return err
+		}
+	}
+	return finishJournal(rfs, j)
+}
+
+func writeJournal(rfs renameFS, j journal) error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeTarget(rfs, SyncJournal, append(data, '\n')); err != nil {
+		return fmt.Errorf("write %s: %w", SyncJournal, err)
+	}
+	return nil
+}
+
+// stageFile writes data to name with perm, first removing a stale file left by an
+// earlier run (WriteFile keeps an existing file's mode).
+func stageFile(rfs renameFS, name string, data []byte, perm fs.FileMode) error {
+	if err := rfs.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := rfs.MkdirAll(path.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return rfs.WriteFile(name, data, perm)
+}
+
+// ResumeSync completes a sync interrupted after staging: staged files left in place
+// replace their targets. It returns how many files it replaced; without a journal it
+// does nothing. A sync interrupted while staging cannot be resumed, only reverted.
+func ResumeSync(fsys FS) (int, error) {
+	rfs, j, err := readJournal(fsys)
+	if err != nil || rfs == nil {
+		return 0, err
+	}
+	if !j.Ready {
+		return 0, fmt.Errorf("%s: the sync was interrupted before every file was staged; revert it", SyncJournal)
+	}
+	n := 0
+	for _, e := range j.Files {
+		if !exists(rfs, e.Staged) {
+			continue // already replaced
+		}
+		if err := rfs.Rename(e.Staged, e.Path); err != nil {
+			return n, fmt.Errorf("replace %s: %w", e.Path, err)
+		}
+		n++
+	}
+	return n, finishJournal(rfs, j)
+}
+
+// RevertSync undoes an interrupted sync, restoring every replaced file from its backup
+// (files that did not exist before are removed) and deleting staged files. It returns how
+// many files it restored or removed; without a journal it does nothing.
+func RevertSync(fsys FS) (int, error) {
+	rfs, j, err := readJournal(fsys)
+	if err != nil || rfs == nil {
+		return 0, err
+	}
+	n := 0
+	for _, e := range j.Files {
+		if err := rfs.Remove(e.Staged); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return n, err
+		}
+		switch {
+		case exists(rfs, e.Backup):
+			if err := rfs.Rename(e.Backup, e.Path); err != nil {
+				return n, fmt.Errorf("restore %s: %w", e.Path, err)
+			}
+		case e.New:
+			err := rfs.Remove(e.Path)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return n, fmt.Errorf("remove %s: %w", e.Path, err)
+			}
+		default:
+			continue // interrupted before its backup was written, so never replaced
+		}
+		n++
+	}
+	if err := rfs.Remove(SyncJournal); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return n, err
+	}
+	return n, nil
+}
+
+// checkNoJournal fails when an interrupted sync must be resumed or reverted first.
+func checkNoJournal(fsys FS) error {
+	if exists(fsys, SyncJournal) {
+		return fmt.Errorf("%w (%s exists); resume or revert it before syncing again", ErrInterruptedSync, SyncJournal)
+	}
+	return nil
+}
+
+func readJournal(fsys FS) (renameFS, journal, error) {
+	data, err := fsys.ReadFile(SyncJournal)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, journal{}, nil
+	}
+	if err != nil {
+		return nil, journal{}, err
+	}
+	var j journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, journal{}, fmt.Errorf("%s: %w", SyncJournal, err)
+	}
+	rfs, ok := fsys.(renameFS)
+	if !ok {
+		return nil, journal{}, fmt.Errorf("%s: filesystem cannot rename files", SyncJournal)
+	}
+	return rfs, j, nil
+}
+
+func finishJournal(rfs renameFS, j journal) error {
+	for _, e := range j.Files {
+		if err := rfs.Remove(e.Backup); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return rfs.Remove(SyncJournal)
+}
+
+func exists(fsys FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
@@ -0,0 +1,305 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/source"
+)
+
+// twoDocFetch serves an in-memory two-document profile whose fragments say "<doc> <ref>".
+func twoDocFetch(t *testing.T) source.Fetcher {
+	dir := filepath.Join(t.TempDir(), "checkout")
+	manifest := strings.TrimSpace(`
+schemaVersion: 1
+id: p
+documents:
+  - output: A.md
+    fragments:
+      - ./A.md
+  - output: B.md
+    fragments:
+      - ./B.md
+`) + "\n"
+	return func(_ context.Context, opts source.FetchOptions) (source.ResolvedSource, error) {
+		files := fstest.MapFS{
+			"Governance/Profiles/p/profile.yaml": {Data: []byte(manifest)},
+			"Governance/Profiles/p/A.md":         {Data: []byte("A " + opts.Ref + "\n")},
+			"Governance/Profiles/p/B.md":         {Data: []byte("B " + opts.Ref + "\n")},
+		}
+		return source.ResolvedSource{CheckoutDir: dir, SourceRepo: opts.RepoURL, SourceRef: opts.Ref, SourceCommit: opts.Ref, FS: files}, nil
+	}
+}
+
+// faultFS fails (or, with crash, panics as if the process died) on the Nth rename, and
+// panics when writing crashOn.
+type faultFS struct {
+	renameFS
+	renames, failAt int
+	crash           bool
+	crashOn         string
+}
+
+type crashed struct{}
+
+func (f *faultFS) Rename(oldname, newname string) error {
+	f.renames++
+	if f.renames == f.failAt {
+		if f.crash {
+			panic(crashed{})
+		}
+		return errors.New("disk full")
+	}
+	return f.renameFS.Rename(oldname, newname)
+}
+
+func (f *faultFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if name == f.crashOn {
+		panic(crashed{})
+	}
+	return f.renameFS.WriteFile(name, data, perm)
+}
+
+func snapshot(m *MemFS) map[string]string {
+	out := map[string]string{}
+	for _, name := range m.Names() {
+		data, _ := m.ReadFile(name)
+		out[name] = string(data)
+	}
+	return out
+}
+
+func TestSync_IsAllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	fetch := twoDocFetch(t)
+	m := NewMemFS()
+	if _, err := Init(ctx, InitOptions{FS: m, SourceRepo: "mem", SourceRef: "v1", ProfileID: "p", Fetch: fetch}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	synced := func(fsys FS) error {
+		_, err := Sync(ctx, SyncOptions{FS: fsys, SourceRepo: "mem", SourceRef: "v2", ProfileID: "p", Fetch: fetch})
+		return err
+	}
+	assertUnchanged := func(want map[string]string) {
+		t.Helper()
+		got := snapshot(m)
+		if len(got) != len(want) {
+			t.Fatalf("unexpected files: %v", m.Names())
+		}
+		for name, data := range want {
+			if got[name] != data {
+				t.Fatalf("%s changed:\n%s", name, got[name])
+			}
+		}
+	}
+
+	// A document that cannot be updated leaves every document untouched.
+	b, _ := m.ReadFile("B.md")
+	broken := strings.Replace(string(b), "<!-- GOV:END id=doc-b -->", "", 1)
+	if broken == string(b) {
+		t.Fatalf("unexpected B.md:\n%s", b)
+	}
+	if err := m.WriteFile("B.md", []byte(broken), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	before := snapshot(m)
+	if err := synced(m); err == nil || !strings.Contains(err.Error(), "B.md") {
+		t.Fatalf("expected B.md error, got %v", err)
+	}
+	assertUnchanged(before)
+	if err := m.WriteFile("B.md", b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	before = snapshot(m)
+
+	// A failed replace is rolled back.
+	if err := synced(&faultFS{renameFS: m, failAt: 2}); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected rename error, got %v", err)
+	}
+	assertUnchanged(before)
+
+	// An interrupted run leaves a journal; Sync refuses until it is reverted or resumed.
+	interrupt := func() {
+		t.Helper()
+		defer func() {
+			if _, ok := recover().(crashed); !ok {
+				t.Fatalf("expected simulated crash")
+			}
+		}()
+		_ = synced(&faultFS{renameFS: m, failAt: 2, crash: true})
+	}
+	interrupt()
+	if a, _ := m.ReadFile("A.md"); !strings.Contains(string(a), "A v2") {
+		t.Fatalf("expected A.md replaced before the crash:\n%s", a)
+	}
+	if err := synced(m); !errors.Is(err, ErrInterruptedSync) {
+		t.Fatalf("expected ErrInterruptedSync, got %v", err)
+	}
+	if n, err := RevertSync(m); err != nil || n != 2 {
+		t.Fatalf("RevertSync: %d, %v", n, err)
+	}
+	assertUnchanged(before)
+
+	// A run interrupted while staging can only be reverted; every file it left is removed.
+	func() {
+		defer func() {
+			if _, ok := recover().(crashed); !ok {
+				t.Fatalf("expected simulated crash")
+			}
+		}()
+		_ = synced(&faultFS{renameFS: m, crashOn: "B.md" + stagedSuffix})
+	}()
+	if !exists(m, "A.md"+stagedSuffix) || !exists(m, "A.md"+backupSuffix) {
+		t.Fatalf("expected A.md staged before the crash, got %v", m.Names())
+	}
+	if _, err := ResumeSync(m); err == nil || !strings.Contains(err.Error(), "revert it") {
+		t.Fatalf("expected ResumeSync to refuse, got %v", err)
+	}
+	if n, err := RevertSync(m); err != nil || n != 2 {
+		t.Fatalf("RevertSync: %d, %v", n, err)
+	}
+	assertUnchanged(before)
+
+	interrupt()
+	if n, err := ResumeSync(m); err != nil || n != 1 {
+		t.Fatalf("ResumeSync: %d, %v", n, err)
+	}
+	if strings.Join(m.Names(), ",") != "A.md,B.md" {
+		t.Fatalf("expected journal and staged files removed, got %v", m.Names())
+	}
+	a, _ := m.ReadFile("A.md")
+	b, _ = m.ReadFile("B.md")
+	if !strings.Contains(string(a), "A v2") || !strings.Contains(string(b), "B v2") {
+		t.Fatalf("expected both documents synced:\n%s\n%s", a, b)
+	}
+	if n, err := ResumeSync(m); err != nil || n != 0 {
+		t.Fatalf("ResumeSync without journal: %d, %v", n, err)
+	}
+}
+
+func TestSync_PreservesFileModeOnDisk(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "checkout")
+	fetch := func(_ context.Context, opts source.FetchOptions) (source.ResolvedSource, error) {
+		files := fstest.MapFS{
+			"Governance/Profiles/p/profile.yaml": {Data: []byte("schemaVersion: 1\nid: p\ndocuments:\n  - output: run.sh\n    fragments: [./run.sh]\n")},
+			"Governance/Profiles/p/run.sh":       {Data: []byte("echo " + opts.Ref + "\n")},
+		}
+		return source.ResolvedSource{CheckoutDir: dir, SourceRepo: opts.RepoURL, SourceRef: opts.Ref, SourceCommit: opts.Ref, FS: files}, nil
+	}
+	target := t.TempDir()
+	script := filepath.Join(target, "run.sh")
+	writeFile(t, script, "#!/bin/sh\n")
+	if err := os.Chmod(script, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Init(ctx, InitOptions{RepoRoot: target, SourceRepo: "mem", SourceRef: "v1", ProfileID: "p", Fetch: fetch}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	assertMode := func(want string) {
+		t.Helper()
+		info, err := os.Stat(script)
+		if err != nil || info.Mode().Perm() != 0o755 {
+			t.Fatalf("expected run.sh to stay 0755, got %v %v", info.Mode(), err)
+		}
+		if b := readFile(t, script); !strings.Contains(b, want) {
+			t.Fatalf("expected %q in run.sh:\n%s", want, b)
+		}
+	}
+	assertMode("echo v1")
+
+	synced := func(fsys FS) error {
+		_, err := Sync(ctx, SyncOptions{FS: fsys, SourceRepo: "mem", SourceRef: "v2", ProfileID: "p", Fetch: fetch})
+		return err
+	}
+	// An interrupted sync is reverted by renaming the backup over the target.
+	func() {
+		defer func() {
+			if _, ok := recover().(crashed); !ok {
+				t.Fatalf("expected simulated crash")
+			}
+		}()
+		_ = synced(&faultFS{renameFS: DirFS(target).(renameFS), failAt: 1, crash: true})
+	}()
+	if n, err := RevertSync(DirFS(target)); err != nil || n != 1 {
+		t.Fatalf("RevertSync: %d, %v", n, err)
+	}
+	assertMode("echo v1")
+
+	if err := synced(DirFS(target)); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	assertMode("echo v2")
+}
+
+func TestSyncApplications_CommitsAllApplicationsTogether(t *testing.T) {
+	ctx := context.Background()
+	fetch := twoDocFetch(t)
+	m := NewMemFS()
+	apps := func(ref string) []SyncOptions {
+		var out []SyncOptions
+		for _, root := range []string{"api", "web"} {
+			out = append(out, SyncOptions{FS: m, DocsRoot: root, SourceRepo: "mem", SourceRef: ref, ProfileID: "p", Fetch: fetch})
+		}
+		return out
+	}
+	for _, o := range apps("v1") {
+		if _, err := Init(ctx, InitOptions{FS: m, DocsRoot: o.DocsRoot, SourceRepo: "mem", SourceRef: "v1", ProfileID: "p", Fetch: fetch}); err != nil {
+			t.Fatalf("Init: %v", err)
+		}
+	}
+
+	// The second application cannot be updated, so the first is not updated either.
+	b, _ := m.ReadFile("web/B.md")
+	if err := m.WriteFile("web/B.md", []byte(strings.Replace(string(b), "<!-- GOV:END id=doc-b -->", "", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	before := snapshot(m)
+	_, err := SyncApplications(ctx, apps("v2"))
+	var se *SyncError
+	if !errors.As(err, &se) || len(se.Errs) != 2 || se.Errs[0] != nil || se.Errs[1] == nil || !strings.Contains(se.Errs[1].Error(), "web/B.md") {
+		t.Fatalf("expected web/B.md error for the second application, got %v", err)
+	}
+	got := snapshot(m)
+	for name, data := range before {
+		if got[name] != data {
+			t.Fatalf("%s changed:\n%s", name, got[name])
+		}
+	}
+
+	if err := m.WriteFile("web/B.md", b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := SyncApplications(ctx, apps("v2"))
+	if err != nil || len(res) != 2 || res[0].DocsUpdated != 2 || res[1].DocsUpdated != 2 {
+		t.Fatalf("SyncApplications: %+v, %v", res, err)
+	}
+	for _, name := range []string{"api/A.md", "web/B.md"} {
+		if data, _ := m.ReadFile(name); !strings.Contains(string(data), " v2") {
+			t.Fatalf("expected %s synced:\n%s", name, data)
+		}
+	}
+
+	if _, err := SyncApplications(ctx, []SyncOptions{{RepoRoot: "a"}, {RepoRoot: "b"}}); err == nil || !strings.Contains(err.Error(), "differ") {
+		t.Fatalf("expected repo root error, got %v", err)
+	}
+
+	// Applications writing the same files are rejected even when nothing would change.
+	overlapping := append(apps("v2"), apps("v2")[0])
+	before = snapshot(m)
+	if _, err := SyncApplications(ctx, overlapping); !errors.Is(err, ErrOverlappingOutputs) || !strings.Contains(err.Error(), "api/A.md is written by both p and p") {
+		t.Fatalf("expected overlapping outputs, got %v", err)
+	}
+	got = snapshot(m)
+	for name, data := range before {
+		if got[name] != data {
+			t.Fatalf("%s changed:\n%s", name, got[name])
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_BuildArchiveIsReproducible(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	target := filepath.Join(tmp, "target")
+	cache := filepath.Join(tmp, "cache")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "backend-go-hex", "Rules.Profile.md"), "RULES\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "backend-go-hex", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: backend-go-hex
+documents:
+  - output: Rules.md
+    fragments:
+      - ./Rules.Profile.md
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+	mustRun(t, srcRepo, "git", "tag", "v0.0.1")
+
+	cfgPath := filepath.Join(target, ".governance", "config.yaml")
+	writeFile(t, cfgPath, strings.TrimSpace(`
+schemaVersion: 1
+source:
+  repo: `+srcRepo+`
+  ref: "v0.0.1"
+  profile: "backend-go-hex"
+paths:
+  docsRoot: "docs"
+  cacheDir: `+cache+`
+`)+"\n")
+
+	build := func(name string) []byte {
+		t.Helper()
+		out := filepath.Join(tmp, "dist", name)
+		var stdout, stderr bytes.Buffer
+		if code := Run([]string{"agent-gov", "build", "--config", cfgPath, "--out", out}, &stdout, &stderr); code != 0 {
+			t.Fatalf("build: expected 0, got %d\n%s", code, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), "wrote "+out) {
+			t.Fatalf("unexpected output:\n%s", stdout.String())
+		}
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatalf("read archive: %v", err)
+		}
+		return data
+	}
+	first := build("a.tar.gz")
+	if !bytes.Equal(first, build("b.tar.gz")) {
+		t.Fatalf("expected identical archives")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(first))
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("tar: %v", err)
+		}
+		data, _ := io.ReadAll(tr)
+		files[hdr.Name] = string(data)
+		names = append(names, hdr.Name)
+	}
+	if strings.Join(names, ",") != "agent-gov-bundle.json,docs/Rules.md" {
+		t.Fatalf("unexpected entries: %v", names)
+	}
+	commit := strings.TrimSpace(string(mustRunOut(t, srcRepo, "git", "rev-parse", "HEAD")))
+	manifest := files["agent-gov-bundle.json"]
+	for _, want := range []string{`"profile": "backend-go-hex"`, `"sourceRef": "v0.0.1"`, `"sourceCommit": "` + commit + `"`, `"path": "docs/Rules.md"`} {
+		if !strings.Contains(manifest, want) {
+			t.Fatalf("manifest missing %s:\n%s", want, manifest)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "dist", "a.tar.gz", "docs")); err == nil {
+		t.Fatalf("archive output must not be treated as a directory")
+	}
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/commitmsg"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/config"
+)
+
+func runCommitMsg(subArgs []string, stdout, stderr io.Writer) int {
+	if len(subArgs) < 1 || subArgs[0] != "check" {
+		fmt.Fprintln(stderr, "usage: agent-gov commitmsg check [--config PATH] <file>")
+		return 2
+	}
+	fs := flag.NewFlagSet("commitmsg check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", defaultConfigPath, "path to .governance/config.yaml")
+	if err := fs.Parse(subArgs[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, "commitmsg check requires exactly one message file")
+		return 2
+	}
+	return checkCommitMsgFile(*configPath, subArgs[1:], fs.Arg(0), stdout, stderr)
+}
+
+func checkCommitMsgFile(configPath string, args []string, msgPath string, stdout, stderr io.Writer) int {
+	resolvedConfigPath, _, err := resolveConfigPath(configPath, args)
+	if err != nil {
+		fmt.Fprintf(stderr, "config discovery error: %v\n", err)
+		return 2
+	}
+	cfg, err := config.Load(resolvedConfigPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "config error: %v\n", err)
+		return 2
+	}
+	msg, err := os.ReadFile(msgPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "commitmsg error: %v\n", err)
+		return 2
+	}
+
+	policy := commitmsg.Policy{
+		Convention:        cfg.Commits.Convention,
+		Types:             cfg.Commits.Types,
+		MatchBranchType:   cfg.Commits.MatchBranchType,
+		RequireCheckpoint: cfg.Commits.RequirePlanCheckpoint,
+	}
+	if policy.MatchBranchType || policy.RequireCheckpoint {
+		repoRoot := repoRootForConfig(resolvedConfigPath)
+		branch, err := gitCurrentBranch(repoRoot)
+		if err != nil {
+			fmt.Fprintf(stderr, "commitmsg error: git branch: %v\n", err)
+			return 2
+		}
+		if isDetachedHead(branch) {
+			// Rebases and other detached-HEAD commits have no branch type or plan to check against.
+			policy.MatchBranchType, policy.RequireCheckpoint = false, false
+		}
+		policy.BranchType = commitmsg.BranchType(branch)
+		if policy.RequireCheckpoint {
+			planPath, ok, err := findPlanForBranch(filepath.Join(repoRoot, "Docs", "Plans"), branch)
+			if err != nil {
+				fmt.Fprintf(stderr, "commitmsg error: plan scan: %v\n", err)
+				return 2
+			}
+			if !ok {
+				fmt.Fprintf(stderr, "commit message rejected: no plan found for branch %q under Docs/Plans\n", branch)
+				return 1
+			}
+			plan, err := os.ReadFile(planPath)
+			if err != nil {
+				fmt.Fprintf(stderr, "commitmsg error: read plan: %v\n", err)
+				return 2
+			}
+			policy.Checkpoints = commitmsg.PlanCheckpoints(string(plan))
+		}
+	}
+
+	problems := commitmsg.Check(string(msg), policy)
+	if len(problems) > 0 {
+		fmt.Fprintf(stderr, "commit message rejected: %d issue(s)\n", len(problems))
+		for _, p := range problems {
+			fmt.Fprintf(stderr, "- %s\n", p)
+		}
+		return 1
+	}
+	fmt.Fprintln(stdout, "ok")
+	return 0
+}
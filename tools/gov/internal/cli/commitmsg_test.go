@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommitMsgCheck_RequiresPlanCheckpointAndBranchType(t *testing.T) {
+	tmp := t.TempDir()
+	repo := filepath.Join(tmp, "repo")
+	mustRun(t, tmp, "git", "init", repo)
+	mustRun(t, repo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, repo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(repo, ".governance", "config.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+source:
+  repo: .
+  ref: "HEAD"
+  profile: "backend-go-hex"
+commits:
+  convention: conventional
+  matchBranchType: true
+  requirePlanCheckpoint: true
+`)+"\n")
+	writeFile(t, filepath.Join(repo, "Docs", "Plans", "feat", "app-thing.md"), strings.TrimSpace(`
+---
+branch: feat/app-thing
+status: active
+---
+
+## Checkpoints
+
+- [ ] Checkpoint 1 — parser
+- [ ] Final checkpoint — PR wrap-up
+`)+"\n")
+	writeFile(t, filepath.Join(repo, "README.md"), "x\n")
+	mustRun(t, repo, "git", "add", ".")
+	mustRun(t, repo, "git", "commit", "-m", "init")
+	mustRun(t, repo, "git", "checkout", "-b", "feat/app-thing")
+
+	oldCwd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldCwd) }()
+	_ = os.Chdir(repo)
+
+	msgPath := filepath.Join(tmp, "msg")
+	cases := []struct {
+		msg  string
+		code int
+		want string
+	}{
+		{msg: "feat(app): add parser\n\nCheckpoint 1\n", code: 0},
+		{msg: "fix(app): add parser\n\nCheckpoint 1\n", code: 1, want: "does not match branch type"},
+		{msg: "feat(app): add parser\n", code: 1, want: "must reference a plan checkpoint"},
+		{msg: "feat(app): add parser\n\nCheckpoint 3\n", code: 1, want: "not declared"},
+	}
+	for _, tc := range cases {
+		writeFile(t, msgPath, tc.msg)
+		var out, errOut bytes.Buffer
+		code := Run([]string{"agent-gov", "commitmsg", "check", msgPath}, &out, &errOut)
+		if code != tc.code {
+			t.Fatalf("msg=%q expected %d, got %d stderr=%s", tc.msg, tc.code, code, errOut.String())
+		}
+		if tc.want != "" && !strings.Contains(errOut.String(), tc.want) {
+			t.Fatalf("msg=%q expected %q in stderr, got:\n%s", tc.msg, tc.want, errOut.String())
+		}
+	}
+
+	// A branch without a plan is rejected when checkpoints are required.
+	mustRun(t, repo, "git", "checkout", "-b", "feat/app-other")
+	writeFile(t, msgPath, "feat: x\n\nCheckpoint 1\n")
+	var out, errOut bytes.Buffer
+	if code := Run([]string{"agent-gov", "commitmsg", "check", msgPath}, &out, &errOut); code != 1 {
+		t.Fatalf("expected 1, got %d stderr=%s", code, errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "no plan found") {
+		t.Fatalf("expected missing plan error, got:\n%s", errOut.String())
+	}
+}
+
+func TestCommitMsgCheck_UsageErrors(t *testing.T) {
+	cases := [][]string{
+		{"agent-gov", "commitmsg"},
+		{"agent-gov", "commitmsg", "lint", "x"},
+		{"agent-gov", "commitmsg", "check"},
+	}
+	for _, args := range cases {
+		var out, errOut bytes.Buffer
+		if code := Run(args, &out, &errOut); code != 2 {
+			t.Fatalf("args=%v expected 2, got %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_DiffShowsPendingSyncWithoutWriting(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	target := filepath.Join(tmp, "target")
+	cache := filepath.Join(tmp, "cache")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "backend-go-hex", "Rules.Profile.md"), "RULES v1\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "backend-go-hex", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: backend-go-hex
+documents:
+  - output: Rules.md
+    fragments:
+      - ./Rules.Profile.md
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+	mustRun(t, srcRepo, "git", "tag", "v0.0.1")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "backend-go-hex", "Rules.Profile.md"), "RULES v2\n")
+	mustRun(t, srcRepo, "git", "commit", "-am", "v2")
+	mustRun(t, srcRepo, "git", "tag", "v0.0.2")
+
+	cfgPath := filepath.Join(target, ".governance", "config.yaml")
+	writeConfig := func(ref string) {
+		writeFile(t, cfgPath, strings.TrimSpace(`
+schemaVersion: 1
+source:
+  repo: `+srcRepo+`
+  ref: "`+ref+`"
+  profile: "backend-go-hex"
+paths:
+  docsRoot: "."
+  cacheDir: `+cache+`
+`)+"\n")
+	}
+	writeConfig("v0.0.1")
+
+	var out, errOut bytes.Buffer
+	if code := Run([]string{"agent-gov", "init", "--config", cfgPath}, &out, &errOut); code != 0 {
+		t.Fatalf("init: expected 0, got %d\n%s", code, errOut.String())
+	}
+	out.Reset()
+	if code := Run([]string{"agent-gov", "diff", "--config", cfgPath}, &out, &errOut); code != 0 || out.String() != "no changes\n" {
+		t.Fatalf("diff: expected no changes, got %d\n%s%s", code, out.String(), errOut.String())
+	}
+
+	writeConfig("v0.0.2")
+	before, err := os.ReadFile(filepath.Join(target, "Rules.md"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	out.Reset()
+	if code := Run([]string{"agent-gov", "diff", "--config", cfgPath}, &out, &errOut); code != 0 {
+		t.Fatalf("diff: expected 0, got %d\n%s", code, errOut.String())
+	}
+	if !strings.HasPrefix(out.String(), "--- a/Rules.md\n+++ b/Rules.md\n") || !strings.Contains(out.String(), "\n-RULES v1\n") || !strings.Contains(out.String(), "\n+RULES v2\n") {
+		t.Fatalf("unexpected diff output:\n%s", out.String())
+	}
+	after, _ := os.ReadFile(filepath.Join(target, "Rules.md"))
+	if !bytes.Equal(before, after) {
+		t.Fatalf("diff must not modify files")
+	}
+}
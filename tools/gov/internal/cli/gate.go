@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/govkit"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/config"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/coverage"
+)
+
+func runGate(subArgs []string, stdout, stderr io.Writer) int {
+	if len(subArgs) < 1 {
+		fmt.Fprintln(stderr, "gate requires a gate name: coverage")
+		return 2
+	}
+	switch subArgs[0] {
+	case "coverage":
+		return runCoverageGate(subArgs[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown gate: %s\n", subArgs[0])
+		return 2
+	}
+}
+
+func runCoverageGate(subArgs []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gate coverage", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", defaultConfigPath, "path to .governance/config.yaml")
+	profilePath := fs.String("profile", "", "coverage profile (Go cover profile or lcov tracefile)")
+	format := fs.String("format", coverage.FormatAuto, "coverage format: auto, go, lcov")
+	threshold := fs.Float64("threshold", 0, "minimum total coverage percentage (overrides the profile manifest threshold; its exclusions still apply)")
+	application := fs.String("application", "", "profile application whose coverage settings apply (required when the config has several)")
+	if err := fs.Parse(subArgs); err != nil {
+		return 2
+	}
+	if strings.TrimSpace(*profilePath) == "" {
+		fmt.Fprintln(stderr, "--profile is required for gate coverage")
+		return 2
+	}
+
+	resolvedConfigPath, autoDiscovered, err := resolveConfigPath(*configPath, subArgs)
+	if err != nil {
+		fmt.Fprintf(stderr, "config discovery error: %v\n", err)
+		return 2
+	}
+	if autoDiscovered {
+		fmt.Fprintf(stderr, "using config: %s\n", resolvedConfigPath)
+	}
+	cfg, err := config.Load(resolvedConfigPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "config error: %v\n", err)
+		return 2
+	}
+	cacheDir, err := cfg.CacheDir()
+	if err != nil {
+		fmt.Fprintf(stderr, "cache dir error: %v\n", err)
+		return 2
+	}
+	// The threshold and exclusions come from the selected profile application; an
+	// explicit --threshold (even 0) overrides only the threshold.
+	app, err := selectApplication(cfg.ProfileApplications(), *application)
+	if err != nil {
+		fmt.Fprintf(stderr, "config error: %v\n", err)
+		return 2
+	}
+	m, _, err := govkit.LoadProfile(context.Background(),
+		govkit.WithSource(resolveRepoPathIfLocal(resolvedConfigPath, app.Repo), app.Ref, app.Profile),
+		govkit.WithCacheDir(cacheDir),
+	)
+	if err != nil {
+		fmt.Fprintf(stderr, "gate coverage failed: %v\n", err)
+		return 1
+	}
+
+	minPct := m.Coverage.Threshold
+	thresholdSet := false
+	fs.Visit(func(f *flag.Flag) { thresholdSet = thresholdSet || f.Name == "threshold" })
+	if thresholdSet {
+		if *threshold < 0 || *threshold > 100 {
+			fmt.Fprintf(stderr, "--threshold must be between 0 and 100, got %v\n", *threshold)
+			return 2
+		}
+		minPct = *threshold
+	} else if minPct <= 0 {
+		fmt.Fprintf(stderr, "no coverage threshold declared in profile %q (set coverage.threshold or pass --threshold)\n", m.ID)
+		return 2
+	}
+
+	raw, err := os.ReadFile(*profilePath)
+	if err != nil {
+		fmt.Fprintf(stderr, "gate coverage error: %v\n", err)
+		return 2
+	}
+	report, err := coverage.Parse(raw, *format)
+	if err != nil {
+		fmt.Fprintf(stderr, "gate coverage error: %s: %v\n", *profilePath, err)
+		return 2
+	}
+	excludes := append(append([]string{}, m.Coverage.Exclude...), cfg.Coverage.Exclude...)
+	summary := coverage.Summarize(report.Exclude(excludes))
+	if summary.Total == 0 {
+		fmt.Fprintln(stderr, "gate coverage failed: no coverable statements after exclusions")
+		return 1
+	}
+
+	shortfalls := summary.Shortfalls(minPct)
+	if len(shortfalls) > 0 {
+		fmt.Fprintf(stdout, "packages below %.1f%%:\n", minPct)
+		for _, p := range shortfalls {
+			fmt.Fprintf(stdout, "- %s %.1f%% (%d/%d)\n", p.Package, p.Percent(), p.Covered, p.Total)
+		}
+	}
+	if summary.Percent() < minPct {
+		fmt.Fprintf(stderr, "coverage %.1f%% is below %.1f%%\n", summary.Percent(), minPct)
+		return 1
+	}
+	fmt.Fprintf(stdout, "coverage %.1f%% (threshold %.1f%%)\n", summary.Percent(), minPct)
+	return 0
+}
@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGateCoverage_UsesProfileThresholdAndExclusions(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	target := filepath.Join(tmp, "target")
+	cache := filepath.Join(tmp, "cache")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "backend-go-hex", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: backend-go-hex
+coverage:
+  threshold: 85
+  exclude:
+    - "**/openapi/**"
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+
+	cfgPath := filepath.Join(target, ".governance", "config.yaml")
+	writeFile(t, cfgPath, strings.TrimSpace(`
+schemaVersion: 1
+source:
+  repo: `+srcRepo+`
+  ref: "HEAD"
+  profile: "backend-go-hex"
+paths:
+  cacheDir: `+cache+`
+coverage:
+  exclude:
+    - "**/mocks/**"
+`)+"\n")
+
+	profile := filepath.Join(target, "coverage.out")
+	writeFile(t, profile, strings.Join([]string{
+		"mode: set",
+		"example.com/app/internal/svc/svc.go:3.20,5.2 9 1",
+		"example.com/app/internal/db/db.go:3.20,5.2 1 0",
+		"example.com/app/internal/openapi/gen.go:3.20,5.2 50 0",
+		"example.com/app/internal/mocks/mock.go:3.20,5.2 50 0",
+		"",
+	}, "\n"))
+
+	var out, errOut bytes.Buffer
+	code := Run([]string{"agent-gov", "gate", "coverage", "--config", cfgPath, "--profile", profile}, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("expected 0, got %d stderr=%s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "coverage 90.0% (threshold 85.0%)") {
+		t.Fatalf("expected summary, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "- example.com/app/internal/db 0.0% (0/1)") {
+		t.Fatalf("expected per-package shortfall, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	errOut.Reset()
+	code = Run([]string{"agent-gov", "gate", "coverage", "--config", cfgPath, "--profile", profile, "--threshold", "95"}, &out, &errOut)
+	if code != 1 {
+		t.Fatalf("expected 1, got %d stderr=%s", code, errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "coverage 90.0% is below 95.0%") {
+		t.Fatalf("expected threshold failure, got:\n%s", errOut.String())
+	}
+}
+
+func TestGateCoverage_ThresholdFlagKeepsProfileExclusions(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	target := filepath.Join(tmp, "target")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	// No threshold in the manifest: --threshold supplies it, the exclusions still apply.
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "profile.yaml"), "schemaVersion: 1\nid: p\ncoverage:\n  exclude:\n    - \"**/gen/**\"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+
+	cfgPath := filepath.Join(target, ".governance", "config.yaml")
+	writeFile(t, cfgPath, "schemaVersion: 1\nsource:\n  repo: "+srcRepo+"\n  ref: HEAD\n  profile: p\npaths:\n  cacheDir: "+filepath.Join(tmp, "cache")+"\n")
+	profile := filepath.Join(target, "coverage.out")
+	writeFile(t, profile, "mode: set\nexample.com/app/svc/svc.go:3.20,5.2 8 1\nexample.com/app/svc/db.go:3.20,5.2 2 0\nexample.com/app/gen/gen.go:3.20,5.2 90 0\n")
+
+	for _, tc := range []struct {
+		threshold string
+		code      int
+		want      string
+	}{
+		{"80", 0, "coverage 80.0% (threshold 80.0%)"},
+		{"0", 0, "coverage 80.0% (threshold 0.0%)"},
+		{"81", 1, "coverage 80.0% is below 81.0%"},
+		{"101", 2, "--threshold must be between 0 and 100"},
+	} {
+		var out, errOut bytes.Buffer
+		code := Run([]string{"agent-gov", "gate", "coverage", "--config", cfgPath, "--profile", profile, "--threshold", tc.threshold}, &out, &errOut)
+		if code != tc.code || !strings.Contains(out.String()+errOut.String(), tc.want) {
+			t.Fatalf("--threshold %s: code=%d stdout=%s stderr=%s", tc.threshold, code, out.String(), errOut.String())
+		}
+	}
+
+	var out, errOut bytes.Buffer
+	if code := Run([]string{"agent-gov", "gate", "coverage", "--config", cfgPath, "--profile", profile}, &out, &errOut); code != 2 || !strings.Contains(errOut.String(), "no coverage threshold declared") {
+		t.Fatalf("expected missing threshold error, code=%d stderr=%s", code, errOut.String())
+	}
+}
+
+func readFileBytes(t *testing.T, path string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return b
+}
+
+func TestGateCoverage_UsageErrors(t *testing.T) {
+	cases := [][]string{
+		{"agent-gov", "gate"},
+		{"agent-gov", "gate", "lint"},
+		{"agent-gov", "gate", "coverage"},
+		{"agent-gov", "gate", "coverage", "--profile", "x.out", "--config", "/does/not/exist.yaml"},
+	}
+	for _, args := range cases {
+		var out, errOut bytes.Buffer
+		if code := Run(args, &out, &errOut); code != 2 {
+			t.Fatalf("args=%v expected 2, got %d stderr=%s", args, code, errOut.String())
+		}
+	}
+}
+
+func TestGateCoverage_RequiresThreshold(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "profile.yaml"), "schemaVersion: 1\nid: p\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+
+	cfgPath := filepath.Join(tmp, "target", ".governance", "config.yaml")
+	writeFile(t, cfgPath, "schemaVersion: 1\nsource:\n  repo: "+srcRepo+"\n  ref: HEAD\n  profile: p\npaths:\n  cacheDir: "+cache+"\n")
+	if err := os.WriteFile(filepath.Join(tmp, "c.out"), []byte("mode: set\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var out, errOut bytes.Buffer
+	code := Run([]string{"agent-gov", "gate", "coverage", "--config", cfgPath, "--profile", filepath.Join(tmp, "c.out")}, &out, &errOut)
+	if code != 2 || !strings.Contains(errOut.String(), "no coverage threshold") {
+		t.Fatalf("expected threshold error, got %d stderr=%s", code, errOut.String())
+	}
+}
+
+func TestGatesRun_ExecutesProfileGatesAndWritesSummary(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	target := filepath.Join(tmp, "target")
+	cache := filepath.Join(tmp, "cache")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: p
+gates:
+  - name: ci
+    command: [sh, -c, "test -f marker.txt"]
+    timeout: 30s
+  - name: docs
+    command: [sh, -c, "exit 1"]
+    optional: true
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+
+	cfgPath := filepath.Join(target, ".governance", "config.yaml")
+	writeFile(t, cfgPath, "schemaVersion: 1\nsource:\n  repo: "+srcRepo+"\n  ref: HEAD\n  profile: p\npaths:\n  cacheDir: "+cache+"\n")
+
+	summaryPath := filepath.Join(tmp, "out", "gates.md")
+	var out, errOut bytes.Buffer
+	code := Run([]string{"agent-gov", "gates", "run", "--config", cfgPath, "--summary", summaryPath}, &out, &errOut)
+	if code != 1 {
+		t.Fatalf("expected 1 without marker, got %d stderr=%s", code, errOut.String())
+	}
+
+	// Gates run from the repo root derived from the config location.
+	writeFile(t, filepath.Join(target, "marker.txt"), "x\n")
+	out.Reset()
+	errOut.Reset()
+	code = Run([]string{"agent-gov", "gates", "run", "--config", cfgPath, "--summary", summaryPath}, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("expected 0, got %d stdout=%s stderr=%s", code, out.String(), errOut.String())
+	}
+	b, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatalf("read summary: %v", err)
+	}
+	if !strings.Contains(string(b), "| ci |") || !strings.Contains(string(b), "All required gates passed.") {
+		t.Fatalf("unexpected summary:\n%s", b)
+	}
+
+	out.Reset()
+	errOut.Reset()
+	if code := Run([]string{"agent-gov", "gates", "run", "--config", cfgPath, "--only", "nope"}, &out, &errOut); code != 2 {
+		t.Fatalf("expected 2 for unknown gate, got %d", code)
+	}
+	if code := Run([]string{"agent-gov", "gates"}, &out, &errOut); code != 2 {
+		t.Fatalf("expected 2 for missing subcommand, got %d", code)
+	}
+}
+
+func TestGatesRun_SeveralApplicationsNeedAChoice(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	target := filepath.Join(tmp, "target")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	for id, exit := range map[string]string{"api": "0", "web": "1"} {
+		writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", id, "profile.yaml"),
+			"schemaVersion: 1\nid: "+id+"\ngates:\n  - name: ci\n    command: [sh, -c, \"exit "+exit+"\"]\n")
+	}
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+
+	cfgPath := filepath.Join(target, ".governance", "config.yaml")
+	writeFile(t, cfgPath, "schemaVersion: 1\nsource:\n  repo: "+srcRepo+"\n  ref: HEAD\npaths:\n  cacheDir: "+filepath.Join(tmp, "cache")+
+		"\napplications:\n  - profile: api\n    docsRoot: api\n  - profile: web\n    docsRoot: web\n")
+
+	for _, tc := range []struct {
+		args   []string
+		code   int
+		stderr string
+	}{
+		{nil, 2, "config has 2 applications (api, web); choose one with --application NAME"},
+		{[]string{"--application", "nope"}, 2, `unknown application "nope" (have: api, web)`},
+		{[]string{"--application", "api"}, 0, ""},
+		{[]string{"--application", "web"}, 1, ""},
+	} {
+		var out, errOut bytes.Buffer
+		args := append([]string{"agent-gov", "gates", "run", "--config", cfgPath}, tc.args...)
+		if code := Run(args, &out, &errOut); code != tc.code || !strings.Contains(errOut.String(), tc.stderr) {
+			t.Fatalf("%v: expected %d with %q, got %d stderr=%s", tc.args, tc.code, tc.stderr, code, errOut.String())
+		}
+	}
+
+	// Applications sharing a source need no choice to list its profiles.
+	var out, errOut bytes.Buffer
+	if code := Run([]string{"agent-gov", "profiles", "list", "--config", cfgPath}, &out, &errOut); code != 0 {
+		t.Fatalf("profiles list: expected 0, got %d stderr=%s", code, errOut.String())
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/govkit"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/config"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/gates"
+)
+
+func runGates(subArgs []string, stdout, stderr io.Writer) int {
+	if len(subArgs) < 1 || subArgs[0] != "run" {
+		fmt.Fprintln(stderr, "usage: agent-gov gates run [--config PATH] [--application NAME] [--only NAME] [--summary FILE]")
+		return 2
+	}
+	fs := flag.NewFlagSet("gates run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", defaultConfigPath, "path to .governance/config.yaml")
+	var only stringSliceFlag
+	fs.Var(&only, "only", "run only the named gate (repeatable)")
+	summaryPath := fs.String("summary", "", "write a Markdown summary to this file")
+	application := fs.String("application", "", "profile application whose gates run (required when the config has several)")
+	if err := fs.Parse(subArgs[1:]); err != nil {
+		return 2
+	}
+
+	resolvedConfigPath, autoDiscovered, err := resolveConfigPath(*configPath, subArgs[1:])
+	if err != nil {
+		fmt.Fprintf(stderr, "config discovery error: %v\n", err)
+		return 2
+	}
+	if autoDiscovered {
+		fmt.Fprintf(stderr, "using config: %s\n", resolvedConfigPath)
+	}
+	cfg, err := config.Load(resolvedConfigPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "config error: %v\n", err)
+		return 2
+	}
+	cacheDir, err := cfg.CacheDir()
+	if err != nil {
+		fmt.Fprintf(stderr, "cache dir error: %v\n", err)
+		return 2
+	}
+	ctx := context.Background()
+	app, err := selectApplication(cfg.ProfileApplications(), *application)
+	if err != nil {
+		fmt.Fprintf(stderr, "config error: %v\n", err)
+		return 2
+	}
+	m, _, err := govkit.LoadProfile(ctx,
+		govkit.WithSource(resolveRepoPathIfLocal(resolvedConfigPath, app.Repo), app.Ref, app.Profile),
+		govkit.WithCacheDir(cacheDir),
+	)
+	if err != nil {
+		fmt.Fprintf(stderr, "gates run failed: %v\n", err)
+		return 1
+	}
+
+	res, err := gates.Run(ctx, gates.RunOptions{
+		Dir:    repoRootForConfig(resolvedConfigPath),
+		Gates:  m.Gates,
+		Only:   only,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "gates run error: %v\n", err)
+		return 2
+	}
+
+	summary := gates.Summary(res)
+	fmt.Fprintln(stdout)
+	fmt.Fprint(stdout, summary)
+	if strings.TrimSpace(*summaryPath) != "" {
+		if err := os.MkdirAll(filepath.Dir(*summaryPath), 0o755); err != nil {
+			fmt.Fprintf(stderr, "gates run error: %v\n", err)
+			return 2
+		}
+		if err := os.WriteFile(*summaryPath, []byte(summary), 0o644); err != nil {
+			fmt.Fprintf(stderr, "gates run error: %v\n", err)
+			return 2
+		}
+	}
+	if !res.OK() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/config"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/hooks"
+)
+
+func runHooks(subArgs []string, stdout, stderr io.Writer) int {
+	if len(subArgs) < 1 {
+		fmt.Fprintln(stderr, "hooks requires a subcommand: install, uninstall, status, run")
+		return 2
+	}
+	sub := subArgs[0]
+	switch sub {
+	case "install", "uninstall", "status":
+		return runHooksManage(sub, subArgs[1:], stdout, stderr)
+	case "run":
+		if len(subArgs) < 2 {
+			fmt.Fprintln(stderr, "hooks run requires a hook name")
+			return 2
+		}
+		return runHook(subArgs[1], subArgs[2:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown hooks subcommand: %s\n", sub)
+		return 2
+	}
+}
+
+func runHooksManage(sub string, subArgs []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("hooks "+sub, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	bin := fs.String("bin", "agent-gov", "agent-gov command invoked by the hooks (install only)")
+	if err := fs.Parse(subArgs); err != nil {
+		return 2
+	}
+
+	hooksDir, err := gitHooksDir(".")
+	if err != nil {
+		fmt.Fprintf(stderr, "hooks error: %v\n", err)
+		return 2
+	}
+
+	var statuses []hooks.HookStatus
+	switch sub {
+	case "install":
+		statuses, err = hooks.Install(hooks.InstallOptions{HooksDir: hooksDir, Bin: *bin})
+	case "uninstall":
+		statuses, err = hooks.Uninstall(hooksDir, nil)
+	default:
+		statuses, err = hooks.Status(hooksDir, nil)
+	}
+	for _, st := range statuses {
+		fmt.Fprintln(stdout, st.String())
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "hooks %s failed: %v\n", sub, err)
+		return 1
+	}
+	if sub == "status" {
+		for _, st := range statuses {
+			if st.State != hooks.StateManaged {
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+// runHook executes the governance gates for a managed git hook.
+func runHook(name string, hookArgs []string, stdout, stderr io.Writer) int {
+	switch name {
+	case "pre-commit":
+		return runPreflight(nil, stdout, stderr)
+	case "commit-msg":
+		if len(hookArgs) < 1 {
+			fmt.Fprintln(stderr, "commit-msg hook requires the message file argument")
+			return 2
+		}
+		if code := checkCommitMsgFile(defaultConfigPath, nil, hookArgs[0], stdout, stderr); code != 0 {
+			return code
+		}
+		return runPlanCheck(stderr)
+	case "pre-push":
+		return runSubcommand("verify", nil, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown hook: %s\n", name)
+		return 2
+	}
+}
+
+// runPlanCheck fails when commits.requirePlanCheckpoint is set and the current branch has
+// no plan under Docs/Plans. It passes on a detached HEAD (e.g. during a rebase), which has
+// no branch to look a plan up for.
+func runPlanCheck(stderr io.Writer) int {
+	cfgPath, ok, err := findNearestConfig(".")
+	if err != nil {
+		fmt.Fprintf(stderr, "plan check error: find config: %v\n", err)
+		return 2
+	}
+	if !ok {
+		fmt.Fprintf(stderr, "plan check failed: could not find %s upward from current directory\n", defaultConfigPath)
+		return 1
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "config error: %v\n", err)
+		return 2
+	}
+	if !cfg.Commits.RequirePlanCheckpoint {
+		return 0
+	}
+	repoRoot := repoRootForConfig(cfgPath)
+	branch, err := gitCurrentBranch(repoRoot)
+	if err != nil {
+		fmt.Fprintf(stderr, "plan check error: git branch: %v\n", err)
+		return 2
+	}
+	if isDetachedHead(branch) {
+		return 0
+	}
+	planned, err := listPlannedBranches(filepath.Join(repoRoot, "Docs", "Plans"))
+	if err != nil {
+		fmt.Fprintf(stderr, "plan check error: plan scan: %v\n", err)
+		return 2
+	}
+	if !planned[branch] {
+		fmt.Fprintf(stderr, "plan check failed: no plan found for branch %q under Docs/Plans\n", branch)
+		return 1
+	}
+	return 0
+}
+
+// gitHooksDir returns the effective hooks directory for the repo containing dir.
+// `git rev-parse --git-path hooks` honours core.hooksPath and linked worktrees.
+func gitHooksDir(dir string) (string, error) {
+	ctx := context.Background()
+	top, err := gitOutputIn(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	p, err := gitOutputIn(ctx, top, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(top, p)
+	}
+	return filepath.Clean(p), nil
+}
+
+func gitOutputIn(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := execCommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v (%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
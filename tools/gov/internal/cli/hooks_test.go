@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHooks_InstallRespectsCoreHooksPathAndStatus(t *testing.T) {
+	tmp := t.TempDir()
+	repo := filepath.Join(tmp, "repo")
+	mustRun(t, tmp, "git", "init", repo)
+	mustRun(t, repo, "git", "config", "core.hooksPath", ".githooks")
+	writeFile(t, filepath.Join(repo, ".githooks", "pre-commit"), "#!/bin/sh\nexit 0\n")
+
+	oldCwd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldCwd) }()
+	_ = os.Chdir(repo)
+
+	var out, errOut bytes.Buffer
+	if code := Run([]string{"agent-gov", "hooks", "status"}, &out, &errOut); code != 1 {
+		t.Fatalf("expected status 1 before install, got %d stderr=%s", code, errOut.String())
+	}
+
+	out.Reset()
+	errOut.Reset()
+	if code := Run([]string{"agent-gov", "hooks", "install", "--bin", "tools/bin/agent-gov"}, &out, &errOut); code != 0 {
+		t.Fatalf("install code=%d stderr=%s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "pre-commit: installed (chains existing hook)") {
+		t.Fatalf("expected chained pre-commit, got:\n%s", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".githooks", "pre-push")); err != nil {
+		t.Fatalf("expected hook under core.hooksPath: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".git", "hooks", "pre-push")); err == nil {
+		t.Fatalf("expected no hook under .git/hooks when core.hooksPath is set")
+	}
+
+	out.Reset()
+	errOut.Reset()
+	if code := Run([]string{"agent-gov", "hooks", "status"}, &out, &errOut); code != 0 {
+		t.Fatalf("status code=%d stderr=%s", code, errOut.String())
+	}
+
+	out.Reset()
+	errOut.Reset()
+	if code := Run([]string{"agent-gov", "hooks", "uninstall"}, &out, &errOut); code != 0 {
+		t.Fatalf("uninstall code=%d stderr=%s", code, errOut.String())
+	}
+	b, err := os.ReadFile(filepath.Join(repo, ".githooks", "pre-commit"))
+	if err != nil || string(b) != "#!/bin/sh\nexit 0\n" {
+		t.Fatalf("expected original hook restored, got %q (%v)", b, err)
+	}
+}
+
+func TestHooks_RunCommitMsg_RequiresPlanForBranch(t *testing.T) {
+	tmp := t.TempDir()
+	repo := filepath.Join(tmp, "repo")
+	mustRun(t, tmp, "git", "init", repo)
+	mustRun(t, repo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, repo, "git", "config", "user.name", "Test")
+	cfgPath := filepath.Join(repo, ".governance", "config.yaml")
+	cfg := "schemaVersion: 1\nsource:\n  repo: .\n  ref: \"HEAD\"\n  profile: \"backend-go-hex\"\npaths:\n  docsRoot: \".\"\n"
+	writeFile(t, cfgPath, cfg)
+	writeFile(t, filepath.Join(repo, "Docs", "Plans", "feat", "planned.md"), "# plan\n\n- [ ] Checkpoint 1 — scaffold\n")
+	writeFile(t, filepath.Join(repo, "README.md"), "x\n")
+	mustRun(t, repo, "git", "add", ".")
+	mustRun(t, repo, "git", "commit", "-m", "init")
+	mustRun(t, repo, "git", "checkout", "-b", "feat/unplanned")
+
+	oldCwd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldCwd) }()
+	_ = os.Chdir(repo)
+
+	msgPath := filepath.Join(tmp, "COMMIT_EDITMSG")
+	run := func(msg string) (int, string) {
+		t.Helper()
+		writeFile(t, msgPath, msg)
+		var out, errOut bytes.Buffer
+		code := Run([]string{"agent-gov", "hooks", "run", "commit-msg", msgPath}, &out, &errOut)
+		return code, errOut.String()
+	}
+
+	var out, errOut bytes.Buffer
+	if code := Run([]string{"agent-gov", "hooks", "run", "commit-msg"}, &out, &errOut); code != 2 {
+		t.Fatalf("expected 2 without the message file, got %d stderr=%s", code, errOut.String())
+	}
+
+	// Without commits.requirePlanCheckpoint, an unplanned branch is fine.
+	if code, stderr := run("feat: add unplanned work\n"); code != 0 {
+		t.Fatalf("expected 0 without requirePlanCheckpoint, got %d stderr=%s", code, stderr)
+	}
+
+	writeFile(t, cfgPath, cfg+"commits:\n  requirePlanCheckpoint: true\n")
+	if code, stderr := run("feat: add unplanned work\n\nCheckpoint 1\n"); code != 1 || !strings.Contains(stderr, "no plan found") {
+		t.Fatalf("expected plan failure, got %d stderr=%s", code, stderr)
+	}
+
+	mustRun(t, repo, "git", "checkout", "-b", "feat/planned")
+	if code, stderr := run("feat: add planned work\n\nCheckpoint 1\n"); code != 0 {
+		t.Fatalf("expected 0, got %d stderr=%s", code, stderr)
+	}
+	if code, stderr := run("wip\n"); code != 1 {
+		t.Fatalf("expected 1 for non-conventional message, got %d stderr=%s", code, stderr)
+	}
+
+	// Commits replayed by a rebase run on a detached HEAD, which has no plan to check.
+	mustRun(t, repo, "git", "checkout", "--detach")
+	if code, stderr := run("feat: replay planned work\n"); code != 0 {
+		t.Fatalf("expected 0 on a detached HEAD, got %d stderr=%s", code, stderr)
+	}
+}
+
+func TestHooks_UsageErrors(t *testing.T) {
+	cases := [][]string{
+		{"agent-gov", "hooks"},
+		{"agent-gov", "hooks", "nope"},
+		{"agent-gov", "hooks", "run"},
+		{"agent-gov", "hooks", "run", "post-merge"},
+	}
+	for _, args := range cases {
+		var out, errOut bytes.Buffer
+		if code := Run(args, &out, &errOut); code != 2 {
+			t.Fatalf("args=%v expected 2, got %d", args, code)
+		}
+	}
+}
@@ -11,6 +11,10 @@ import (
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/commitmsg"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/config"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/refactorcheck"
 )
 
 type stringSliceFlag []string
@@ -33,6 +37,8 @@ func runPreflight(subArgs []string, stdout, stderr io.Writer) int {
 	var require stringSliceFlag
 	fs.Var(&require, "require", "required path relative to repo root (repeatable)")
 	activePlan := fs.String("active-plan", "", "path to active plan file (optional)")
+	checkPurity := fs.Bool("check-refactor-purity", false, "on refactor/* branches, fail if the diff touches test expectations or API boundaries")
+	baseRef := fs.String("base", "", "base branch for --check-refactor-purity (default refactor.baseRef or main)")
 
 	if err := fs.Parse(subArgs); err != nil {
 		return 2
@@ -54,7 +60,7 @@ func runPreflight(subArgs []string, stdout, stderr io.Writer) int {
 		fmt.Fprintf(stderr, "preflight error: git branch: %v\n", err)
 		return 2
 	}
-	if branch == "HEAD" || strings.TrimSpace(branch) == "" {
+	if isDetachedHead(branch) {
 		fmt.Fprintln(stderr, "preflight failed: detached HEAD (create/switch to a feature branch)")
 		return 1
 	}
@@ -110,10 +116,60 @@ func runPreflight(subArgs []string, stdout, stderr io.Writer) int {
 		}
 	}
 
+	if *checkPurity {
+		if code := checkRefactorPurity(cfgPath, repoRoot, branch, *baseRef, stderr); code != 0 {
+			return code
+		}
+	}
+
 	fmt.Fprintln(stdout, "ok")
 	return 0
 }
 
+// checkRefactorPurity enforces "refactors must not be mixed with behavior changes"
+// for `refactor/*` branches by inspecting the diff against the merge base.
+func checkRefactorPurity(cfgPath, repoRoot, branch, baseRef string, stderr io.Writer) int {
+	if commitmsg.BranchType(branch) != "refactor" {
+		return 0
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "preflight error: config: %v\n", err)
+		return 2
+	}
+	if strings.TrimSpace(baseRef) == "" {
+		baseRef = cfg.Refactor.BaseRef
+	}
+	rules := refactorcheck.Rules{
+		Expectations: cfg.Refactor.Expectations,
+		Boundaries:   cfg.Refactor.Boundaries,
+	}
+	if len(rules.Expectations) == 0 {
+		rules.Expectations = refactorcheck.DefaultExpectations
+	}
+
+	ctx := context.Background()
+	mergeBase, err := gitOutputIn(ctx, repoRoot, "merge-base", "HEAD", baseRef)
+	if err != nil {
+		fmt.Fprintf(stderr, "preflight error: merge base with %s: %v\n", baseRef, err)
+		return 2
+	}
+	diff, err := gitOutputIn(ctx, repoRoot, "diff", "--name-status", "-M", "-z", mergeBase)
+	if err != nil {
+		fmt.Fprintf(stderr, "preflight error: diff: %v\n", err)
+		return 2
+	}
+	findings := refactorcheck.Check(refactorcheck.ParseNameStatus(diff), rules)
+	if len(findings) == 0 {
+		return 0
+	}
+	fmt.Fprintf(stderr, "preflight failed: refactor branch %q contains %d behavior change(s) relative to %s:\n", branch, len(findings), baseRef)
+	for _, f := range findings {
+		fmt.Fprintf(stderr, "- %s\n", f)
+	}
+	return 1
+}
+
 func gitCurrentBranch(dir string) (string, error) {
 	ctx := context.Background()
 	cmd := execCommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD")
@@ -125,6 +181,11 @@ func gitCurrentBranch(dir string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// isDetachedHead reports whether branch, as returned by gitCurrentBranch, names no branch.
+func isDetachedHead(branch string) bool {
+	return branch == "HEAD" || strings.TrimSpace(branch) == ""
+}
+
 func listPlannedBranches(plansDir string) (map[string]bool, error) {
 	branches := make(map[string]bool)
 	entries, err := walkPlanFiles(plansDir)
@@ -143,6 +204,24 @@ func listPlannedBranches(plansDir string) (map[string]bool, error) {
 	return branches, nil
 }
 
+// findPlanForBranch returns the plan file whose branch (frontmatter or path) matches branch.
+func findPlanForBranch(plansDir, branch string) (string, bool, error) {
+	entries, err := walkPlanFiles(plansDir)
+	if err != nil {
+		return "", false, err
+	}
+	for _, p := range entries {
+		b, _, err := planMetadataForPath(plansDir, p)
+		if err != nil {
+			return "", false, err
+		}
+		if b == branch {
+			return p, true, nil
+		}
+	}
+	return "", false, nil
+}
+
 func findActiveBranchFromPlans(plansDir string) (string, error) {
 	entries, err := walkPlanFiles(plansDir)
 	if err != nil {
@@ -265,3 +265,71 @@ func mustRunOut(t *testing.T, dir string, exe string, args ...string) []byte {
 	}
 	return out
 }
+
+func TestPreflight_CheckRefactorPurity(t *testing.T) {
+	tmp := t.TempDir()
+	repo := filepath.Join(tmp, "repo")
+	mustRun(t, tmp, "git", "init", repo)
+	mustRun(t, repo, "git", "branch", "-m", "main")
+	mustRun(t, repo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, repo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(repo, ".governance", "config.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+source:
+  repo: .
+  ref: "HEAD"
+  profile: "backend-go-hex"
+refactor:
+  boundaries:
+    - "api/**"
+`)+"\n")
+	writeFile(t, filepath.Join(repo, "internal", "svc", "svc.go"), "package svc\n")
+	writeFile(t, filepath.Join(repo, "internal", "svc", "testdata", "out.golden"), "v1\n")
+	// git quotes names like this one in plain --name-status output.
+	writeFile(t, filepath.Join(repo, "internal", "svc", "testdata", "café out.golden"), "v1\n")
+	writeFile(t, filepath.Join(repo, "api", "v1.yaml"), "openapi: 3.0.0\n")
+	mustRun(t, repo, "git", "add", ".")
+	mustRun(t, repo, "git", "commit", "-m", "init")
+
+	oldCwd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldCwd) }()
+	_ = os.Chdir(repo)
+
+	// Pure refactor: internal code only.
+	mustRun(t, repo, "git", "checkout", "-b", "refactor/app-rename")
+	writeFile(t, filepath.Join(repo, "internal", "svc", "svc.go"), "package svc\n\n// renamed\n")
+	mustRun(t, repo, "git", "commit", "-am", "refactor")
+	var out, errOut bytes.Buffer
+	if code := Run([]string{"agent-gov", "preflight", "--check-refactor-purity"}, &out, &errOut); code != 0 {
+		t.Fatalf("expected 0, got %d stderr=%s", code, errOut.String())
+	}
+
+	// Golden + API edits on the same refactor branch are flagged.
+	writeFile(t, filepath.Join(repo, "internal", "svc", "testdata", "out.golden"), "v2\n")
+	writeFile(t, filepath.Join(repo, "internal", "svc", "testdata", "café out.golden"), "v2\n")
+	writeFile(t, filepath.Join(repo, "api", "v1.yaml"), "openapi: 3.1.0\n")
+	mustRun(t, repo, "git", "commit", "-am", "behavior")
+	out.Reset()
+	errOut.Reset()
+	code := Run([]string{"agent-gov", "preflight", "--check-refactor-purity"}, &out, &errOut)
+	if code != 1 {
+		t.Fatalf("expected 1, got %d stderr=%s", code, errOut.String())
+	}
+	for _, want := range []string{
+		"api/v1.yaml (modifies public API boundary)",
+		"internal/svc/testdata/out.golden (modifies test expectation)",
+		"internal/svc/testdata/café out.golden (modifies test expectation)",
+	} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Fatalf("expected %q in stderr, got:\n%s", want, errOut.String())
+		}
+	}
+
+	// Non-refactor branches are not subject to the purity check.
+	mustRun(t, repo, "git", "checkout", "-b", "feat/app-behavior")
+	out.Reset()
+	errOut.Reset()
+	if code := Run([]string{"agent-gov", "preflight", "--check-refactor-purity"}, &out, &errOut); code != 0 {
+		t.Fatalf("expected 0 on feat branch, got %d stderr=%s", code, errOut.String())
+	}
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/govkit"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/config"
+)
+
+func runProfile(subArgs []string, stdout, stderr io.Writer) int {
+	if len(subArgs) < 1 || subArgs[0] != "validate" {
+		fmt.Fprintln(stderr, "usage: agent-gov profile validate [--root DIR] [--cache-dir DIR] (--all | ID...)")
+		return 2
+	}
+	fs := flag.NewFlagSet("profile validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	root := fs.String("root", ".", "governance source repo root (contains Governance/)")
+	all := fs.Bool("all", false, "validate every profile and report unused Core/Templates files")
+	cacheDir := fs.String("cache-dir", "", "cache for cross-repository extends (default: the user cache dir)")
+	if err := fs.Parse(subArgs[1:]); err != nil {
+		return 2
+	}
+
+	ids := fs.Args()
+	if *all && len(ids) > 0 {
+		fmt.Fprintln(stderr, "--all cannot be combined with profile IDs")
+		return 2
+	}
+	if !*all && len(ids) == 0 {
+		fmt.Fprintln(stderr, "profile validate requires --all or at least one profile ID")
+		return 2
+	}
+
+	if *cacheDir == "" {
+		dir, err := config.Config{}.CacheDir()
+		if err != nil {
+			fmt.Fprintf(stderr, "cache dir error: %v\n", err)
+			return 2
+		}
+		*cacheDir = dir
+	}
+
+	// With --all, ids is empty: every profile is validated and unused files are reported.
+	res, err := govkit.ValidateProfiles(context.Background(), govkit.Checkout{Dir: *root}, ids, govkit.WithCacheDir(*cacheDir))
+	if err != nil {
+		fmt.Fprintf(stderr, "profile validate error: %v\n", err)
+		return 2
+	}
+	if len(res.Problems) > 0 {
+		fmt.Fprintf(stderr, "profile validation failed: %d issue(s)\n", len(res.Problems))
+		for _, p := range res.Problems {
+			fmt.Fprintf(stderr, "- %s\n", p)
+		}
+		return 1
+	}
+	fmt.Fprintf(stdout, "ok: %d profile(s) valid\n", len(res.IDs))
+	return 0
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProfileValidate(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "Governance", "Core", "Core.md"), "core\n")
+	writeFile(t, filepath.Join(root, "Governance", "Profiles", "p", "profile.yaml"), "schemaVersion: 1\nid: p\ndocuments:\n  - output: A.md\n    fragments: [../../Core/Core.md]\n")
+
+	var out, errOut bytes.Buffer
+	if code := Run([]string{"agent-gov", "profile", "validate", "--root", root, "--all"}, &out, &errOut); code != 0 {
+		t.Fatalf("expected 0, got %d stderr=%s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "ok: 1 profile(s) valid") {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+
+	writeFile(t, filepath.Join(root, "Governance", "Core", "Unused.md"), "x\n")
+	out.Reset()
+	errOut.Reset()
+	if code := Run([]string{"agent-gov", "profile", "validate", "--root", root, "--all"}, &out, &errOut); code != 1 {
+		t.Fatalf("expected 1, got %d", code)
+	}
+	if !strings.Contains(errOut.String(), "- unused file Governance/Core/Unused.md") {
+		t.Fatalf("expected unused file report, got: %s", errOut.String())
+	}
+
+	// Validating a single profile does not report unused shared files.
+	errOut.Reset()
+	if code := Run([]string{"agent-gov", "profile", "validate", "--root", root, "p"}, &out, &errOut); code != 0 {
+		t.Fatalf("expected 0, got %d stderr=%s", code, errOut.String())
+	}
+
+	for _, args := range [][]string{
+		{"agent-gov", "profile"},
+		{"agent-gov", "profile", "validate", "--root", root},
+		{"agent-gov", "profile", "validate", "--root", root, "--all", "p"},
+		{"agent-gov", "profile", "validate", "--root", filepath.Join(root, "nope"), "--all"},
+	} {
+		if code := Run(args, &out, &errOut); code != 2 {
+			t.Fatalf("args=%v expected 2, got %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/govkit"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/config"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/profile"
+)
+
+func runProfiles(subArgs []string, stdout, stderr io.Writer) int {
+	if len(subArgs) < 1 || (subArgs[0] != "list" && subArgs[0] != "show") {
+		fmt.Fprintln(stderr, "usage: agent-gov profiles list|show [--config PATH] [--application NAME] [--ref REF] [ID]")
+		return 2
+	}
+	action := subArgs[0]
+	fs := flag.NewFlagSet("profiles "+action, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", defaultConfigPath, "path to .governance/config.yaml")
+	ref := fs.String("ref", "", "source ref to inspect (default: the configured source.ref)")
+	application := fs.String("application", "", "profile application whose source is inspected (required when applications use different sources)")
+	if err := fs.Parse(subArgs[1:]); err != nil {
+		return 2
+	}
+	if action == "show" && fs.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: agent-gov profiles show [--config PATH] [--application NAME] [--ref REF] ID")
+		return 2
+	}
+	if action == "list" && fs.NArg() != 0 {
+		fmt.Fprintln(stderr, "usage: agent-gov profiles list [--config PATH] [--application NAME] [--ref REF]")
+		return 2
+	}
+
+	resolvedConfigPath, autoDiscovered, err := resolveConfigPath(*configPath, subArgs[1:])
+	if err != nil {
+		fmt.Fprintf(stderr, "config discovery error: %v\n", err)
+		return 2
+	}
+	if autoDiscovered {
+		fmt.Fprintf(stderr, "using config: %s\n", resolvedConfigPath)
+	}
+	cfg, err := config.Load(resolvedConfigPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "config error: %v\n", err)
+		return 2
+	}
+	cacheDir, err := cfg.CacheDir()
+	if err != nil {
+		fmt.Fprintf(stderr, "cache dir error: %v\n", err)
+		return 2
+	}
+	// Profiles are read from the selected application's source; applications sharing a
+	// source need no choice.
+	apps := cfg.ProfileApplications()
+	if strings.TrimSpace(*ref) != "" {
+		for i := range apps {
+			apps[i].Ref = *ref
+		}
+	}
+	if strings.TrimSpace(*application) == "" {
+		apps = distinctSources(apps)
+	}
+	app, err := selectApplication(apps, *application)
+	if err != nil {
+		fmt.Fprintf(stderr, "config error: %v\n", err)
+		return 2
+	}
+	ctx := context.Background()
+	// list needs no profile ID; show's is its only argument.
+	opts := []govkit.Option{
+		govkit.WithSource(resolveRepoPathIfLocal(resolvedConfigPath, app.Repo), app.Ref, fs.Arg(0)),
+		govkit.WithCacheDir(cacheDir),
+	}
+	if action == "list" {
+		return listProfiles(ctx, opts, stdout, stderr)
+	}
+	return showProfile(ctx, opts, stdout, stderr)
+}
+
+// distinctSources returns the first application for each source repo and ref.
+func distinctSources(apps []config.ApplicationConfig) []config.ApplicationConfig {
+	seen := map[string]bool{}
+	var out []config.ApplicationConfig
+	for _, app := range apps {
+		key := app.Repo + "@" + app.Ref
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, app)
+		}
+	}
+	return out
+}
+
+func listProfiles(ctx context.Context, opts []govkit.Option, stdout, stderr io.Writer) int {
+	list, src, err := govkit.ListProfiles(ctx, opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "profiles list failed: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "source: %s@%s (%s)\n\n", src.Repo, src.Ref, src.Commit)
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tEXTENDS\tDESCRIPTION")
+	for _, p := range list {
+		if p.Err != nil {
+			fmt.Fprintf(tw, "%s\t-\tinvalid: %v\n", p.ID, p.Err)
+			continue
+		}
+		extends := "-"
+		if len(p.Manifest.Lineage) > 1 {
+			extends = strings.Join(p.Manifest.Lineage[:len(p.Manifest.Lineage)-1], ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", p.ID, extends, p.Manifest.Description)
+	}
+	if err := tw.Flush(); err != nil {
+		fmt.Fprintf(stderr, "profiles list failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func showProfile(ctx context.Context, opts []govkit.Option, stdout, stderr io.Writer) int {
+	m, src, err := govkit.LoadProfile(ctx, opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "profiles show failed: %v\n", err)
+		return 1
+	}
+	// Paths from cross-repository bases are shown as repo@ref:path.
+	rel := func(p string) string {
+		for _, s := range m.Sources {
+			if r, err := filepath.Rel(s.Dir, p); err == nil && !strings.HasPrefix(r, "..") {
+				return s.Repo + "@" + s.Ref + ":" + filepath.ToSlash(r)
+			}
+		}
+		r, err := filepath.Rel(src.Dir, p)
+		if err != nil {
+			return p
+		}
+		return filepath.ToSlash(r)
+	}
+
+	fmt.Fprintf(stdout, "ID: %s\n", m.ID)
+	fmt.Fprintf(stdout, "Description: %s\n", m.Description)
+	fmt.Fprintf(stdout, "Extends chain: %s\n", strings.Join(m.Lineage, " -> "))
+	fmt.Fprintf(stdout, "Source: %s@%s (%s)\n", src.Repo, src.Ref, src.Commit)
+	for _, s := range m.Sources {
+		fmt.Fprintf(stdout, "Base source: %s@%s (%s)\n", s.Repo, s.Ref, s.Commit)
+	}
+
+	if len(m.Parameters) > 0 {
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "Parameters:")
+		for _, name := range m.ParameterNames() {
+			p := m.Parameters[name]
+			def := "required"
+			if p.Default != nil {
+				def = fmt.Sprintf("default %v", p.Default)
+			}
+			fmt.Fprintf(stdout, "  %s (%s; %s)", name, p.Constraints(), def)
+			if p.Description != "" {
+				fmt.Fprintf(stdout, " - %s", p.Description)
+			}
+			fmt.Fprintln(stdout)
+		}
+	}
+
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "Documents:")
+	for _, d := range m.Documents {
+		fmt.Fprintf(stdout, "  %s (declared in %s)\n", d.Output, rel(d.Origin))
+		for _, sec := range d.Blocks() {
+			indent := "    "
+			if sec.Name != "" {
+				fmt.Fprintf(stdout, "    section %s:\n", sec.Name)
+				indent = "      "
+			}
+			for _, frag := range sec.Fragments {
+				var notes []string
+				if frag.From != "" {
+					notes = append(notes, "from "+rel(frag.From))
+				}
+				if frag.When != "" {
+					notes = append(notes, "when "+frag.When)
+				}
+				if len(notes) > 0 {
+					fmt.Fprintf(stdout, "%s- %s (%s)\n", indent, rel(frag.Path), strings.Join(notes, ", "))
+					continue
+				}
+				fmt.Fprintf(stdout, "%s- %s\n", indent, rel(frag.Path))
+			}
+		}
+	}
+	for _, section := range []struct {
+		title string
+		specs []profile.FileSpec
+	}{{"Templates", m.Templates}, {"Playbooks", m.Playbooks}} {
+		fmt.Fprintln(stdout)
+		fmt.Fprintf(stdout, "%s:\n", section.title)
+		for _, f := range section.specs {
+			when := ""
+			if f.When != "" {
+				when = ", when " + f.When
+			}
+			fmt.Fprintf(stdout, "  %s <- %s (declared in %s%s)\n", f.Output, rel(f.Source), rel(f.Origin), when)
+		}
+	}
+	return 0
+}
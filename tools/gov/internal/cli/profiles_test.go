@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProfilesListAndShow(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Core", "Core.md"), "core\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "base", "Base.md"), "base\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "base", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: base
+description: Base profile.
+parameters:
+  ciProvider:
+    type: enum
+    values: [github, gitlab]
+    description: Hosting for CI and reviews.
+documents:
+  - output: Constitution.md
+    fragments: [../../Core/Core.md, ./Base.md]
+`)+"\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "child", "Child.md"), "child\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "child", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: child
+description: Child profile.
+extends: [../base/profile.yaml]
+playbooks:
+  - source: ./Child.md
+    output: Docs/Playbooks/Child.md
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+	mustRun(t, srcRepo, "git", "tag", "v1")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "broken", "profile.yaml"), "schemaVersion: 1\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v2")
+
+	cfgPath := filepath.Join(tmp, "target", ".governance", "config.yaml")
+	writeFile(t, cfgPath, "schemaVersion: 1\nsource:\n  repo: "+srcRepo+"\n  ref: HEAD\n  profile: child\npaths:\n  cacheDir: "+cache+"\n")
+
+	var out, errOut bytes.Buffer
+	if code := Run([]string{"agent-gov", "profiles", "list", "--config", cfgPath}, &out, &errOut); code != 0 {
+		t.Fatalf("expected 0, got %d stderr=%s", code, errOut.String())
+	}
+	for _, want := range []string{"base ", "Base profile.", "child ", "base  ", "Child profile.", "broken", "invalid: profile id is required"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in list output:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if code := Run([]string{"agent-gov", "profiles", "list", "--config", cfgPath, "--ref", "v1"}, &out, &errOut); code != 0 {
+		t.Fatalf("expected 0, got %d stderr=%s", code, errOut.String())
+	}
+	if strings.Contains(out.String(), "broken") || !strings.Contains(out.String(), "@v1") {
+		t.Fatalf("expected listing at v1:\n%s", out.String())
+	}
+
+	out.Reset()
+	if code := Run([]string{"agent-gov", "profiles", "show", "--config", cfgPath, "child"}, &out, &errOut); code != 0 {
+		t.Fatalf("expected 0, got %d stderr=%s", code, errOut.String())
+	}
+	for _, want := range []string{
+		"ID: child",
+		"Description: Child profile.",
+		"Extends chain: base -> child",
+		"  ciProvider (enum [github, gitlab]; required) - Hosting for CI and reviews.",
+		"Constitution.md (declared in Governance/Profiles/base/profile.yaml)",
+		"    - Governance/Core/Core.md\n    - Governance/Profiles/base/Base.md\n",
+		"Docs/Playbooks/Child.md <- Governance/Profiles/child/Child.md (declared in Governance/Profiles/child/profile.yaml)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in show output:\n%s", want, out.String())
+		}
+	}
+
+	if code := Run([]string{"agent-gov", "profiles", "show", "--config", cfgPath, "missing"}, &out, &errOut); code != 1 {
+		t.Fatalf("expected 1 for missing profile, got %d", code)
+	}
+	for _, args := range [][]string{
+		{"agent-gov", "profiles"},
+		{"agent-gov", "profiles", "show", "--config", cfgPath},
+		{"agent-gov", "profiles", "list", "--config", cfgPath, "extra"},
+		{"agent-gov", "profiles", "list", "--config", filepath.Join(tmp, "nope.yaml")},
+	} {
+		if code := Run(args, &out, &errOut); code != 2 {
+			t.Fatalf("args=%v expected 2, got %d", args, code)
+		}
+	}
+}
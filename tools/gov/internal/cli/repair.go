@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/govkit"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/config"
+)
+
+// stdin answers repair's confirmation prompts; tests replace it.
+var stdin io.Reader = os.Stdin
+
+func runRepair(subArgs []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("repair", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", defaultConfigPath, "path to .governance/config.yaml")
+	yes := fs.Bool("yes", false, "apply every proposed fix without asking")
+	if err := fs.Parse(subArgs); err != nil {
+		return 2
+	}
+
+	resolvedConfigPath, autoDiscovered, err := resolveConfigPath(*configPath, subArgs)
+	if err != nil {
+		fmt.Fprintf(stderr, "config discovery error: %v\n", err)
+		return 2
+	}
+	if autoDiscovered {
+		fmt.Fprintf(stderr, "using config: %s\n", resolvedConfigPath)
+	}
+	cfg, err := config.Load(resolvedConfigPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "config error: %v\n", err)
+		return 2
+	}
+	cacheDir, err := cfg.CacheDir()
+	if err != nil {
+		fmt.Fprintf(stderr, "cache dir error: %v\n", err)
+		return 2
+	}
+	repoRoot := repoRootForConfig(resolvedConfigPath)
+
+	ctx := context.Background()
+	in := bufio.NewReader(stdin)
+	failed, repaired := false, 0
+	for _, app := range cfg.ProfileApplications() {
+		res, err := govkit.Repair(ctx, applicationOptions(cfg, resolvedConfigPath, repoRoot, cacheDir, app)...)
+		if err != nil {
+			fmt.Fprintf(stderr, "repair failed: %v\n", err)
+			failed = true
+			continue
+		}
+		for _, f := range res.Files {
+			fmt.Fprintf(stdout, "%s:\n", f.Path)
+			for _, fix := range f.Fixes {
+				fmt.Fprintf(stdout, "- %s\n", fix)
+			}
+			for _, p := range f.Problems {
+				fmt.Fprintf(stderr, "- %s\n", p)
+			}
+			if len(f.Problems) > 0 {
+				failed = true
+			}
+			if len(f.Fixes) == 0 {
+				continue
+			}
+			if !*yes {
+				fmt.Fprintf(stdout, "Apply %d fix(es) to %s? [y/N] ", len(f.Fixes), f.Path)
+				answer, _ := in.ReadString('\n')
+				if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+					fmt.Fprintln(stdout, "skipped")
+					continue
+				}
+			}
+			if err := govkit.ApplyRepair(f); err != nil {
+				fmt.Fprintf(stderr, "repair failed: %v\n", err)
+				failed = true
+				continue
+			}
+			repaired++
+		}
+	}
+	if failed {
+		return 1
+	}
+	fmt.Fprintf(stdout, "repaired %d file(s)\n", repaired)
+	return 0
+}
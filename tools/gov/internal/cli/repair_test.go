@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_Repair_PromptsThenAppliesWithYes(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+	target := filepath.Join(tmp, "target")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "rules.md"), "# Rules\n\n- one\n- two\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "p", "profile.yaml"), "schemaVersion: 1\nid: p\ndocuments:\n  - output: Rules.md\n    fragments: [./rules.md]\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+	mustRun(t, srcRepo, "git", "tag", "v1")
+
+	cfgPath := filepath.Join(target, ".governance", "config.yaml")
+	writeFile(t, cfgPath, "schemaVersion: 1\nsource:\n  repo: "+srcRepo+"\n  ref: v1\n  profile: p\npaths:\n  cacheDir: "+cache+"\n")
+
+	var outBuf, errBuf bytes.Buffer
+	if code := Run([]string{"agent-gov", "init", "--config", cfgPath}, &outBuf, &errBuf); code != 0 {
+		t.Fatalf("init code=%d stderr=%s", code, errBuf.String())
+	}
+	docPath := filepath.Join(target, "Rules.md")
+	b, err := os.ReadFile(docPath)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	damaged := strings.Replace(string(b), "<!-- GOV:END id=doc-rules -->\n", "", 1)
+	writeFile(t, docPath, damaged)
+
+	oldStdin := stdin
+	defer func() { stdin = oldStdin }()
+
+	stdin = strings.NewReader("n\n")
+	outBuf.Reset()
+	errBuf.Reset()
+	if code := Run([]string{"agent-gov", "repair", "--config", cfgPath}, &outBuf, &errBuf); code != 0 {
+		t.Fatalf("repair code=%d stderr=%s", code, errBuf.String())
+	}
+	for _, want := range []string{"- Rules.md: missing END marker; block now spans lines ", "Apply 1 fix(es) to " + docPath + "? [y/N] skipped\n", "repaired 0 file(s)\n"} {
+		if !strings.Contains(outBuf.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, outBuf.String())
+		}
+	}
+	if b, _ := os.ReadFile(docPath); string(b) != damaged {
+		t.Fatalf("declined repair must not modify the file")
+	}
+
+	outBuf.Reset()
+	errBuf.Reset()
+	if code := Run([]string{"agent-gov", "repair", "--config", cfgPath, "--yes"}, &outBuf, &errBuf); code != 0 {
+		t.Fatalf("repair --yes code=%d stderr=%s", code, errBuf.String())
+	}
+	if !strings.Contains(outBuf.String(), "repaired 1 file(s)\n") || strings.Contains(outBuf.String(), "[y/N]") {
+		t.Fatalf("unexpected output:\n%s", outBuf.String())
+	}
+	outBuf.Reset()
+	errBuf.Reset()
+	if code := Run([]string{"agent-gov", "verify", "--config", cfgPath}, &outBuf, &errBuf); code != 0 {
+		t.Fatalf("verify after repair code=%d stderr=%s", code, errBuf.String())
+	}
+}
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -9,8 +11,10 @@ import (
 	"path/filepath"
 	"strings"
 
-	"agent-governance-strategy/tools/gov/internal/builder"
-	"agent-governance-strategy/tools/gov/internal/config"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/govkit"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/builder"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/conditions"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/config"
 )
 
 const defaultConfigPath = ".governance/config.yaml"
@@ -28,7 +32,21 @@ func Run(args []string, stdout, stderr io.Writer) int {
 		return 0
 	case "preflight":
 		return runPreflight(args[2:], stdout, stderr)
-	case "init", "sync", "verify", "build":
+	case "hooks":
+		return runHooks(args[2:], stdout, stderr)
+	case "commitmsg":
+		return runCommitMsg(args[2:], stdout, stderr)
+	case "gate":
+		return runGate(args[2:], stdout, stderr)
+	case "gates":
+		return runGates(args[2:], stdout, stderr)
+	case "profile":
+		return runProfile(args[2:], stdout, stderr)
+	case "profiles":
+		return runProfiles(args[2:], stdout, stderr)
+	case "repair":
+		return runRepair(args[2:], stdout, stderr)
+	case "init", "sync", "verify", "diff", "build":
 		return runSubcommand(cmd, args[2:], stdout, stderr)
 	default:
 		fmt.Fprintf(stderr, "unknown command: %s\n\n", cmd)
@@ -43,6 +61,8 @@ func runSubcommand(cmd string, subArgs []string, stdout, stderr io.Writer) int {
 
 	configPath := fs.String("config", defaultConfigPath, "path to .governance/config.yaml")
 	outDir := fs.String("out", "", "output directory (build only)")
+	resume := fs.Bool("resume", false, "finish an interrupted sync (sync only)")
+	revert := fs.Bool("revert", false, "undo an interrupted sync (sync only)")
 
 	if err := fs.Parse(subArgs); err != nil {
 		// flag package already printed the error/usage.
@@ -58,135 +78,252 @@ func runSubcommand(cmd string, subArgs []string, stdout, stderr io.Writer) int {
 		fmt.Fprintf(stderr, "using config: %s\n", resolvedConfigPath)
 	}
 
-	switch cmd {
-	case "build":
-		if strings.TrimSpace(*outDir) == "" {
-			fmt.Fprintln(stderr, "--out is required for build")
-			return 2
+	if cmd == "build" && strings.TrimSpace(*outDir) == "" {
+		fmt.Fprintln(stderr, "--out is required for build")
+		return 2
+	}
+	if (*resume || *revert) && (cmd != "sync" || *resume == *revert) {
+		fmt.Fprintln(stderr, "--resume or --revert (not both) is only valid for sync")
+		return 2
+	}
+	cfg, err := config.Load(resolvedConfigPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "config error: %v\n", err)
+		return 2
+	}
+	cacheDir, err := cfg.CacheDir()
+	if err != nil {
+		fmt.Fprintf(stderr, "cache dir error: %v\n", err)
+		return 2
+	}
+	repoRoot := repoRootForConfig(resolvedConfigPath)
+	if *resume || *revert {
+		return runSyncRecovery(repoRoot, *resume, stdout, stderr)
+	}
+
+	ctx := context.Background()
+	apps := cfg.ProfileApplications()
+	// Sync checks for overlapping outputs itself, with the profiles it loads to render.
+	if len(apps) > 1 && cmd != "sync" {
+		var checked []builder.Application
+		for _, app := range apps {
+			m, _, err := govkit.LoadProfile(ctx, applicationOptions(cfg, resolvedConfigPath, repoRoot, cacheDir, app)...)
+			if err != nil {
+				fmt.Fprintf(stderr, "config error: %s: %v\n", app.Name, err)
+				return 2
+			}
+			checked = append(checked, builder.Application{Name: app.Name, DocsRoot: app.DocsRoot, Manifest: m})
 		}
-		cfg, err := config.Load(resolvedConfigPath)
-		if err != nil {
+		if err := builder.CheckApplicationOutputs(checked); err != nil {
 			fmt.Fprintf(stderr, "config error: %v\n", err)
 			return 2
 		}
-		cacheDir, err := cfg.CacheDir()
-		if err != nil {
-			fmt.Fprintf(stderr, "cache dir error: %v\n", err)
-			return 2
+	}
+
+	// An --out ending in .tar.gz, .tgz or .zip collects every application's build into
+	// one archive, written only if all of them succeed.
+	var bundle *govkit.Bundle
+	var archive bytes.Buffer
+	if format, ok := govkit.ArchiveFormatFor(*outDir); ok && cmd == "build" {
+		bundle = govkit.NewBundle(&archive, format)
+	}
+
+	// With several applications each result line is prefixed with the application name.
+	failed := false
+	var issues []string
+	var syncApps [][]govkit.Option
+	var syncLabels []string
+	for _, app := range apps {
+		label, issuePrefix := "", ""
+		if len(apps) > 1 {
+			label, issuePrefix = "["+app.Name+"] ", app.Name+": "
 		}
-		sourceRepo := resolveRepoPathIfLocal(resolvedConfigPath, cfg.Source.Repo)
-		res, err := builder.Build(context.Background(), builder.BuildOptions{
-			OutDir:         *outDir,
-			DocsRoot:       cfg.Paths.DocsRoot,
-			CacheDir:       cacheDir,
-			SourceRepo:     sourceRepo,
-			SourceRef:      cfg.Source.Ref,
-			ProfileID:      cfg.Source.Profile,
-			MarkerPrefix:   cfg.Sync.ManagedBlockPrefix,
-			AddendaHeading: cfg.Sync.LocalAddendaHeading,
-		})
-		if err != nil {
-			fmt.Fprintf(stderr, "build failed: %v\n", err)
+		opts := applicationOptions(cfg, resolvedConfigPath, repoRoot, cacheDir, app)
+		switch cmd {
+		case "build":
+			out := *outDir
+			if bundle != nil {
+				out = ""
+				opts = append(opts, govkit.WithFS(bundle))
+			}
+			res, err := govkit.Build(ctx, out, opts...)
+			if err != nil {
+				fmt.Fprintf(stderr, "%sbuild failed: %v\n", label, err)
+				failed = true
+				continue
+			}
+			if bundle != nil {
+				p := govkit.BundleProfile{Profile: app.Profile, SourceRepo: app.Repo, SourceRef: app.Ref, SourceCommit: res.SourceCommit, DocsRoot: app.DocsRoot}
+				if len(apps) > 1 {
+					p.Name = app.Name
+				}
+				bundle.Record(p)
+			}
+			fmt.Fprintf(stdout, "%sbuilt %d doc(s) and %d file(s) (sourceCommit=%s)\n", label, res.DocsWritten, res.FilesWritten, res.SourceCommit)
+		case "init":
+			res, err := govkit.Init(ctx, opts...)
+			if err != nil {
+				fmt.Fprintf(stderr, "%sinit failed: %v\n", label, err)
+				failed = true
+				continue
+			}
+			fmt.Fprintf(stdout, "%sinitialized %d doc(s) and %d file(s)\n", label, res.DocsWritten, res.FilesWritten)
+		case "sync":
+			// Applications are synced together below, as one commit.
+			syncApps = append(syncApps, opts)
+			syncLabels = append(syncLabels, label)
+		case "diff":
+			res, err := govkit.Diff(ctx, opts...)
+			if err != nil {
+				fmt.Fprintf(stderr, "%sdiff failed: %v\n", label, err)
+				failed = true
+				continue
+			}
+			if !res.Changed() {
+				fmt.Fprintf(stdout, "%sno changes\n", label)
+			}
+			for _, f := range res.Files {
+				fmt.Fprint(stdout, f.Unified())
+			}
+		case "verify":
+			res, err := govkit.Verify(ctx, opts...)
+			if err != nil {
+				fmt.Fprintf(stderr, "%sverify failed: %v\n", label, err)
+				failed = true
+				continue
+			}
+			if len(apps) > 1 && res.OK {
+				fmt.Fprintf(stdout, "%sok\n", label)
+			}
+			for _, issue := range res.Issues {
+				issues = append(issues, issuePrefix+issue.String())
+			}
+		default:
+			fmt.Fprintf(stderr, "internal error: unhandled command %s\n", cmd)
 			return 1
 		}
-		fmt.Fprintf(stdout, "built %d doc(s) and %d file(s) (sourceCommit=%s)\n", res.DocsWritten, res.ExtraFilesWritten, res.SourceCommit)
-		return 0
-	case "init":
-		cfg, err := config.Load(resolvedConfigPath)
-		if err != nil {
-			fmt.Fprintf(stderr, "config error: %v\n", err)
-			return 2
-		}
-		cacheDir, err := cfg.CacheDir()
-		if err != nil {
-			fmt.Fprintf(stderr, "cache dir error: %v\n", err)
-			return 2
-		}
-		sourceRepo := resolveRepoPathIfLocal(resolvedConfigPath, cfg.Source.Repo)
-		repoRoot := repoRootForConfig(resolvedConfigPath)
-		res, err := builder.Init(context.Background(), builder.InitOptions{
-			RepoRoot:       repoRoot,
-			DocsRoot:       cfg.Paths.DocsRoot,
-			CacheDir:       cacheDir,
-			SourceRepo:     sourceRepo,
-			SourceRef:      cfg.Source.Ref,
-			ProfileID:      cfg.Source.Profile,
-			MarkerPrefix:   cfg.Sync.ManagedBlockPrefix,
-			AddendaHeading: cfg.Sync.LocalAddendaHeading,
-		})
-		if err != nil {
-			fmt.Fprintf(stderr, "init failed: %v\n", err)
+	}
+
+	if cmd == "sync" {
+		res, err := govkit.SyncAll(ctx, syncApps...)
+		var se *govkit.SyncError
+		switch {
+		case errors.As(err, &se):
+			for i, appErr := range se.Errs {
+				if appErr != nil {
+					fmt.Fprintf(stderr, "%ssync failed: %v\n", syncLabels[i], appErr)
+				}
+			}
+			if len(syncApps) > 1 {
+				fmt.Fprintln(stderr, "no files were changed")
+			}
 			return 1
-		}
-		fmt.Fprintf(stdout, "initialized %d doc(s) and %d file(s)\n", res.DocsWritten, res.ExtraFilesWritten)
-		return 0
-	case "sync":
-		cfg, err := config.Load(resolvedConfigPath)
-		if err != nil {
+		case errors.Is(err, govkit.ErrOverlappingOutputs):
 			fmt.Fprintf(stderr, "config error: %v\n", err)
 			return 2
-		}
-		cacheDir, err := cfg.CacheDir()
-		if err != nil {
-			fmt.Fprintf(stderr, "cache dir error: %v\n", err)
-			return 2
-		}
-		sourceRepo := resolveRepoPathIfLocal(resolvedConfigPath, cfg.Source.Repo)
-		repoRoot := repoRootForConfig(resolvedConfigPath)
-		res, err := builder.Sync(context.Background(), builder.SyncOptions{
-			RepoRoot:     repoRoot,
-			DocsRoot:     cfg.Paths.DocsRoot,
-			CacheDir:     cacheDir,
-			SourceRepo:   sourceRepo,
-			SourceRef:    cfg.Source.Ref,
-			ProfileID:    cfg.Source.Profile,
-			MarkerPrefix: cfg.Sync.ManagedBlockPrefix,
-		})
-		if err != nil {
+		case err != nil:
 			fmt.Fprintf(stderr, "sync failed: %v\n", err)
+			if errors.Is(err, govkit.ErrInterruptedSync) {
+				fmt.Fprintln(stderr, "run `agent-gov sync --resume` to finish it or `agent-gov sync --revert` to undo it")
+			}
 			return 1
 		}
-		fmt.Fprintf(stdout, "synced %d doc(s)\n", res.DocsUpdated)
-		return 0
-	case "verify":
-		cfg, err := config.Load(resolvedConfigPath)
-		if err != nil {
-			fmt.Fprintf(stderr, "config error: %v\n", err)
-			return 2
-		}
-		cacheDir, err := cfg.CacheDir()
-		if err != nil {
-			fmt.Fprintf(stderr, "cache dir error: %v\n", err)
-			return 2
+		for i, r := range res {
+			fmt.Fprintf(stdout, "%ssynced %d doc(s)\n", syncLabels[i], r.DocsUpdated)
 		}
-		sourceRepo := resolveRepoPathIfLocal(resolvedConfigPath, cfg.Source.Repo)
-		repoRoot := repoRootForConfig(resolvedConfigPath)
-		res, err := builder.Verify(context.Background(), builder.VerifyOptions{
-			RepoRoot:     repoRoot,
-			DocsRoot:     cfg.Paths.DocsRoot,
-			CacheDir:     cacheDir,
-			SourceRepo:   sourceRepo,
-			SourceRef:    cfg.Source.Ref,
-			ProfileID:    cfg.Source.Profile,
-			MarkerPrefix: cfg.Sync.ManagedBlockPrefix,
-		})
-		if err != nil {
-			fmt.Fprintf(stderr, "verify failed: %v\n", err)
+	}
+
+	if bundle != nil && !failed {
+		if err := writeBundle(bundle, &archive, *outDir); err != nil {
+			fmt.Fprintf(stderr, "build failed: %v\n", err)
 			return 1
 		}
-		if res.OK {
-			fmt.Fprintln(stdout, "ok")
-			return 0
-		}
-		fmt.Fprintf(stderr, "verification failed: %d issue(s)\n", len(res.Issues))
-		for _, issue := range res.Issues {
+		fmt.Fprintf(stdout, "wrote %s\n", *outDir)
+	}
+
+	if len(issues) > 0 {
+		fmt.Fprintf(stderr, "verification failed: %d issue(s)\n", len(issues))
+		for _, issue := range issues {
 			fmt.Fprintf(stderr, "- %s\n", issue)
 		}
 		return 1
-	default:
-		fmt.Fprintf(stderr, "internal error: unhandled command %s\n", cmd)
+	}
+	if failed {
 		return 1
 	}
+	if cmd == "verify" && len(apps) == 1 {
+		fmt.Fprintln(stdout, "ok")
+	}
+	return 0
+}
+
+// applicationOptions are the govkit options that apply one profile application of cfg to
+// the repository at repoRoot.
+func applicationOptions(cfg config.Config, configPath, repoRoot, cacheDir string, app config.ApplicationConfig) []govkit.Option {
+	return []govkit.Option{
+		govkit.WithSource(resolveRepoPathIfLocal(configPath, app.Repo), app.Ref, app.Profile),
+		govkit.WithRepoRoot(repoRoot),
+		govkit.WithDocsRoot(app.DocsRoot),
+		govkit.WithCacheDir(cacheDir),
+		govkit.WithMarkerPrefix(cfg.Sync.ManagedBlockPrefix),
+		govkit.WithAddendaHeading(cfg.Sync.LocalAddendaHeading),
+		govkit.WithHashNormalization(cfg.Sync.HashNormalization),
+		govkit.WithVariables(app.Variables),
+		govkit.WithParameters(app.Parameters),
+		govkit.WithFacts(conditions.Detect(repoRoot, cfg.Features, app.DocsRoot)),
+	}
+}
+
+// selectApplication returns the profile application called name, or the only one of apps
+// when name is empty.
+func selectApplication(apps []config.ApplicationConfig, name string) (config.ApplicationConfig, error) {
+	names := make([]string, 0, len(apps))
+	for _, app := range apps {
+		if strings.TrimSpace(name) != "" && app.Name == name {
+			return app, nil
+		}
+		names = append(names, app.Name)
+	}
+	if strings.TrimSpace(name) != "" {
+		return config.ApplicationConfig{}, fmt.Errorf("unknown application %q (have: %s)", name, strings.Join(names, ", "))
+	}
+	if len(apps) != 1 {
+		return config.ApplicationConfig{}, fmt.Errorf("config has %d applications (%s); choose one with --application NAME", len(apps), strings.Join(names, ", "))
+	}
+	return apps[0], nil
+}
+
+// writeBundle closes the bundle into archive and writes it to path.
+func writeBundle(bundle *govkit.Bundle, archive *bytes.Buffer, path string) error {
+	if err := bundle.Close(); err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, archive.Bytes(), 0o644)
+}
+
+// runSyncRecovery finishes or undoes a sync interrupted while replacing files.
+func runSyncRecovery(repoRoot string, resume bool, stdout, stderr io.Writer) int {
+	if resume {
+		n, err := govkit.ResumeSync(govkit.WithRepoRoot(repoRoot))
+		if err != nil {
+			fmt.Fprintf(stderr, "resume failed: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "resumed sync: replaced %d file(s)\n", n)
+		return 0
+	}
+	n, err := govkit.RevertSync(govkit.WithRepoRoot(repoRoot))
+	if err != nil {
+		fmt.Fprintf(stderr, "revert failed: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "reverted sync: restored %d file(s)\n", n)
+	return 0
 }
 
 func resolveConfigPath(configPath string, args []string) (string, bool, error) {
@@ -299,11 +436,40 @@ func printUsage(w io.Writer) {
 	fmt.Fprintln(w, "  init     Initialize governance docs in this repo")
 	fmt.Fprintln(w, "  sync     Update managed governance blocks in-place")
 	fmt.Fprintln(w, "  verify   Verify managed governance blocks match expected content")
-	fmt.Fprintln(w, "  build    Assemble governance bundle into an output folder")
+	fmt.Fprintln(w, "  diff     Show what sync would change, without writing")
+	fmt.Fprintln(w, "  repair   Fix damaged managed-block markers (missing END, duplicate BEGIN)")
+	fmt.Fprintln(w, "  build    Assemble governance bundle into an output folder or archive")
+	fmt.Fprintln(w, "  commitmsg Check a commit message file against the commit policy (check FILE)")
+	fmt.Fprintln(w, "  gate     Run a quality gate (coverage --profile FILE)")
+	fmt.Fprintln(w, "  gates    Run the profile's declared quality gates (run [--only NAME] [--summary FILE])")
+	fmt.Fprintln(w, "  hooks    Install, uninstall, or inspect managed git hooks (install|uninstall|status)")
+	fmt.Fprintln(w, "  profiles List available profiles or show a resolved profile (list | show ID) [--ref REF]")
+	fmt.Fprintln(w, "  profile  Validate profiles in a governance source repo (validate [--all | ID...])")
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Global options:")
 	fmt.Fprintf(w, "  --config PATH   Path to config (default %s; auto-discovers upward when omitted)\n", defaultConfigPath)
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Build options:")
-	fmt.Fprintln(w, "  --out DIR       Output directory (required)")
+	fmt.Fprintln(w, "  --out DIR       Output directory (required); a name ending in .tar.gz, .tgz or .zip")
+	fmt.Fprintln(w, "                  writes a reproducible archive with an agent-gov-bundle.json manifest")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Sync options:")
+	fmt.Fprintln(w, "  --resume        Finish a sync that was interrupted while replacing files")
+	fmt.Fprintln(w, "  --revert        Undo a sync that was interrupted while replacing files")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Repair options:")
+	fmt.Fprintln(w, "  --yes           Apply every proposed fix without asking")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Gate coverage options:")
+	fmt.Fprintln(w, "  --profile FILE  Go cover profile or lcov tracefile (required)")
+	fmt.Fprintln(w, "  --format FMT    auto (default), go, or lcov")
+	fmt.Fprintln(w, "  --threshold N   Override the profile manifest coverage.threshold (exclusions still apply)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Hooks options:")
+	fmt.Fprintln(w, "  --bin CMD       agent-gov command invoked by installed hooks (default agent-gov)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Profile validate options:")
+	fmt.Fprintln(w, "  --root DIR      Governance source repo root (default .)")
+	fmt.Fprintln(w, "  --all           Validate every profile and report unused Core/Templates files")
+	fmt.Fprintln(w, "  --cache-dir DIR Cache for cross-repository extends (default: user cache dir)")
 }
@@ -456,3 +456,18 @@ sync:
 		t.Fatalf("expected sync failed, got:\n%s", errOut.String())
 	}
 }
+
+func TestRun_SyncRecoveryFlagsAreExclusiveAndSyncOnly(t *testing.T) {
+	for _, args := range [][]string{
+		{"agent-gov", "sync", "--resume", "--revert"},
+		{"agent-gov", "verify", "--resume"},
+	} {
+		var out, errOut bytes.Buffer
+		if code := Run(args, &out, &errOut); code != 2 {
+			t.Fatalf("%v: expected 2, got %d", args, code)
+		}
+		if !strings.Contains(errOut.String(), "only valid for sync") {
+			t.Fatalf("%v: unexpected stderr:\n%s", args, errOut.String())
+		}
+	}
+}
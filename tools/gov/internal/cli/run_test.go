@@ -194,6 +194,214 @@ sync:
 	}
 }
 
+func TestRun_InitVerify_MultipleProfileApplications(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	cache := filepath.Join(tmp, "cache")
+	target := filepath.Join(tmp, "target")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	for _, id := range []string{"backend", "ios"} {
+		writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", id, "Rules.md"), strings.ToUpper(id)+"\n")
+		writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", id, "profile.yaml"), "schemaVersion: 1\nid: "+id+"\ndocuments:\n  - output: Rules.md\n    fragments: [./Rules.md]\n")
+	}
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+	mustRun(t, srcRepo, "git", "tag", "v1")
+
+	cfgPath := filepath.Join(target, ".governance", "config.yaml")
+	writeFile(t, cfgPath, strings.TrimSpace(`
+schemaVersion: 1
+source:
+  repo: `+srcRepo+`
+  ref: v1
+paths:
+  cacheDir: `+cache+`
+applications:
+  - profile: backend
+    docsRoot: services/api
+  - profile: ios
+    docsRoot: apps/ios
+`)+"\n")
+
+	var outBuf, errBuf bytes.Buffer
+	if code := Run([]string{"agent-gov", "init", "--config", cfgPath}, &outBuf, &errBuf); code != 0 {
+		t.Fatalf("init code=%d stderr=%s", code, errBuf.String())
+	}
+	if outBuf.String() != "[backend] initialized 1 doc(s) and 0 file(s)\n[ios] initialized 1 doc(s) and 0 file(s)\n" {
+		t.Fatalf("unexpected init output: %q", outBuf.String())
+	}
+	for path, want := range map[string]string{"services/api/Rules.md": "BACKEND", "apps/ios/Rules.md": "IOS"} {
+		b, err := os.ReadFile(filepath.Join(target, filepath.FromSlash(path)))
+		if err != nil || !strings.Contains(string(b), want) {
+			t.Fatalf("expected %s in %s: %v", want, path, err)
+		}
+	}
+
+	// Tampering with one application's block is reported under its name.
+	iosDoc := filepath.Join(target, "apps", "ios", "Rules.md")
+	b, _ := os.ReadFile(iosDoc)
+	writeFile(t, iosDoc, strings.Replace(string(b), "IOS", "EDITED", 1))
+	outBuf.Reset()
+	errBuf.Reset()
+	if code := Run([]string{"agent-gov", "verify", "--config", cfgPath}, &outBuf, &errBuf); code != 1 {
+		t.Fatalf("expected verify failure, code=%d stderr=%s", code, errBuf.String())
+	}
+	if outBuf.String() != "[backend] ok\n" || !strings.Contains(errBuf.String(), "verification failed: 1 issue(s)\n- ios: Rules.md:") {
+		t.Fatalf("unexpected verify output: stdout=%q stderr=%q", outBuf.String(), errBuf.String())
+	}
+}
+
+func TestRun_Sync_MultipleApplicationsIsAllOrNothing(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	target := filepath.Join(tmp, "target")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	release := func(tag string) {
+		for _, id := range []string{"backend", "ios"} {
+			writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", id, "Rules.md"), id+" "+tag+"\n")
+			writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", id, "profile.yaml"), "schemaVersion: 1\nid: "+id+"\ndocuments:\n  - output: Rules.md\n    fragments: [./Rules.md]\n")
+		}
+		mustRun(t, srcRepo, "git", "add", ".")
+		mustRun(t, srcRepo, "git", "commit", "-m", tag)
+		mustRun(t, srcRepo, "git", "tag", tag)
+	}
+	release("v1")
+	release("v2")
+
+	cfgPath := filepath.Join(target, ".governance", "config.yaml")
+	config := func(ref string) {
+		writeFile(t, cfgPath, "schemaVersion: 1\nsource:\n  repo: "+srcRepo+"\n  ref: "+ref+"\npaths:\n  cacheDir: "+filepath.Join(tmp, "cache")+"\napplications:\n  - profile: backend\n    docsRoot: services/api\n  - profile: ios\n    docsRoot: apps/ios\n")
+	}
+	config("v1")
+	var outBuf, errBuf bytes.Buffer
+	if code := Run([]string{"agent-gov", "init", "--config", cfgPath}, &outBuf, &errBuf); code != 0 {
+		t.Fatalf("init code=%d stderr=%s", code, errBuf.String())
+	}
+
+	// The ios document lost its block, so neither application is synced.
+	config("v2")
+	iosDoc := filepath.Join(target, "apps", "ios", "Rules.md")
+	writeFile(t, iosDoc, "rewritten by hand\n")
+	apiDoc := filepath.Join(target, "services", "api", "Rules.md")
+	api := string(readFileBytes(t, apiDoc))
+	outBuf.Reset()
+	errBuf.Reset()
+	if code := Run([]string{"agent-gov", "sync", "--config", cfgPath}, &outBuf, &errBuf); code != 1 {
+		t.Fatalf("expected sync failure, code=%d stdout=%s", code, outBuf.String())
+	}
+	if !strings.Contains(errBuf.String(), "[ios] sync failed: ") || strings.Contains(errBuf.String(), "[backend]") || !strings.Contains(errBuf.String(), "no files were changed") {
+		t.Fatalf("unexpected stderr:\n%s", errBuf.String())
+	}
+	if got := string(readFileBytes(t, apiDoc)); got != api || !strings.Contains(got, "backend v1") {
+		t.Fatalf("expected services/api/Rules.md unchanged:\n%s", got)
+	}
+
+	// Once every application renders, all of them are synced.
+	if err := os.RemoveAll(filepath.Join(target, "apps")); err != nil {
+		t.Fatal(err)
+	}
+	config("v1")
+	if code := Run([]string{"agent-gov", "init", "--config", cfgPath}, &outBuf, &errBuf); code != 0 {
+		t.Fatalf("init code=%d stderr=%s", code, errBuf.String())
+	}
+	config("v2")
+	outBuf.Reset()
+	if code := Run([]string{"agent-gov", "sync", "--config", cfgPath}, &outBuf, &errBuf); code != 0 {
+		t.Fatalf("sync code=%d stderr=%s", code, errBuf.String())
+	}
+	if outBuf.String() != "[backend] synced 1 doc(s)\n[ios] synced 1 doc(s)\n" {
+		t.Fatalf("unexpected sync output: %q", outBuf.String())
+	}
+	if !strings.Contains(string(readFileBytes(t, apiDoc)), "backend v2") || !strings.Contains(string(readFileBytes(t, iosDoc)), "ios v2") {
+		t.Fatalf("expected both applications synced")
+	}
+}
+
+func TestRun_Init_DetectsFactsPerApplication(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	target := filepath.Join(tmp, "target")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "svc", "Rules.md"), "RULES\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "svc", "Go.md"), "GO RULES\n")
+	writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", "svc", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: svc
+documents:
+  - output: Rules.md
+    fragments:
+      - ./Rules.md
+      - path: ./Go.md
+        when: go
+`)+"\n")
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+	mustRun(t, srcRepo, "git", "tag", "v1")
+
+	// Only services/api is a Go module.
+	writeFile(t, filepath.Join(target, "services", "api", "go.mod"), "module api\n")
+	cfgPath := filepath.Join(target, ".governance", "config.yaml")
+	writeFile(t, cfgPath, "schemaVersion: 1\nsource:\n  repo: "+srcRepo+"\n  ref: v1\npaths:\n  cacheDir: "+filepath.Join(tmp, "cache")+"\napplications:\n  - name: api\n    profile: svc\n    docsRoot: services/api\n  - name: web\n    profile: svc\n    docsRoot: services/web\n")
+
+	var outBuf, errBuf bytes.Buffer
+	if code := Run([]string{"agent-gov", "init", "--config", cfgPath}, &outBuf, &errBuf); code != 0 {
+		t.Fatalf("init code=%d stderr=%s", code, errBuf.String())
+	}
+	api, _ := os.ReadFile(filepath.Join(target, "services", "api", "Rules.md"))
+	web, _ := os.ReadFile(filepath.Join(target, "services", "web", "Rules.md"))
+	if !strings.Contains(string(api), "GO RULES") || !strings.Contains(string(api), "when=go:true") {
+		t.Fatalf("expected Go rules for api:\n%s", api)
+	}
+	if strings.Contains(string(web), "GO RULES") || !strings.Contains(string(web), "when=go:false") {
+		t.Fatalf("expected no Go rules for web:\n%s", web)
+	}
+	outBuf.Reset()
+	errBuf.Reset()
+	if code := Run([]string{"agent-gov", "verify", "--config", cfgPath}, &outBuf, &errBuf); code != 0 {
+		t.Fatalf("verify code=%d stdout=%s stderr=%s", code, outBuf.String(), errBuf.String())
+	}
+}
+
+func TestRun_Init_RejectsOverlappingApplications(t *testing.T) {
+	tmp := t.TempDir()
+	srcRepo := filepath.Join(tmp, "govsrc")
+	target := filepath.Join(tmp, "target")
+
+	mustRun(t, tmp, "git", "init", srcRepo)
+	mustRun(t, srcRepo, "git", "config", "user.email", "test@example.com")
+	mustRun(t, srcRepo, "git", "config", "user.name", "Test")
+	for _, id := range []string{"a", "b"} {
+		writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", id, "Rules.md"), id+"\n")
+		writeFile(t, filepath.Join(srcRepo, "Governance", "Profiles", id, "profile.yaml"), "schemaVersion: 1\nid: "+id+"\ndocuments:\n  - output: Rules.md\n    fragments: [./Rules.md]\n")
+	}
+	mustRun(t, srcRepo, "git", "add", ".")
+	mustRun(t, srcRepo, "git", "commit", "-m", "v1")
+	mustRun(t, srcRepo, "git", "tag", "v1")
+
+	cfgPath := filepath.Join(target, ".governance", "config.yaml")
+	writeFile(t, cfgPath, "schemaVersion: 1\nsource:\n  repo: "+srcRepo+"\n  ref: v1\npaths:\n  cacheDir: "+filepath.Join(tmp, "cache")+"\napplications:\n  - profile: a\n    docsRoot: docs\n  - profile: b\n    docsRoot: ./docs/\n")
+
+	var outBuf, errBuf bytes.Buffer
+	if code := Run([]string{"agent-gov", "init", "--config", cfgPath}, &outBuf, &errBuf); code != 2 {
+		t.Fatalf("expected config error, code=%d stderr=%s", code, errBuf.String())
+	}
+	if !strings.Contains(errBuf.String(), "overlapping outputs: docs/Rules.md is written by both a and b") {
+		t.Fatalf("unexpected stderr: %s", errBuf.String())
+	}
+	if _, err := os.Stat(filepath.Join(target, "docs", "Rules.md")); err == nil {
+		t.Fatalf("expected no files written")
+	}
+}
+
 func writeFile(t *testing.T, path, content string) {
 	t.Helper()
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -0,0 +1,172 @@
+package commitmsg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	ConventionConventional = "conventional"
+	ConventionNone         = "none"
+)
+
+// DefaultTypes are the Conventional Commits types accepted when a policy does not list its own.
+var DefaultTypes = []string{"feat", "fix", "docs", "chore", "refactor", "test", "build", "ci", "perf", "style", "revert"}
+
+// Policy describes how commit messages are validated.
+type Policy struct {
+	// Convention is "conventional" or "none".
+	Convention string
+	// Types are the allowed commit types (conventional only). Empty means DefaultTypes.
+	Types []string
+	// BranchType is the `type` segment of a `type/area-short-slug` branch. When
+	// MatchBranchType is set, the commit type must equal it.
+	BranchType      string
+	MatchBranchType bool
+	// RequireCheckpoint requires a "Checkpoint N" reference that exists in Checkpoints.
+	RequireCheckpoint bool
+	// Checkpoints are the checkpoint labels declared in the branch plan (e.g. "1", "2", "final").
+	Checkpoints []string
+}
+
+var (
+	conventionalSubjectRe = regexp.MustCompile(`^([a-z][a-z0-9-]*)(\([^()]+\))?(!)?: \S`)
+	checkpointRefRe       = regexp.MustCompile(`(?i)\b(?:checkpoint\s+(\d+|final)|(final)\s+checkpoint)\b`)
+	planCheckpointRe      = regexp.MustCompile(`(?i)^\s*[-*]\s+\[[ x]\]\s+(?:checkpoint\s+(\d+)|(final)\s+checkpoint)\b`)
+)
+
+// Check validates msg against p and returns a list of problems (empty when valid).
+func Check(msg string, p Policy) []string {
+	subject, body := splitMessage(msg)
+	if subject == "" {
+		return []string{"commit message is empty"}
+	}
+	if isGitGenerated(subject) {
+		return nil
+	}
+
+	var problems []string
+	switch p.Convention {
+	case "", ConventionConventional:
+		problems = append(problems, checkConventional(subject, p)...)
+	case ConventionNone:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown commit convention %q", p.Convention))
+	}
+
+	if p.RequireCheckpoint {
+		problems = append(problems, checkCheckpoint(subject+"\n"+body, p.Checkpoints)...)
+	}
+	return problems
+}
+
+func checkConventional(subject string, p Policy) []string {
+	m := conventionalSubjectRe.FindStringSubmatch(subject)
+	if m == nil {
+		return []string{fmt.Sprintf("subject %q does not follow Conventional Commits (`type(scope): summary`)", subject)}
+	}
+	typ := m[1]
+	types := p.Types
+	if len(types) == 0 {
+		types = DefaultTypes
+	}
+	if !contains(types, typ) {
+		return []string{fmt.Sprintf("commit type %q is not allowed (allowed: %s)", typ, strings.Join(types, ", "))}
+	}
+	if p.MatchBranchType {
+		if p.BranchType == "" {
+			return []string{"branch does not follow `type/area-short-slug`, so the commit type cannot be checked"}
+		}
+		if typ != p.BranchType {
+			return []string{fmt.Sprintf("commit type %q does not match branch type %q", typ, p.BranchType)}
+		}
+	}
+	return nil
+}
+
+func checkCheckpoint(msg string, checkpoints []string) []string {
+	m := checkpointRefRe.FindStringSubmatch(msg)
+	if m == nil {
+		return []string{"commit message must reference a plan checkpoint (e.g. `Checkpoint 2` or `Final checkpoint`)"}
+	}
+	ref := strings.ToLower(m[1] + m[2])
+	if !contains(checkpoints, ref) {
+		if len(checkpoints) == 0 {
+			return []string{fmt.Sprintf("checkpoint %q referenced but the branch plan declares no checkpoints", ref)}
+		}
+		return []string{fmt.Sprintf("checkpoint %q is not declared in the branch plan (declared: %s)", ref, strings.Join(checkpoints, ", "))}
+	}
+	return nil
+}
+
+// PlanCheckpoints returns the checkpoint labels declared in a plan's checkpoint list,
+// e.g. "- [ ] Checkpoint 1 — ..." yields "1" and "- [ ] Final checkpoint — ..." yields "final".
+func PlanCheckpoints(plan string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, line := range strings.Split(plan, "\n") {
+		m := planCheckpointRe.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		label := m[1]
+		if label == "" {
+			label = strings.ToLower(m[2])
+		}
+		if !seen[label] {
+			seen[label] = true
+			out = append(out, label)
+		}
+	}
+	return out
+}
+
+// BranchType returns the `type` segment of a `type/area-short-slug` branch name.
+func BranchType(branch string) string {
+	typ, rest, ok := strings.Cut(strings.TrimSpace(branch), "/")
+	if !ok || typ == "" || rest == "" {
+		return ""
+	}
+	return typ
+}
+
+// splitMessage strips git comment lines and anything below the scissors line,
+// returning the subject (first non-empty line) and the remaining body.
+func splitMessage(msg string) (string, string) {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(msg, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "# ") && strings.Contains(line, ">8") {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		return strings.TrimSpace(line), strings.Join(lines[i+1:], "\n")
+	}
+	return "", ""
+}
+
+func isGitGenerated(subject string) bool {
+	for _, prefix := range []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(subject, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
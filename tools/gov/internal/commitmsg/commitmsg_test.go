@@ -0,0 +1,115 @@
+package commitmsg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheck_Conventional(t *testing.T) {
+	cases := []struct {
+		msg  string
+		ok   bool
+		want string
+	}{
+		{msg: "feat(identity): add sign-up\n\nbody\n", ok: true},
+		{msg: "fix!: drop legacy flag\n", ok: true},
+		{msg: "Merge branch 'main' into feat/x\n", ok: true},
+		{msg: "fixup! feat: thing\n", ok: true},
+		{msg: "Add sign-up\n", want: "Conventional Commits"},
+		{msg: "feature: add sign-up\n", want: "not allowed"},
+		{msg: "# only comments\n\n", want: "empty"},
+	}
+	for _, tc := range cases {
+		problems := Check(tc.msg, Policy{Convention: ConventionConventional})
+		if tc.ok {
+			if len(problems) != 0 {
+				t.Fatalf("msg=%q expected ok, got %v", tc.msg, problems)
+			}
+			continue
+		}
+		if len(problems) == 0 || !strings.Contains(strings.Join(problems, "; "), tc.want) {
+			t.Fatalf("msg=%q expected problem containing %q, got %v", tc.msg, tc.want, problems)
+		}
+	}
+}
+
+func TestCheck_IgnoresCommentsAndScissors(t *testing.T) {
+	msg := strings.Join([]string{
+		"# Please enter the commit message",
+		"docs: update readme",
+		"",
+		"# ------------------------ >8 ------------------------",
+		"diff --git a/x b/x",
+	}, "\n")
+	if problems := Check(msg, Policy{}); len(problems) != 0 {
+		t.Fatalf("expected ok, got %v", problems)
+	}
+}
+
+func TestCheck_MatchBranchType(t *testing.T) {
+	p := Policy{Convention: ConventionConventional, MatchBranchType: true, BranchType: "fix"}
+	if problems := Check("fix: handle nil\n", p); len(problems) != 0 {
+		t.Fatalf("expected ok, got %v", problems)
+	}
+	problems := Check("feat: handle nil\n", p)
+	if len(problems) != 1 || !strings.Contains(problems[0], `branch type "fix"`) {
+		t.Fatalf("expected branch type mismatch, got %v", problems)
+	}
+	p.BranchType = ""
+	if problems := Check("fix: handle nil\n", p); len(problems) != 1 {
+		t.Fatalf("expected problem when branch type unknown, got %v", problems)
+	}
+}
+
+func TestCheck_RequireCheckpoint(t *testing.T) {
+	p := Policy{Convention: ConventionNone, RequireCheckpoint: true, Checkpoints: []string{"1", "2", "final"}}
+	if problems := Check("Add parser\n\nCheckpoint 2\n", p); len(problems) != 0 {
+		t.Fatalf("expected ok, got %v", problems)
+	}
+	if problems := Check("wrap up (final checkpoint)\n\nFinal Checkpoint\n", p); len(problems) != 0 {
+		t.Fatalf("expected ok, got %v", problems)
+	}
+	if problems := Check("Add parser\n", p); len(problems) != 1 || !strings.Contains(problems[0], "must reference") {
+		t.Fatalf("expected missing reference, got %v", problems)
+	}
+	if problems := Check("Add parser (checkpoint 7)\n", p); len(problems) != 1 || !strings.Contains(problems[0], "not declared") {
+		t.Fatalf("expected undeclared checkpoint, got %v", problems)
+	}
+}
+
+func TestCheck_UnknownConvention(t *testing.T) {
+	if problems := Check("x\n", Policy{Convention: "gitmoji"}); len(problems) != 1 {
+		t.Fatalf("expected problem, got %v", problems)
+	}
+}
+
+func TestPlanCheckpoints(t *testing.T) {
+	plan := strings.Join([]string{
+		"## Checkpoints",
+		"",
+		"- [x] Checkpoint 1 — parser",
+		"- [ ] Checkpoint 2 — wiring",
+		"- [ ] Final checkpoint — PR wrap-up (final approval gate)",
+		"",
+		"## Completion checklist",
+		"- [ ] Check off completed checkpoint(s) above",
+	}, "\n")
+	got := strings.Join(PlanCheckpoints(plan), ",")
+	if got != "1,2,final" {
+		t.Fatalf("expected 1,2,final got %q", got)
+	}
+}
+
+func TestBranchType(t *testing.T) {
+	cases := map[string]string{
+		"feat/identity-add-foo": "feat",
+		"refactor/app-x":        "refactor",
+		"main":                  "",
+		"feat/":                 "",
+	}
+	for branch, want := range cases {
+		if got := BranchType(branch); got != want {
+			t.Fatalf("branch=%q expected %q got %q", branch, want, got)
+		}
+	}
+}
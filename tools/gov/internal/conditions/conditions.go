@@ -0,0 +1,134 @@
+package conditions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Detected facts and the repo paths that make them true.
+var detectors = []struct {
+	Name string
+	Path string
+}{
+	{Name: "go", Path: "go.mod"},
+	{Name: "gitlab", Path: ".gitlab-ci.yml"},
+	{Name: "github", Path: ".github/workflows"},
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+// Facts are the named booleans a `when:` condition is evaluated against.
+type Facts map[string]bool
+
+// Detect returns facts auto-detected from repoRoot and the given directories under it (a
+// fact holds when its path exists in any of them), overridden by the config-declared
+// features. Pass an application's docs root so a monorepo's facts are per application.
+func Detect(repoRoot string, features map[string]bool, dirs ...string) Facts {
+	facts := Facts{}
+	roots := []string{repoRoot}
+	for _, dir := range dirs {
+		roots = append(roots, filepath.Join(repoRoot, filepath.FromSlash(dir)))
+	}
+	for _, d := range detectors {
+		facts[d.Name] = existsIn(roots, d.Path)
+	}
+	for k, v := range features {
+		facts[k] = v
+	}
+	return facts
+}
+
+func existsIn(roots []string, path string) bool {
+	for _, root := range roots {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(path))); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Eval evaluates expr against facts. An empty expression is true.
+//
+// Grammar: terms joined by `&&` and `||` (`&&` binds tighter), each term a fact name
+// optionally negated with `!`. Facts that are neither detected nor declared are false.
+func Eval(expr string, facts Facts) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+	for _, clause := range strings.Split(expr, "||") {
+		all := true
+		for _, term := range strings.Split(clause, "&&") {
+			name, negated, err := parseTerm(term)
+			if err != nil {
+				return false, fmt.Errorf("condition %q: %w", expr, err)
+			}
+			if facts[name] == negated {
+				all = false
+			}
+		}
+		if all {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Validate reports syntax errors in expr.
+func Validate(expr string) error {
+	_, err := Names(expr)
+	return err
+}
+
+// Names returns the sorted, de-duplicated fact names referenced by expr.
+func Names(expr string) ([]string, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	seen := map[string]bool{}
+	var names []string
+	for _, clause := range strings.Split(expr, "||") {
+		for _, term := range strings.Split(clause, "&&") {
+			name, _, err := parseTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("condition %q: %w", expr, err)
+			}
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Record formats the evaluated facts for names as `a:true,b:false` (sorted) for block metadata.
+func Record(names []string, facts Facts) string {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	var parts []string
+	for i, n := range sorted {
+		if i > 0 && sorted[i-1] == n {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s:%t", n, facts[n]))
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseTerm(term string) (string, bool, error) {
+	term = strings.TrimSpace(term)
+	negated := false
+	if strings.HasPrefix(term, "!") {
+		negated = true
+		term = strings.TrimSpace(strings.TrimPrefix(term, "!"))
+	}
+	if !namePattern.MatchString(term) {
+		return "", false, fmt.Errorf("invalid term %q", term)
+	}
+	return term, negated, nil
+}
@@ -0,0 +1,90 @@
+package conditions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetect_FindsRepoFactsAndAppliesFeatureOverrides(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module x\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".github", "workflows"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	facts := Detect(root, map[string]bool{"openapi": true, "github": false})
+	if !facts["go"] || facts["gitlab"] || facts["github"] || !facts["openapi"] {
+		t.Fatalf("unexpected facts: %v", facts)
+	}
+}
+
+func TestEval(t *testing.T) {
+	facts := Facts{"go": true, "gitlab": false, "openapi": true}
+	cases := map[string]bool{
+		"":                         true,
+		"go":                       true,
+		"!go":                      false,
+		"gitlab":                   false,
+		"undeclared":               false,
+		"go && openapi":            true,
+		"go && !openapi":           false,
+		"gitlab || go":             true,
+		"gitlab || !go":            false,
+		"gitlab && go || ! gitlab": true,
+	}
+	for expr, want := range cases {
+		got, err := Eval(expr, facts)
+		if err != nil {
+			t.Fatalf("%q: %v", expr, err)
+		}
+		if got != want {
+			t.Fatalf("%q: expected %v, got %v", expr, want, got)
+		}
+	}
+	for _, bad := range []string{"go &&", "a b", "!!go", "(go)"} {
+		if _, err := Eval(bad, facts); err == nil || !strings.Contains(err.Error(), "invalid term") {
+			t.Fatalf("%q: expected invalid term error, got %v", bad, err)
+		}
+		if err := Validate(bad); err == nil {
+			t.Fatalf("%q: expected validate error", bad)
+		}
+	}
+}
+
+func TestNamesAndRecord(t *testing.T) {
+	names, err := Names("openapi && !gitlab || openapi")
+	if err != nil {
+		t.Fatalf("Names: %v", err)
+	}
+	if strings.Join(names, ",") != "gitlab,openapi" {
+		t.Fatalf("unexpected names: %v", names)
+	}
+	if got := Record(append(names, "go", "gitlab"), Facts{"go": true, "openapi": true}); got != "gitlab:false,go:true,openapi:true" {
+		t.Fatalf("unexpected record: %q", got)
+	}
+	if got := Record(nil, nil); got != "" {
+		t.Fatalf("expected empty record, got %q", got)
+	}
+}
+
+func TestDetect_ChecksApplicationDirectories(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "services", "api"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "services", "api", "go.mod"), []byte("module x\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gitlab-ci.yml"), []byte("stages: []\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if facts := Detect(root, nil, "services/api"); !facts["go"] || !facts["gitlab"] {
+		t.Fatalf("unexpected api facts: %v", facts)
+	}
+	if facts := Detect(root, nil, "services/web"); facts["go"] || !facts["gitlab"] {
+		t.Fatalf("unexpected web facts: %v", facts)
+	}
+}
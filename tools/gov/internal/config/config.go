@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/render"
+
 	"gopkg.in/yaml.v3"
 )
 
@@ -16,6 +19,23 @@ type Config struct {
 	Source SourceConfig `yaml:"source"`
 	Paths  PathsConfig  `yaml:"paths"`
 	Sync   SyncConfig   `yaml:"sync"`
+
+	Commits  CommitsConfig  `yaml:"commits"`
+	Refactor RefactorConfig `yaml:"refactor"`
+	Coverage CoverageConfig `yaml:"coverage"`
+
+	// Variables are values for fragment placeholders; they override the profile's defaults.
+	Variables map[string]any `yaml:"variables"`
+	// Features are flags for fragment `when:` conditions; they override auto-detected facts
+	// (go, gitlab, github).
+	Features map[string]bool `yaml:"features"`
+	// Parameters are values for the profile's typed `parameters` schema.
+	Parameters map[string]any `yaml:"parameters"`
+
+	// Applications apply several profiles to one repository (e.g. per monorepo subtree).
+	// When set, they replace `source.profile` and `paths.docsRoot`; `source.repo` and
+	// `source.ref` remain the defaults.
+	Applications []ApplicationConfig `yaml:"applications"`
 }
 
 type SourceConfig struct {
@@ -24,6 +44,20 @@ type SourceConfig struct {
 	Profile string `yaml:"profile"`
 }
 
+// ApplicationConfig applies one profile to a docs root.
+type ApplicationConfig struct {
+	// Name labels the application in output (default: the profile ID).
+	Name     string `yaml:"name"`
+	Profile  string `yaml:"profile"`
+	DocsRoot string `yaml:"docsRoot"`
+	// Repo and Ref default to `source.repo` and `source.ref`.
+	Repo string `yaml:"repo"`
+	Ref  string `yaml:"ref"`
+	// Variables (deep) and Parameters are merged over the top-level values.
+	Variables  map[string]any `yaml:"variables"`
+	Parameters map[string]any `yaml:"parameters"`
+}
+
 type PathsConfig struct {
 	DocsRoot string `yaml:"docsRoot"`
 	CacheDir string `yaml:"cacheDir"`
@@ -32,6 +66,35 @@ type PathsConfig struct {
 type SyncConfig struct {
 	ManagedBlockPrefix  string `yaml:"managedBlockPrefix"`
 	LocalAddendaHeading string `yaml:"localAddendaHeading"`
+	// HashNormalization is "exact" (default), "normalize-eol" or "normalize-whitespace".
+	HashNormalization string `yaml:"hashNormalization"`
+}
+
+// CommitsConfig controls `agent-gov commitmsg check`.
+type CommitsConfig struct {
+	// Convention is "conventional" (default) or "none".
+	Convention string   `yaml:"convention"`
+	Types      []string `yaml:"types"`
+	// MatchBranchType requires the commit type to equal the `type` of a `type/area-short-slug` branch.
+	MatchBranchType bool `yaml:"matchBranchType"`
+	// RequirePlanCheckpoint requires a `Checkpoint N` reference declared in the branch plan.
+	RequirePlanCheckpoint bool `yaml:"requirePlanCheckpoint"`
+}
+
+// RefactorConfig is the boundary manifest used by `preflight --check-refactor-purity`.
+type RefactorConfig struct {
+	// BaseRef is the branch the merge base is computed against (default "main").
+	BaseRef string `yaml:"baseRef"`
+	// Expectations are globs for golden/snapshot files; existing ones must not change in a refactor.
+	// Defaults to common golden/testdata patterns when omitted.
+	Expectations []string `yaml:"expectations"`
+	// Boundaries are globs for public API surfaces that a refactor must not touch.
+	Boundaries []string `yaml:"boundaries"`
+}
+
+// CoverageConfig adds repo-specific exclusions to the profile's coverage gate.
+type CoverageConfig struct {
+	Exclude []string `yaml:"exclude"`
 }
 
 func Load(path string) (Config, error) {
@@ -55,21 +118,142 @@ func (c Config) Validate() error {
 	if c.SchemaVersion != 1 {
 		problems = append(problems, "schemaVersion must be 1")
 	}
-	if strings.TrimSpace(c.Source.Repo) == "" {
-		problems = append(problems, "source.repo is required")
+	if len(c.Applications) == 0 {
+		if strings.TrimSpace(c.Source.Repo) == "" {
+			problems = append(problems, "source.repo is required")
+		}
+		if strings.TrimSpace(c.Source.Ref) == "" {
+			problems = append(problems, "source.ref is required")
+		}
+		if strings.TrimSpace(c.Source.Profile) == "" {
+			problems = append(problems, "source.profile is required")
+		}
+	} else {
+		problems = append(problems, c.validateApplications()...)
 	}
-	if strings.TrimSpace(c.Source.Ref) == "" {
-		problems = append(problems, "source.ref is required")
+	switch strings.TrimSpace(c.Commits.Convention) {
+	case "", "conventional", "none":
+	default:
+		problems = append(problems, "commits.convention must be one of: conventional, none")
 	}
-	if strings.TrimSpace(c.Source.Profile) == "" {
-		problems = append(problems, "source.profile is required")
+	switch strings.TrimSpace(c.Sync.HashNormalization) {
+	case "", "exact", "normalize-eol", "normalize-whitespace":
+	default:
+		problems = append(problems, "sync.hashNormalization must be one of: exact, normalize-eol, normalize-whitespace")
 	}
+	problems = append(problems, validateParameters("parameters", c.Parameters)...)
 	if len(problems) > 0 {
 		return errors.New(strings.Join(problems, "; "))
 	}
 	return nil
 }
 
+func (c Config) validateApplications() []string {
+	var problems []string
+	if strings.TrimSpace(c.Source.Profile) != "" {
+		problems = append(problems, "source.profile cannot be combined with applications (list it as an application)")
+	}
+	names := map[string]int{}
+	for i, app := range c.Applications {
+		field := fmt.Sprintf("applications[%d]", i)
+		if strings.TrimSpace(app.Profile) == "" {
+			problems = append(problems, field+".profile is required")
+		}
+		if strings.TrimSpace(app.Repo) == "" && strings.TrimSpace(c.Source.Repo) == "" {
+			problems = append(problems, field+".repo is required (or set source.repo)")
+		}
+		if strings.TrimSpace(app.Ref) == "" && strings.TrimSpace(c.Source.Ref) == "" {
+			problems = append(problems, field+".ref is required (or set source.ref)")
+		}
+		name := applicationName(app)
+		if prev, ok := names[name]; ok && name != "" {
+			problems = append(problems, fmt.Sprintf("%s: duplicate application name %q (also applications[%d]; set name)", field, name, prev))
+		} else {
+			names[name] = i
+		}
+		problems = append(problems, validateParameters(field+".parameters", app.Parameters)...)
+	}
+	return problems
+}
+
+func validateParameters(field string, params map[string]any) []string {
+	var problems []string
+	for _, name := range sortedKeys(params) {
+		switch params[name].(type) {
+		case string, int, float64, bool:
+		default:
+			problems = append(problems, fmt.Sprintf("%s.%s must be a string, number, or bool", field, name))
+		}
+	}
+	return problems
+}
+
+func applicationName(app ApplicationConfig) string {
+	if name := strings.TrimSpace(app.Name); name != "" {
+		return name
+	}
+	return strings.TrimSpace(app.Profile)
+}
+
+// ProfileApplications returns the profile applications to process: the configured
+// `applications` with defaults filled in, or a single one built from `source` and `paths`.
+func (c Config) ProfileApplications() []ApplicationConfig {
+	if len(c.Applications) == 0 {
+		return []ApplicationConfig{{
+			Name:       c.Source.Profile,
+			Profile:    c.Source.Profile,
+			DocsRoot:   c.Paths.DocsRoot,
+			Repo:       c.Source.Repo,
+			Ref:        c.Source.Ref,
+			Variables:  c.Variables,
+			Parameters: c.Parameters,
+		}}
+	}
+	apps := make([]ApplicationConfig, 0, len(c.Applications))
+	for _, app := range c.Applications {
+		app.Name = applicationName(app)
+		if strings.TrimSpace(app.DocsRoot) == "" {
+			app.DocsRoot = "."
+		}
+		if strings.TrimSpace(app.Repo) == "" {
+			app.Repo = c.Source.Repo
+		}
+		if strings.TrimSpace(app.Ref) == "" {
+			app.Ref = c.Source.Ref
+		}
+		if len(c.Variables) > 0 || len(app.Variables) > 0 {
+			app.Variables = render.Merge(c.Variables, app.Variables)
+		}
+		app.Parameters = mergeParameters(c.Parameters, app.Parameters)
+		apps = append(apps, app)
+	}
+	return apps
+}
+
+// mergeParameters returns base with overlay's values applied (nil when both are empty).
+func mergeParameters(base, overlay map[string]any) map[string]any {
+	if len(base) == 0 && len(overlay) == 0 {
+		return nil
+	}
+	out := map[string]any{}
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range overlay {
+		out[k] = v
+	}
+	return out
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (c Config) WithDefaults() Config {
 	if strings.TrimSpace(c.Paths.DocsRoot) == "" {
 		c.Paths.DocsRoot = "."
@@ -80,6 +264,15 @@ func (c Config) WithDefaults() Config {
 	if strings.TrimSpace(c.Sync.LocalAddendaHeading) == "" {
 		c.Sync.LocalAddendaHeading = "Local Addenda (project-owned)"
 	}
+	if strings.TrimSpace(c.Sync.HashNormalization) == "" {
+		c.Sync.HashNormalization = "exact"
+	}
+	if strings.TrimSpace(c.Commits.Convention) == "" {
+		c.Commits.Convention = "conventional"
+	}
+	if strings.TrimSpace(c.Refactor.BaseRef) == "" {
+		c.Refactor.BaseRef = "main"
+	}
 	return c
 }
 
@@ -34,6 +34,27 @@ source:
 	if cfg.Sync.LocalAddendaHeading == "" {
 		t.Fatalf("LocalAddendaHeading default missing")
 	}
+	if cfg.Sync.HashNormalization != "exact" {
+		t.Fatalf("HashNormalization default: got %q", cfg.Sync.HashNormalization)
+	}
+}
+
+func TestLoad_RejectsUnknownHashNormalization(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	base := "schemaVersion: 1\nsource:\n  repo: /tmp/gov\n  ref: v1\n  profile: p\n"
+	if err := os.WriteFile(path, []byte(base+"sync:\n  hashNormalization: normalize-eol\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if cfg, err := Load(path); err != nil || cfg.Sync.HashNormalization != "normalize-eol" {
+		t.Fatalf("expected normalize-eol, got %+v err=%v", cfg.Sync, err)
+	}
+	if err := os.WriteFile(path, []byte(base+"sync:\n  hashNormalization: crlf\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "sync.hashNormalization") {
+		t.Fatalf("expected sync.hashNormalization error, got %v", err)
+	}
 }
 
 func TestLoad_RejectsMissingRequiredFields(t *testing.T) {
@@ -135,3 +156,146 @@ func TestCacheDir_ExpandsBareTilde(t *testing.T) {
 		t.Fatalf("expected tilde expansion")
 	}
 }
+
+func TestLoad_CommitsDefaultsAndValidation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	base := strings.TrimSpace(`
+schemaVersion: 1
+source:
+  repo: "/tmp/gov"
+  ref: "v1.2.3"
+  profile: "mobile-clean-ios"
+`) + "\n"
+	if err := os.WriteFile(path, []byte(base), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Commits.Convention != "conventional" {
+		t.Fatalf("Commits.Convention default: got %q", cfg.Commits.Convention)
+	}
+
+	if err := os.WriteFile(path, []byte(base+"commits:\n  convention: gitmoji\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "commits.convention") {
+		t.Fatalf("expected commits.convention error, got %v", err)
+	}
+}
+
+func TestLoad_ParsesNestedVariables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := strings.TrimSpace(`
+schemaVersion: 1
+source:
+  repo: "/tmp/gov"
+  ref: "v1.2.3"
+  profile: "backend-go-hex"
+variables:
+  Project:
+    Name: payments
+    ModulePath: example.com/payments
+`) + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	project, ok := cfg.Variables["Project"].(map[string]any)
+	if !ok || project["Name"] != "payments" || project["ModulePath"] != "example.com/payments" {
+		t.Fatalf("unexpected variables: %#v", cfg.Variables)
+	}
+}
+
+func TestLoad_RejectsNonScalarParameters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "schemaVersion: 1\nsource:\n  repo: /tmp/gov\n  ref: v1\n  profile: p\nparameters:\n  ciProvider: gitlab\n  coverageThreshold: 90\n  owners: [a, b]\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "parameters.owners must be a string, number, or bool") {
+		t.Fatalf("expected parameters error, got %v", err)
+	}
+}
+
+func TestLoad_ProfileApplicationsInheritSourceDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(`
+schemaVersion: 1
+source:
+  repo: /tmp/gov
+  ref: v1
+variables:
+  Project: {Name: mono, Team: core}
+applications:
+  - profile: backend-go-hex
+    docsRoot: backend
+    variables:
+      Project: {Name: api}
+  - name: ios
+    profile: mobile-clean-ios
+    docsRoot: ios
+    ref: v2
+    parameters:
+      ciProvider: github
+`)), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	apps := cfg.ProfileApplications()
+	if len(apps) != 2 {
+		t.Fatalf("expected 2 applications, got %+v", apps)
+	}
+	if apps[0].Name != "backend-go-hex" || apps[0].Repo != "/tmp/gov" || apps[0].Ref != "v1" || apps[0].DocsRoot != "backend" {
+		t.Fatalf("unexpected first application: %+v", apps[0])
+	}
+	project := apps[0].Variables["Project"].(map[string]any)
+	if project["Name"] != "api" || project["Team"] != "core" {
+		t.Fatalf("expected deep-merged variables, got %v", apps[0].Variables)
+	}
+	if apps[1].Name != "ios" || apps[1].Ref != "v2" || apps[1].Parameters["ciProvider"] != "github" {
+		t.Fatalf("unexpected second application: %+v", apps[1])
+	}
+}
+
+func TestLoad_SingleProfileIsOneApplication(t *testing.T) {
+	cfg := Config{
+		Source: SourceConfig{Repo: "/tmp/gov", Ref: "v1", Profile: "p"},
+		Paths:  PathsConfig{DocsRoot: "docs"},
+	}
+	apps := cfg.ProfileApplications()
+	if len(apps) != 1 || apps[0].Name != "p" || apps[0].Profile != "p" || apps[0].DocsRoot != "docs" || apps[0].Ref != "v1" {
+		t.Fatalf("unexpected applications: %+v", apps)
+	}
+}
+
+func TestLoad_RejectsInvalidApplications(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "schemaVersion: 1\nsource:\n  profile: p\napplications:\n  - profile: a\n    ref: v1\n  - profile: a\n    repo: /tmp/gov\n  - name: x\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err := Load(path)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{
+		"source.profile cannot be combined with applications",
+		"applications[0].repo is required (or set source.repo)",
+		"applications[1].ref is required (or set source.ref)",
+		`applications[1]: duplicate application name "a" (also applications[0]; set name)`,
+		"applications[2].profile is required",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+}
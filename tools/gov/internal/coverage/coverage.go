@@ -0,0 +1,269 @@
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/pathglob"
+)
+
+const (
+	FormatAuto = "auto"
+	FormatGo   = "go"
+	FormatLCOV = "lcov"
+)
+
+// FileCoverage is the covered/total statement (Go) or line (lcov) count for one file.
+type FileCoverage struct {
+	Path    string
+	Covered int
+	Total   int
+}
+
+type Report struct {
+	Files []FileCoverage
+}
+
+type PackageCoverage struct {
+	Package string
+	Covered int
+	Total   int
+}
+
+func (p PackageCoverage) Percent() float64 { return percent(p.Covered, p.Total) }
+
+type Summary struct {
+	Covered  int
+	Total    int
+	Packages []PackageCoverage
+}
+
+func (s Summary) Percent() float64 { return percent(s.Covered, s.Total) }
+
+// Shortfalls returns the packages whose coverage is below threshold.
+func (s Summary) Shortfalls(threshold float64) []PackageCoverage {
+	var out []PackageCoverage
+	for _, p := range s.Packages {
+		if p.Percent() < threshold {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Parse parses a Go cover profile or an lcov tracefile. FormatAuto detects the format.
+func Parse(b []byte, format string) (Report, error) {
+	switch format {
+	case "", FormatAuto:
+		if bytes.HasPrefix(bytes.TrimSpace(b), []byte("mode:")) {
+			return ParseGoProfile(b)
+		}
+		if bytes.Contains(b, []byte("SF:")) {
+			return ParseLCOV(b)
+		}
+		return Report{}, errors.New("unrecognised coverage format (expected Go cover profile or lcov)")
+	case FormatGo:
+		return ParseGoProfile(b)
+	case FormatLCOV:
+		return ParseLCOV(b)
+	default:
+		return Report{}, fmt.Errorf("unknown coverage format %q", format)
+	}
+}
+
+// ParseGoProfile parses `go test -coverprofile` output. Blocks reported by more
+// than one test binary are merged so each block is counted once.
+func ParseGoProfile(b []byte) (Report, error) {
+	type block struct {
+		stmts   int
+		covered bool
+	}
+	blocks := map[string]map[string]block{} // file -> block range -> block
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	lineNo := 0
+	sawMode := false
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "mode:") {
+			sawMode = true
+			continue
+		}
+		if !sawMode {
+			return Report{}, fmt.Errorf("line %d: missing mode header", lineNo)
+		}
+		// <file>:<startLine>.<startCol>,<endLine>.<endCol> <numStmts> <count>
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return Report{}, fmt.Errorf("line %d: malformed block %q", lineNo, line)
+		}
+		idx := strings.LastIndex(fields[0], ":")
+		if idx <= 0 {
+			return Report{}, fmt.Errorf("line %d: malformed block %q", lineNo, line)
+		}
+		file, rng := fields[0][:idx], fields[0][idx+1:]
+		stmts, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return Report{}, fmt.Errorf("line %d: statements: %v", lineNo, err)
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return Report{}, fmt.Errorf("line %d: count: %v", lineNo, err)
+		}
+		if blocks[file] == nil {
+			blocks[file] = map[string]block{}
+		}
+		prev := blocks[file][rng]
+		blocks[file][rng] = block{stmts: stmts, covered: prev.covered || count > 0}
+	}
+	if err := sc.Err(); err != nil {
+		return Report{}, err
+	}
+
+	var r Report
+	for file, bs := range blocks {
+		fc := FileCoverage{Path: file}
+		for _, blk := range bs {
+			fc.Total += blk.stmts
+			if blk.covered {
+				fc.Covered += blk.stmts
+			}
+		}
+		r.Files = append(r.Files, fc)
+	}
+	sortFiles(r.Files)
+	return r, nil
+}
+
+// ParseLCOV parses an lcov tracefile, counting DA line records (falling back to LF/LH).
+func ParseLCOV(b []byte) (Report, error) {
+	merged := map[string]map[int]bool{} // file -> line -> hit
+	summary := map[string]FileCoverage{}
+	var current string
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		key, val, _ := strings.Cut(line, ":")
+		switch key {
+		case "SF":
+			current = val
+			if merged[current] == nil {
+				merged[current] = map[int]bool{}
+			}
+		case "DA":
+			if current == "" {
+				return Report{}, fmt.Errorf("line %d: DA record outside SF", lineNo)
+			}
+			parts := strings.Split(val, ",")
+			if len(parts) < 2 {
+				return Report{}, fmt.Errorf("line %d: malformed DA record %q", lineNo, line)
+			}
+			n, err := strconv.Atoi(parts[0])
+			if err != nil {
+				return Report{}, fmt.Errorf("line %d: DA line: %v", lineNo, err)
+			}
+			hits, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil {
+				return Report{}, fmt.Errorf("line %d: DA hits: %v", lineNo, err)
+			}
+			merged[current][n] = merged[current][n] || hits > 0
+		case "LF", "LH":
+			if current == "" {
+				continue
+			}
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return Report{}, fmt.Errorf("line %d: %s: %v", lineNo, key, err)
+			}
+			fc := summary[current]
+			if key == "LF" {
+				fc.Total += n
+			} else {
+				fc.Covered += n
+			}
+			summary[current] = fc
+		case "end_of_record":
+			current = ""
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return Report{}, err
+	}
+
+	var r Report
+	for file, lines := range merged {
+		fc := FileCoverage{Path: file}
+		if len(lines) == 0 {
+			fc.Covered, fc.Total = summary[file].Covered, summary[file].Total
+		}
+		for _, hit := range lines {
+			fc.Total++
+			if hit {
+				fc.Covered++
+			}
+		}
+		r.Files = append(r.Files, fc)
+	}
+	sortFiles(r.Files)
+	return r, nil
+}
+
+// Exclude drops files matching any of globs.
+func (r Report) Exclude(globs []string) Report {
+	if len(globs) == 0 {
+		return r
+	}
+	var out Report
+	for _, f := range r.Files {
+		if pathglob.MatchAny(globs, f.Path) {
+			continue
+		}
+		out.Files = append(out.Files, f)
+	}
+	return out
+}
+
+// Summarize aggregates file coverage into totals and per-package (directory) coverage.
+func Summarize(r Report) Summary {
+	var s Summary
+	pkgs := map[string]*PackageCoverage{}
+	for _, f := range r.Files {
+		s.Covered += f.Covered
+		s.Total += f.Total
+		dir := path.Dir(strings.ReplaceAll(f.Path, "\\", "/"))
+		p, ok := pkgs[dir]
+		if !ok {
+			p = &PackageCoverage{Package: dir}
+			pkgs[dir] = p
+		}
+		p.Covered += f.Covered
+		p.Total += f.Total
+	}
+	for _, p := range pkgs {
+		s.Packages = append(s.Packages, *p)
+	}
+	sort.Slice(s.Packages, func(i, j int) bool { return s.Packages[i].Package < s.Packages[j].Package })
+	return s
+}
+
+func percent(covered, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(covered) * 100 / float64(total)
+}
+
+func sortFiles(files []FileCoverage) {
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+}
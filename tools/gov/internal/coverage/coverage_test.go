@@ -0,0 +1,97 @@
+package coverage
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+const goProfile = `mode: set
+example.com/app/internal/svc/svc.go:3.20,5.2 2 1
+example.com/app/internal/svc/svc.go:7.20,9.2 2 0
+example.com/app/internal/svc/svc.go:7.20,9.2 2 1
+example.com/app/internal/db/db.go:3.20,5.2 3 0
+example.com/app/internal/db/db.go:6.20,8.2 1 1
+example.com/app/internal/openapi/gen.go:3.20,5.2 10 0
+`
+
+func TestParseGoProfile_MergesDuplicateBlocks(t *testing.T) {
+	r, err := Parse([]byte(goProfile), FormatAuto)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	s := Summarize(r)
+	if s.Covered != 5 || s.Total != 18 {
+		t.Fatalf("expected 5/18, got %d/%d", s.Covered, s.Total)
+	}
+
+	s = Summarize(r.Exclude([]string{"**/openapi/**"}))
+	if s.Covered != 5 || s.Total != 8 {
+		t.Fatalf("expected 5/8 after exclusion, got %d/%d", s.Covered, s.Total)
+	}
+	if math.Abs(s.Percent()-62.5) > 0.001 {
+		t.Fatalf("expected 62.5%%, got %v", s.Percent())
+	}
+	short := s.Shortfalls(85)
+	if len(short) != 1 || short[0].Package != "example.com/app/internal/db" {
+		t.Fatalf("expected db shortfall, got %+v", short)
+	}
+}
+
+func TestParseLCOV(t *testing.T) {
+	lcov := strings.Join([]string{
+		"TN:",
+		"SF:Sources/App/Feature.swift",
+		"DA:1,1",
+		"DA:2,0",
+		"DA:3,4",
+		"LF:3",
+		"LH:2",
+		"end_of_record",
+		"SF:Sources/Core/Util.swift",
+		"LF:4",
+		"LH:4",
+		"end_of_record",
+	}, "\n")
+	r, err := Parse([]byte(lcov), FormatAuto)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	s := Summarize(r)
+	if s.Covered != 6 || s.Total != 7 {
+		t.Fatalf("expected 6/7, got %d/%d", s.Covered, s.Total)
+	}
+	if len(s.Packages) != 2 || s.Packages[0].Package != "Sources/App" {
+		t.Fatalf("unexpected packages: %+v", s.Packages)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	cases := []struct {
+		in     string
+		format string
+	}{
+		{in: "hello", format: FormatAuto},
+		{in: "x", format: "cobertura"},
+		{in: "a.go:1.1,2.2 1 1\n", format: FormatGo},
+		{in: "mode: set\nbad line\n", format: FormatGo},
+		{in: "mode: set\na.go:1.1,2.2 x 1\n", format: FormatGo},
+		{in: "mode: set\na.go:1.1,2.2 1 x\n", format: FormatGo},
+		{in: "DA:1,1\n", format: FormatLCOV},
+		{in: "SF:a\nDA:1\n", format: FormatLCOV},
+		{in: "SF:a\nDA:x,1\n", format: FormatLCOV},
+		{in: "SF:a\nDA:1,x\n", format: FormatLCOV},
+		{in: "SF:a\nLF:x\n", format: FormatLCOV},
+	}
+	for _, tc := range cases {
+		if _, err := Parse([]byte(tc.in), tc.format); err == nil {
+			t.Fatalf("input %q format %q: expected error", tc.in, tc.format)
+		}
+	}
+}
+
+func TestSummarize_EmptyReportIsFullyCovered(t *testing.T) {
+	if got := Summarize(Report{}).Percent(); got != 100 {
+		t.Fatalf("expected 100, got %v", got)
+	}
+}
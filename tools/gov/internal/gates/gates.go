@@ -0,0 +1,168 @@
+package gates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/profile"
+)
+
+// Wrapped for test stubbing.
+var execCommandContext = exec.CommandContext
+
+type Status string
+
+const (
+	StatusPass    Status = "pass"
+	StatusFail    Status = "fail"
+	StatusTimeout Status = "timeout"
+	StatusSkipped Status = "skipped"
+)
+
+type GateResult struct {
+	Name     string
+	Command  []string
+	Optional bool
+	Status   Status
+	Duration time.Duration
+	// Err describes why the gate did not pass.
+	Err string
+}
+
+type RunOptions struct {
+	// Dir is the working directory for gate commands (the repo root).
+	Dir   string
+	Gates []profile.GateSpec
+	// Only restricts the run to the named gates; others are reported as skipped.
+	Only []string
+
+	// Stdout and Stderr receive streamed gate output.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+type Result struct {
+	Gates []GateResult
+}
+
+// OK reports whether every required gate passed.
+func (r Result) OK() bool {
+	for _, g := range r.Gates {
+		if g.Optional || g.Status == StatusSkipped {
+			continue
+		}
+		if g.Status != StatusPass {
+			return false
+		}
+	}
+	return true
+}
+
+// Run executes gates sequentially, streaming their output.
+func Run(ctx context.Context, opts RunOptions) (Result, error) {
+	if len(opts.Gates) == 0 {
+		return Result{}, errors.New("no gates declared")
+	}
+	for _, name := range opts.Only {
+		if !hasGate(opts.Gates, name) {
+			return Result{}, fmt.Errorf("unknown gate %q", name)
+		}
+	}
+	if opts.Stdout == nil {
+		opts.Stdout = io.Discard
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = io.Discard
+	}
+
+	var res Result
+	for _, g := range opts.Gates {
+		gr := GateResult{Name: g.Name, Command: g.Command, Optional: g.Optional}
+		if len(opts.Only) > 0 && !contains(opts.Only, g.Name) {
+			gr.Status = StatusSkipped
+			res.Gates = append(res.Gates, gr)
+			continue
+		}
+		fmt.Fprintf(opts.Stdout, "==> gate %s: %s\n", g.Name, strings.Join(g.Command, " "))
+		gr.Status, gr.Duration, gr.Err = runGate(ctx, opts, g)
+		res.Gates = append(res.Gates, gr)
+	}
+	return res, nil
+}
+
+func runGate(ctx context.Context, opts RunOptions, g profile.GateSpec) (Status, time.Duration, string) {
+	if timeout := g.TimeoutDuration(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	cmd := execCommandContext(ctx, g.Command[0], g.Command[1:]...)
+	cmd.Dir = opts.Dir
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
+	// Don't hang on grandchildren that keep the output pipes open after a timeout.
+	cmd.WaitDelay = time.Second
+	start := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(start)
+	if err == nil {
+		return StatusPass, elapsed, ""
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return StatusTimeout, elapsed, fmt.Sprintf("timed out after %s", g.TimeoutDuration())
+	}
+	return StatusFail, elapsed, err.Error()
+}
+
+// Summary renders a Markdown summary suitable for plan wrap-up and approval requests.
+func Summary(r Result) string {
+	var b strings.Builder
+	b.WriteString("## Quality gates\n\n")
+	b.WriteString("| Gate | Command | Required | Result | Duration |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, g := range r.Gates {
+		required := "yes"
+		if g.Optional {
+			required = "no"
+		}
+		result := string(g.Status)
+		if g.Err != "" {
+			result += " (" + g.Err + ")"
+		}
+		duration := "-"
+		if g.Status != StatusSkipped {
+			duration = g.Duration.Round(100 * time.Millisecond).String()
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %s | %s |\n", g.Name, strings.Join(g.Command, " "), required, result, duration)
+	}
+	b.WriteString("\n")
+	if r.OK() {
+		b.WriteString("All required gates passed.\n")
+	} else {
+		b.WriteString("One or more required gates failed.\n")
+	}
+	return b.String()
+}
+
+func hasGate(gates []profile.GateSpec, name string) bool {
+	for _, g := range gates {
+		if g.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package gates
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/profile"
+)
+
+func TestRun_StreamsOutputAndSummarizes(t *testing.T) {
+	var out, errOut bytes.Buffer
+	res, err := Run(context.Background(), RunOptions{
+		Dir: t.TempDir(),
+		Gates: []profile.GateSpec{
+			{Name: "ci", Command: []string{"sh", "-c", "echo building"}},
+			{Name: "lint", Command: []string{"sh", "-c", "echo lint issues >&2; exit 3"}, Optional: true},
+		},
+		Stdout: &out,
+		Stderr: &errOut,
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !res.OK() {
+		t.Fatalf("expected ok (optional failure only), got %+v", res.Gates)
+	}
+	if !strings.Contains(out.String(), "building") || !strings.Contains(errOut.String(), "lint issues") {
+		t.Fatalf("expected streamed output, got stdout=%q stderr=%q", out.String(), errOut.String())
+	}
+	if res.Gates[1].Status != StatusFail {
+		t.Fatalf("expected lint fail, got %+v", res.Gates[1])
+	}
+	summary := Summary(res)
+	for _, want := range []string{"| ci | `sh -c echo building` | yes | pass |", "| lint |", "| no | fail (exit status 3) |", "All required gates passed."} {
+		if !strings.Contains(summary, want) {
+			t.Fatalf("expected %q in summary:\n%s", want, summary)
+		}
+	}
+}
+
+func TestRun_RequiredFailureAndTimeout(t *testing.T) {
+	res, err := Run(context.Background(), RunOptions{
+		Gates: []profile.GateSpec{
+			{Name: "slow", Command: []string{"sleep", "5"}, Timeout: "50ms"},
+			{Name: "skipped", Command: []string{"true"}},
+		},
+		Only: []string{"slow"},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.OK() {
+		t.Fatalf("expected failure")
+	}
+	if res.Gates[0].Status != StatusTimeout || res.Gates[1].Status != StatusSkipped {
+		t.Fatalf("unexpected statuses: %+v", res.Gates)
+	}
+	if !strings.Contains(Summary(res), "One or more required gates failed.") {
+		t.Fatalf("expected failure summary")
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	if _, err := Run(context.Background(), RunOptions{}); err == nil {
+		t.Fatalf("expected error for no gates")
+	}
+	_, err := Run(context.Background(), RunOptions{
+		Gates: []profile.GateSpec{{Name: "ci", Command: []string{"true"}}},
+		Only:  []string{"nope"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "unknown gate") {
+		t.Fatalf("expected unknown gate error, got %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ManagedMarker identifies hook scripts written by agent-gov.
+const ManagedMarker = "# agent-gov:managed-hook"
+
+// ChainedSuffix is appended to a pre-existing (project-owned) hook when a managed
+// hook is installed in its place. The managed hook runs the chained hook first.
+const ChainedSuffix = ".agent-gov-chained"
+
+// DefaultHooks are the git hooks agent-gov manages.
+var DefaultHooks = []string{"pre-commit", "commit-msg", "pre-push"}
+
+type State string
+
+const (
+	StateMissing   State = "missing"
+	StateManaged   State = "managed"
+	StateUnmanaged State = "unmanaged"
+)
+
+type HookStatus struct {
+	Name  string
+	Path  string
+	State State
+	// Chained is true when a pre-existing hook was preserved and is run by the managed hook.
+	Chained bool
+}
+
+func (s HookStatus) String() string {
+	switch s.State {
+	case StateManaged:
+		if s.Chained {
+			return fmt.Sprintf("%s: installed (chains existing hook)", s.Name)
+		}
+		return fmt.Sprintf("%s: installed", s.Name)
+	case StateUnmanaged:
+		return fmt.Sprintf("%s: unmanaged hook present", s.Name)
+	default:
+		return fmt.Sprintf("%s: not installed", s.Name)
+	}
+}
+
+type InstallOptions struct {
+	// HooksDir is the effective hooks directory (respecting core.hooksPath).
+	HooksDir string
+	// Bin is the agent-gov command the hooks invoke (AGENT_GOV_BIN overrides it at runtime).
+	Bin string
+	// Hooks defaults to DefaultHooks.
+	Hooks []string
+}
+
+// Install writes managed hooks. Existing project-owned hooks are preserved by
+// renaming them with ChainedSuffix; existing managed hooks are rewritten in place.
+func Install(opts InstallOptions) ([]HookStatus, error) {
+	if strings.TrimSpace(opts.HooksDir) == "" {
+		return nil, errors.New("hooks dir is required")
+	}
+	if strings.TrimSpace(opts.Bin) == "" {
+		opts.Bin = "agent-gov"
+	}
+	if len(opts.Hooks) == 0 {
+		opts.Hooks = DefaultHooks
+	}
+	if err := os.MkdirAll(opts.HooksDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create hooks dir: %w", err)
+	}
+
+	var out []HookStatus
+	for _, name := range opts.Hooks {
+		path := filepath.Join(opts.HooksDir, name)
+		chainedPath := path + ChainedSuffix
+		st, err := statusFor(opts.HooksDir, name)
+		if err != nil {
+			return out, err
+		}
+		if st.State == StateUnmanaged {
+			if _, err := os.Stat(chainedPath); err == nil {
+				return out, fmt.Errorf("%s: cannot chain existing hook, %s already exists", name, filepath.Base(chainedPath))
+			}
+			if err := os.Rename(path, chainedPath); err != nil {
+				return out, fmt.Errorf("%s: preserve existing hook: %w", name, err)
+			}
+		}
+		if err := os.WriteFile(path, []byte(Script(name, opts.Bin)), 0o755); err != nil {
+			return out, fmt.Errorf("%s: write hook: %w", name, err)
+		}
+		// WriteFile does not change the mode of an existing file.
+		if err := os.Chmod(path, 0o755); err != nil {
+			return out, fmt.Errorf("%s: chmod hook: %w", name, err)
+		}
+		st, err = statusFor(opts.HooksDir, name)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// Uninstall removes managed hooks and restores any chained project-owned hook.
+// Unmanaged hooks are left untouched.
+func Uninstall(hooksDir string, names []string) ([]HookStatus, error) {
+	if strings.TrimSpace(hooksDir) == "" {
+		return nil, errors.New("hooks dir is required")
+	}
+	if len(names) == 0 {
+		names = DefaultHooks
+	}
+	var out []HookStatus
+	for _, name := range names {
+		path := filepath.Join(hooksDir, name)
+		st, err := statusFor(hooksDir, name)
+		if err != nil {
+			return out, err
+		}
+		if st.State == StateManaged {
+			if err := os.Remove(path); err != nil {
+				return out, fmt.Errorf("%s: remove hook: %w", name, err)
+			}
+			if st.Chained {
+				if err := os.Rename(path+ChainedSuffix, path); err != nil {
+					return out, fmt.Errorf("%s: restore chained hook: %w", name, err)
+				}
+			}
+		}
+		st, err = statusFor(hooksDir, name)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// Status reports the state of each hook in hooksDir.
+func Status(hooksDir string, names []string) ([]HookStatus, error) {
+	if len(names) == 0 {
+		names = DefaultHooks
+	}
+	var out []HookStatus
+	for _, name := range names {
+		st, err := statusFor(hooksDir, name)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+func statusFor(hooksDir, name string) (HookStatus, error) {
+	path := filepath.Join(hooksDir, name)
+	st := HookStatus{Name: name, Path: path, State: StateMissing}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return st, nil
+		}
+		return st, fmt.Errorf("%s: read hook: %w", name, err)
+	}
+	if !IsManaged(string(b)) {
+		st.State = StateUnmanaged
+		return st, nil
+	}
+	st.State = StateManaged
+	if _, err := os.Stat(path + ChainedSuffix); err == nil {
+		st.Chained = true
+	}
+	return st, nil
+}
+
+// IsManaged reports whether a hook script was written by agent-gov.
+func IsManaged(script string) bool {
+	for _, line := range strings.SplitN(script, "\n", 4) {
+		if strings.TrimSpace(line) == ManagedMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// Script renders the managed hook for name. The hook runs any chained
+// project-owned hook first, then dispatches to `agent-gov hooks run <name>`.
+func Script(name, bin string) string {
+	return strings.Join([]string{
+		"#!/bin/sh",
+		ManagedMarker,
+		"# Installed by `agent-gov hooks install`; remove with `agent-gov hooks uninstall`.",
+		"# Set AGENT_GOV_BIN to override the agent-gov command used by this hook.",
+		"set -e",
+		`chained="$0` + ChainedSuffix + `"`,
+		`if [ -x "$chained" ]; then`,
+		`	"$chained" "$@"`,
+		"fi",
+		`bin="${AGENT_GOV_BIN:-}"`,
+		`if [ -z "$bin" ]; then`,
+		"	bin=" + shellQuote(bin),
+		"fi",
+		`exec "$bin" hooks run ` + name + ` "$@"`,
+		"",
+	}, "\n")
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,134 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstall_WritesManagedHooks(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hooks")
+
+	statuses, err := Install(InstallOptions{HooksDir: dir, Bin: "tools/bin/agent-gov"})
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if len(statuses) != len(DefaultHooks) {
+		t.Fatalf("expected %d statuses, got %d", len(DefaultHooks), len(statuses))
+	}
+	for _, st := range statuses {
+		if st.State != StateManaged || st.Chained {
+			t.Fatalf("expected managed unchained hook, got %+v", st)
+		}
+		info, err := os.Stat(st.Path)
+		if err != nil {
+			t.Fatalf("stat: %v", err)
+		}
+		if info.Mode().Perm()&0o111 == 0 {
+			t.Fatalf("expected executable hook, got mode %v", info.Mode())
+		}
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "pre-commit"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	s := string(b)
+	if !strings.Contains(s, "bin='tools/bin/agent-gov'") || !strings.Contains(s, "hooks run pre-commit") {
+		t.Fatalf("unexpected script:\n%s", s)
+	}
+}
+
+func TestInstall_ChainsExistingHookAndUninstallRestoresIt(t *testing.T) {
+	dir := t.TempDir()
+	existing := "#!/bin/sh\necho project hook\n"
+	if err := os.WriteFile(filepath.Join(dir, "pre-commit"), []byte(existing), 0o755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	statuses, err := Install(InstallOptions{HooksDir: dir, Hooks: []string{"pre-commit"}})
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if !statuses[0].Chained {
+		t.Fatalf("expected chained hook, got %+v", statuses[0])
+	}
+	chained, err := os.ReadFile(filepath.Join(dir, "pre-commit"+ChainedSuffix))
+	if err != nil || string(chained) != existing {
+		t.Fatalf("expected existing hook preserved, got %q (%v)", chained, err)
+	}
+
+	// Re-installing updates the managed hook without re-chaining it.
+	if _, err := Install(InstallOptions{HooksDir: dir, Hooks: []string{"pre-commit"}}); err != nil {
+		t.Fatalf("Install again: %v", err)
+	}
+
+	statuses, err = Uninstall(dir, []string{"pre-commit"})
+	if err != nil {
+		t.Fatalf("Uninstall: %v", err)
+	}
+	if statuses[0].State != StateUnmanaged {
+		t.Fatalf("expected restored project hook, got %+v", statuses[0])
+	}
+	restored, err := os.ReadFile(filepath.Join(dir, "pre-commit"))
+	if err != nil || string(restored) != existing {
+		t.Fatalf("expected project hook restored, got %q (%v)", restored, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pre-commit"+ChainedSuffix)); !os.IsNotExist(err) {
+		t.Fatalf("expected chained file removed, got %v", err)
+	}
+}
+
+func TestInstall_RefusesToClobberChainedHook(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "pre-push"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pre-push"+ChainedSuffix), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err := Install(InstallOptions{HooksDir: dir, Hooks: []string{"pre-push"}})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected chain conflict error, got %v", err)
+	}
+}
+
+func TestStatus_ReportsMissingAndUnmanaged(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "commit-msg"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	statuses, err := Status(dir, nil)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	got := map[string]State{}
+	for _, st := range statuses {
+		got[st.Name] = st.State
+	}
+	if got["commit-msg"] != StateUnmanaged || got["pre-commit"] != StateMissing {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+	if !strings.Contains(statuses[0].String(), "not installed") {
+		t.Fatalf("unexpected status line: %q", statuses[0].String())
+	}
+}
+
+func TestInstall_RequiresHooksDir(t *testing.T) {
+	if _, err := Install(InstallOptions{}); err == nil {
+		t.Fatalf("expected error")
+	}
+	if _, err := Uninstall("", nil); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestScript_QuotesBin(t *testing.T) {
+	s := Script("pre-commit", "it's/agent-gov")
+	if !strings.Contains(s, `bin='it'\''s/agent-gov'`) {
+		t.Fatalf("expected quoted bin, got:\n%s", s)
+	}
+	if !IsManaged(s) {
+		t.Fatalf("expected script to be recognised as managed")
+	}
+}
@@ -0,0 +1,52 @@
+package managedblocks
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors; test with errors.Is.
+var (
+	// ErrMissingID: a BEGIN or END marker has no id field.
+	ErrMissingID = errors.New("marker missing id")
+	// ErrDuplicateBegin: a BEGIN marker for an id whose block is still open.
+	ErrDuplicateBegin = errors.New("nested/duplicate BEGIN")
+	// ErrEndWithoutBegin: an END marker with no open block of that id.
+	ErrEndWithoutBegin = errors.New("END without BEGIN")
+	// ErrUnclosedBlock: a BEGIN marker with no matching END marker.
+	ErrUnclosedBlock = errors.New("unclosed block")
+
+	// ErrBlockNotFound: the document has no block with the requested id.
+	ErrBlockNotFound = errors.New("block not found")
+	// ErrDuplicateBlock: the document has more than one block with the requested id.
+	ErrDuplicateBlock = errors.New("multiple blocks found")
+	// ErrChecksumMismatch: a block's content does not match the sha256 in its BEGIN marker.
+	ErrChecksumMismatch = errors.New("sha256 mismatch")
+)
+
+// MarkerError describes a malformed marker found by FindBlocks. Err is one of
+// ErrMissingID, ErrDuplicateBegin, ErrEndWithoutBegin or ErrUnclosedBlock.
+type MarkerError struct {
+	Err error
+	// Kind is "BEGIN" or "END".
+	Kind string
+	ID   string
+	// Line is the 1-based line of the offending marker.
+	Line int
+}
+
+func (e *MarkerError) Error() string {
+	switch e.Err {
+	case ErrMissingID:
+		return fmt.Sprintf("%s marker at line %d missing id", e.Kind, e.Line)
+	case ErrDuplicateBegin:
+		return fmt.Sprintf("nested/duplicate BEGIN for id %q at line %d", e.ID, e.Line)
+	case ErrEndWithoutBegin:
+		return fmt.Sprintf("END without BEGIN for id %q at line %d", e.ID, e.Line)
+	case ErrUnclosedBlock:
+		return fmt.Sprintf("unclosed block %q (BEGIN at line %d)", e.ID, e.Line)
+	}
+	return fmt.Sprintf("%v: %s marker for id %q at line %d", e.Err, e.Kind, e.ID, e.Line)
+}
+
+func (e *MarkerError) Unwrap() error { return e.Err }
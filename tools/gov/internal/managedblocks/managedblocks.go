@@ -18,11 +18,14 @@ type Block struct {
 
 	// Meta are key/value pairs parsed from the BEGIN marker.
 	Meta map[string]string
+
+	// Syntax is the comment syntax of the BEGIN marker; updates keep it.
+	Syntax Syntax
 }
 
 // ReplaceOptions controls block updates.
 type ReplaceOptions struct {
-	// Prefix is the marker namespace, e.g. "GOV" for "<!-- GOV:BEGIN ... -->".
+	// Prefix is the marker namespace, e.g. "GOV" for "<!-- GOV:BEGIN ... -->" or "# GOV:BEGIN ...".
 	Prefix string
 	// BlockID is the managed block id to update.
 	BlockID string
@@ -31,11 +34,29 @@ type ReplaceOptions struct {
 	// MetaUpdates are applied to the BEGIN marker (merged over existing meta).
 	// The "id" field is always preserved as BlockID.
 	MetaUpdates map[string]string
+	// Normalization is applied before hashing NewContent and recorded in the BEGIN
+	// marker. Empty means Exact.
+	Normalization Normalization
+	// Placement is where UpsertBlock puts a block the document does not have yet.
+	// Empty means PlaceEnd.
+	Placement Placement
 }
 
-// ReplaceBlock replaces a managed block's content and updates its BEGIN marker.
-// Only the region between BEGIN and END markers (exclusive) is modified.
-func ReplaceBlock(doc string, opts ReplaceOptions) (string, error) {
+// Placement positions a new block in a document.
+type Placement string
+
+const (
+	// PlaceEnd appends the block after the existing content.
+	PlaceEnd Placement = "end"
+	// PlaceTop puts the block before the existing content (after a `#!` line), where
+	// license and source headers go, e.g. above a Go file's package clause.
+	PlaceTop Placement = "top"
+)
+
+// ReplaceBlock replaces a managed block's content and updates its BEGIN marker. Only
+// markers in syntax are recognised (see FindBlocks). Only the region between BEGIN and END
+// markers (exclusive) is modified.
+func ReplaceBlock(doc string, syntax Syntax, opts ReplaceOptions) (string, error) {
 	if strings.TrimSpace(opts.Prefix) == "" {
 		return "", errors.New("prefix is required")
 	}
@@ -48,7 +69,7 @@ func ReplaceBlock(doc string, opts ReplaceOptions) (string, error) {
 
 	lines, trailingNewline := splitLines(doc)
 
-	blocks, err := FindBlocks(lines, opts.Prefix)
+	blocks, err := FindBlocks(lines, syntax, opts.Prefix)
 	if err != nil {
 		return "", err
 	}
@@ -57,13 +78,13 @@ func ReplaceBlock(doc string, opts ReplaceOptions) (string, error) {
 	for i := range blocks {
 		if blocks[i].ID == opts.BlockID {
 			if b != nil {
-				return "", fmt.Errorf("multiple blocks found with id %q", opts.BlockID)
+				return "", fmt.Errorf("%w with id %q", ErrDuplicateBlock, opts.BlockID)
 			}
 			b = &blocks[i]
 		}
 	}
 	if b == nil {
-		return "", fmt.Errorf("block id %q not found", opts.BlockID)
+		return "", fmt.Errorf("%w: %q", ErrBlockNotFound, opts.BlockID)
 	}
 	if b.BeginLineIdx+1 > b.EndLineIdx {
 		return "", fmt.Errorf("invalid block indices for %q", opts.BlockID)
@@ -82,10 +103,10 @@ func ReplaceBlock(doc string, opts ReplaceOptions) (string, error) {
 	}
 	newContentLines, _ := splitLines(opts.NewContent)
 	canonicalContent := strings.Join(newContentLines, "\n")
-	meta["sha256"] = SHA256Hex(canonicalContent)
+	meta[HashMetaKey] = opts.Normalization.Tag()
+	meta["sha256"] = ContentHash(canonicalContent, opts.Normalization)
 
-	beginLine := FormatBeginMarker(opts.Prefix, meta)
-	lines[b.BeginLineIdx] = beginLine
+	lines[b.BeginLineIdx] = b.Syntax.BeginMarker(opts.Prefix, meta)
 
 	// Replace between begin+1 and end (exclusive).
 	lines = splice(lines, b.BeginLineIdx+1, b.EndLineIdx, newContentLines)
@@ -93,10 +114,86 @@ func ReplaceBlock(doc string, opts ReplaceOptions) (string, error) {
 	return joinLines(lines, trailingNewline), nil
 }
 
-// VerifyBlockSHA256 verifies that the sha256 in the BEGIN marker matches the block content.
-func VerifyBlockSHA256(doc, prefix, blockID string) error {
+// UpsertBlock replaces the block opts.BlockID if doc has one, and otherwise adds a new
+// block written in syntax at opts.Placement, separated from the existing content by a
+// blank line.
+func UpsertBlock(doc string, syntax Syntax, opts ReplaceOptions) (string, error) {
+	lines, _ := splitLines(doc)
+	blocks, err := FindBlocks(lines, syntax, opts.Prefix)
+	if err != nil {
+		return "", err
+	}
+	for _, b := range blocks {
+		if b.ID == opts.BlockID {
+			return ReplaceBlock(doc, syntax, opts)
+		}
+	}
+
+	block := strings.Join(newBlockLines(syntax, opts), "\n") + "\n"
+	if strings.TrimSpace(doc) == "" {
+		return block, nil
+	}
+	if opts.Placement == PlaceTop {
+		var shebang string
+		if strings.HasPrefix(doc, "#!") {
+			i := strings.IndexByte(doc, '\n')
+			if i < 0 {
+				return doc + "\n" + block, nil
+			}
+			shebang, doc = doc[:i+1], doc[i+1:]
+		}
+		return shebang + block + "\n" + strings.TrimLeft(doc, "\n"), nil
+	}
+	return strings.TrimRight(doc, "\n") + "\n\n" + block, nil
+}
+
+// InsertBlockAfter adds a new block opts.BlockID after the END marker of block afterID,
+// separated by a blank line and written in afterID's comment syntax. Only markers in
+// syntax are recognised.
+func InsertBlockAfter(doc string, syntax Syntax, afterID string, opts ReplaceOptions) (string, error) {
+	lines, trailingNewline := splitLines(doc)
+	blocks, err := FindBlocks(lines, syntax, opts.Prefix)
+	if err != nil {
+		return "", err
+	}
+	var after *Block
+	for i := range blocks {
+		switch blocks[i].ID {
+		case opts.BlockID:
+			return "", fmt.Errorf("block id %q already exists", opts.BlockID)
+		case afterID:
+			after = &blocks[i]
+		}
+	}
+	if after == nil {
+		return "", fmt.Errorf("%w: %q", ErrBlockNotFound, afterID)
+	}
+	insert := append([]string{""}, newBlockLines(after.Syntax, opts)...)
+	lines = splice(lines, after.EndLineIdx+1, after.EndLineIdx+1, insert)
+	return joinLines(lines, trailingNewline), nil
+}
+
+// newBlockLines renders the BEGIN marker, content and END marker of a new block.
+func newBlockLines(syntax Syntax, opts ReplaceOptions) []string {
+	meta := map[string]string{}
+	for k, v := range opts.MetaUpdates {
+		meta[k] = v
+	}
+	meta["id"] = opts.BlockID
+	contentLines, _ := splitLines(opts.NewContent)
+	meta[HashMetaKey] = opts.Normalization.Tag()
+	meta["sha256"] = ContentHash(strings.Join(contentLines, "\n"), opts.Normalization)
+
+	out := []string{syntax.BeginMarker(opts.Prefix, meta)}
+	out = append(out, contentLines...)
+	return append(out, syntax.EndMarker(opts.Prefix, opts.BlockID))
+}
+
+// VerifyBlockSHA256 verifies that the sha256 in the BEGIN marker matches the block content,
+// normalised as recorded in the marker's "hash" field. Only markers in syntax are recognised.
+func VerifyBlockSHA256(doc string, syntax Syntax, prefix, blockID string) error {
 	lines, _ := splitLines(doc)
-	blocks, err := FindBlocks(lines, prefix)
+	blocks, err := FindBlocks(lines, syntax, prefix)
 	if err != nil {
 		return err
 	}
@@ -108,46 +205,71 @@ func VerifyBlockSHA256(doc, prefix, blockID string) error {
 		if want == "" {
 			return fmt.Errorf("block %q is missing sha256 in BEGIN marker", blockID)
 		}
+		n, err := ParseNormalization(b.Meta[HashMetaKey])
+		if err != nil {
+			return fmt.Errorf("block %q: %w", blockID, err)
+		}
 		content := strings.Join(lines[b.BeginLineIdx+1:b.EndLineIdx], "\n")
-		got := SHA256Hex(content)
+		got := ContentHash(content, n)
 		if got != want {
-			return fmt.Errorf("block %q sha256 mismatch: got %s want %s", blockID, got, want)
+			return fmt.Errorf("block %q %w: got %s want %s", blockID, ErrChecksumMismatch, got, want)
 		}
 		return nil
 	}
-	return fmt.Errorf("block id %q not found", blockID)
+	return fmt.Errorf("%w: %q", ErrBlockNotFound, blockID)
 }
 
-// FindBlocks finds all well-formed managed blocks in the document.
-func FindBlocks(lines []string, prefix string) ([]Block, error) {
+// BlockMeta returns the metadata parsed from the BEGIN marker of the block with blockID.
+// Only markers in syntax are recognised.
+func BlockMeta(doc string, syntax Syntax, prefix, blockID string) (map[string]string, error) {
+	lines, _ := splitLines(doc)
+	blocks, err := FindBlocks(lines, syntax, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range blocks {
+		if b.ID == blockID {
+			return b.Meta, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrBlockNotFound, blockID)
+}
+
+// FindBlocks finds all well-formed managed blocks in the document. Only markers written in
+// syntax are recognised, so a marker-like line in another syntax (say `# GOV:BEGIN` in a
+// Markdown code sample) is plain text; the zero Syntax accepts every supported syntax (see
+// Syntaxes). Malformed markers are reported as a *MarkerError; with several unclosed
+// blocks, the first one is reported.
+func FindBlocks(lines []string, syntax Syntax, prefix string) ([]Block, error) {
 	var out []Block
 	open := map[string]Block{} // id -> block
 
 	for i, line := range lines {
 		trimmed := strings.TrimSpace(line)
-		if meta, ok := parseMarker(trimmed, prefix, "BEGIN"); ok {
+		if meta, found, ok := parseMarker(trimmed, syntax, prefix, "BEGIN"); ok {
 			id := strings.TrimSpace(meta["id"])
 			if id == "" {
-				return nil, fmt.Errorf("BEGIN marker at line %d missing id", i+1)
+				return nil, &MarkerError{Err: ErrMissingID, Kind: "BEGIN", Line: i + 1}
 			}
 			if _, exists := open[id]; exists {
-				return nil, fmt.Errorf("nested/duplicate BEGIN for id %q at line %d", id, i+1)
+				return nil, &MarkerError{Err: ErrDuplicateBegin, Kind: "BEGIN", ID: id, Line: i + 1}
 			}
 			open[id] = Block{
 				ID:           id,
 				BeginLineIdx: i,
 				Meta:         meta,
+				Syntax:       found,
 			}
 			continue
 		}
-		if meta, ok := parseMarker(trimmed, prefix, "END"); ok {
+		if meta, _, ok := parseMarker(trimmed, syntax, prefix, "END"); ok {
 			id := strings.TrimSpace(meta["id"])
 			if id == "" {
-				return nil, fmt.Errorf("END marker at line %d missing id", i+1)
+				return nil, &MarkerError{Err: ErrMissingID, Kind: "END", Line: i + 1}
 			}
 			b, exists := open[id]
 			if !exists {
-				return nil, fmt.Errorf("END without BEGIN for id %q at line %d", id, i+1)
+				return nil, &MarkerError{Err: ErrEndWithoutBegin, Kind: "END", ID: id, Line: i + 1}
 			}
 			delete(open, id)
 			b.EndLineIdx = i
@@ -155,12 +277,14 @@ func FindBlocks(lines []string, prefix string) ([]Block, error) {
 		}
 	}
 	if len(open) > 0 {
-		var ids []string
-		for id := range open {
-			ids = append(ids, id)
+		var first *Block
+		for _, b := range open {
+			if first == nil || b.BeginLineIdx < first.BeginLineIdx {
+				b := b
+				first = &b
+			}
 		}
-		sort.Strings(ids)
-		return nil, fmt.Errorf("unclosed blocks: %s", strings.Join(ids, ", "))
+		return nil, &MarkerError{Err: ErrUnclosedBlock, Kind: "BEGIN", ID: first.ID, Line: first.BeginLineIdx + 1}
 	}
 	sort.Slice(out, func(i, j int) bool { return out[i].BeginLineIdx < out[j].BeginLineIdx })
 	return out, nil
@@ -171,14 +295,13 @@ func SHA256Hex(content string) string {
 	return hex.EncodeToString(sum[:])
 }
 
-// FormatBeginMarker produces a deterministic BEGIN marker line.
+// FormatBeginMarker produces a deterministic HTML-comment BEGIN marker line.
 func FormatBeginMarker(prefix string, meta map[string]string) string {
-	fields := canonicalizeMeta(meta)
-	return "<!-- " + prefix + ":BEGIN " + strings.Join(fields, " ") + " -->"
+	return HTML.BeginMarker(prefix, meta)
 }
 
 func FormatEndMarker(prefix, blockID string) string {
-	return "<!-- " + prefix + ":END id=" + blockID + " -->"
+	return HTML.EndMarker(prefix, blockID)
 }
 
 func canonicalizeMeta(meta map[string]string) []string {
@@ -192,7 +315,7 @@ func canonicalizeMeta(meta map[string]string) []string {
 		if !ok || strings.TrimSpace(v) == "" {
 			continue
 		}
-		fields = append(fields, k+"="+v)
+		fields = append(fields, formatMetaField(k, v))
 		seen[k] = true
 	}
 	var rest []string
@@ -203,38 +326,32 @@ func canonicalizeMeta(meta map[string]string) []string {
 		if strings.TrimSpace(k) == "" || strings.TrimSpace(v) == "" {
 			continue
 		}
-		rest = append(rest, k+"="+v)
+		rest = append(rest, formatMetaField(k, v))
 	}
 	sort.Strings(rest)
 	fields = append(fields, rest...)
 	return fields
 }
 
-func parseMarker(trimmedLine, prefix, kind string) (map[string]string, bool) {
-	if !strings.HasPrefix(trimmedLine, "<!--") || !strings.HasSuffix(trimmedLine, "-->") {
-		return nil, false
-	}
-	body := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(trimmedLine, "<!--"), "-->"))
-	if body == "" {
-		return nil, false
+// parseMarker parses a kind marker line written in syntax, or in any syntax for the zero
+// Syntax, and returns its meta and the syntax it was written in.
+func parseMarker(trimmedLine string, syntax Syntax, prefix, kind string) (map[string]string, Syntax, bool) {
+	candidates := Syntaxes
+	if syntax != (Syntax{}) {
+		candidates = []Syntax{syntax}
 	}
-	parts := strings.Fields(body)
-	if len(parts) == 0 {
-		return nil, false
-	}
-	wantHead := prefix + ":" + kind
-	if parts[0] != wantHead {
-		return nil, false
-	}
-	meta := map[string]string{}
-	for _, tok := range parts[1:] {
-		k, v, ok := strings.Cut(tok, "=")
+	for _, syntax := range candidates {
+		body, ok := syntax.unwrap(trimmedLine)
 		if !ok {
 			continue
 		}
-		meta[k] = v
+		head, meta := parseMetaFields(body)
+		if head != prefix+":"+kind {
+			continue
+		}
+		return meta, syntax, true
 	}
-	return meta, true
+	return nil, Syntax{}, false
 }
 
 func splitLines(s string) ([]string, bool) {
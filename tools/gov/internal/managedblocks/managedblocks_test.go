@@ -20,7 +20,7 @@ func TestFindBlocks_FindsWellFormedBlocks(t *testing.T) {
 	}, "\n")
 	lines, _ := splitLines(doc)
 
-	blocks, err := FindBlocks(lines, "GOV")
+	blocks, err := FindBlocks(lines, HTML, "GOV")
 	if err != nil {
 		t.Fatalf("FindBlocks: %v", err)
 	}
@@ -34,7 +34,7 @@ func TestFindBlocks_FindsWellFormedBlocks(t *testing.T) {
 
 func TestFindBlocks_RejectsUnclosedBlock(t *testing.T) {
 	lines, _ := splitLines("<!-- GOV:BEGIN id=x -->\nhi\n")
-	_, err := FindBlocks(lines, "GOV")
+	_, err := FindBlocks(lines, HTML, "GOV")
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -55,7 +55,7 @@ func TestReplaceBlock_ReplacesOnlyManagedRegionAndUpdatesSHA(t *testing.T) {
 		"- keep me",
 	}, "\n")
 
-	out, err := ReplaceBlock(doc, ReplaceOptions{
+	out, err := ReplaceBlock(doc, HTML, ReplaceOptions{
 		Prefix:     "GOV",
 		BlockID:    "core",
 		NewContent: "NEW1\nNEW2",
@@ -93,7 +93,7 @@ func TestVerifyBlockSHA256_DetectsMismatch(t *testing.T) {
 		"content",
 		"<!-- GOV:END id=core -->",
 	}, "\n")
-	err := VerifyBlockSHA256(doc, "GOV", "core")
+	err := VerifyBlockSHA256(doc, HTML, "GOV", "core")
 	if err == nil {
 		t.Fatalf("expected mismatch error")
 	}
@@ -110,13 +110,13 @@ func TestVerifyBlockSHA256_PassesWhenCorrect(t *testing.T) {
 		"line2",
 		"<!-- GOV:END id=core -->",
 	}, "\n")
-	if err := VerifyBlockSHA256(doc, "GOV", "core"); err != nil {
+	if err := VerifyBlockSHA256(doc, HTML, "GOV", "core"); err != nil {
 		t.Fatalf("expected ok, got %v", err)
 	}
 }
 
 func TestReplaceBlock_ErrorsWhenBlockNotFound(t *testing.T) {
-	_, err := ReplaceBlock("no blocks here\n", ReplaceOptions{
+	_, err := ReplaceBlock("no blocks here\n", HTML, ReplaceOptions{
 		Prefix:     "GOV",
 		BlockID:    "missing",
 		NewContent: "x",
@@ -128,14 +128,14 @@ func TestReplaceBlock_ErrorsWhenBlockNotFound(t *testing.T) {
 
 func TestFindBlocks_ErrorsOnEndWithoutBegin(t *testing.T) {
 	lines, _ := splitLines(FormatEndMarker("GOV", "x") + "\n")
-	_, err := FindBlocks(lines, "GOV")
+	_, err := FindBlocks(lines, HTML, "GOV")
 	if err == nil {
 		t.Fatalf("expected error")
 	}
 }
 
 func TestReplaceBlock_ErrorsOnMissingPrefix(t *testing.T) {
-	_, err := ReplaceBlock("x", ReplaceOptions{Prefix: "", BlockID: "a", NewContent: "b"})
+	_, err := ReplaceBlock("x", HTML, ReplaceOptions{Prefix: "", BlockID: "a", NewContent: "b"})
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -143,7 +143,7 @@ func TestReplaceBlock_ErrorsOnMissingPrefix(t *testing.T) {
 
 func TestFindBlocks_ErrorsOnBeginMissingID(t *testing.T) {
 	lines, _ := splitLines("<!-- GOV:BEGIN version=v1 -->\nX\n<!-- GOV:END id=x -->\n")
-	_, err := FindBlocks(lines, "GOV")
+	_, err := FindBlocks(lines, HTML, "GOV")
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -151,7 +151,7 @@ func TestFindBlocks_ErrorsOnBeginMissingID(t *testing.T) {
 
 func TestFindBlocks_ErrorsOnDuplicateBeginWithoutClose(t *testing.T) {
 	lines, _ := splitLines("<!-- GOV:BEGIN id=x -->\n<!-- GOV:BEGIN id=x -->\n")
-	_, err := FindBlocks(lines, "GOV")
+	_, err := FindBlocks(lines, HTML, "GOV")
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -166,7 +166,7 @@ func TestReplaceBlock_ErrorsOnMultipleBlocksSameID(t *testing.T) {
 		"b",
 		FormatEndMarker("GOV", "x"),
 	}, "\n")
-	_, err := ReplaceBlock(doc, ReplaceOptions{
+	_, err := ReplaceBlock(doc, HTML, ReplaceOptions{
 		Prefix:     "GOV",
 		BlockID:    "x",
 		NewContent: "c",
@@ -182,7 +182,7 @@ func TestVerifyBlockSHA256_ErrorsWhenMissingSha(t *testing.T) {
 		"content",
 		"<!-- GOV:END id=core -->",
 	}, "\n")
-	if err := VerifyBlockSHA256(doc, "GOV", "core"); err == nil {
+	if err := VerifyBlockSHA256(doc, HTML, "GOV", "core"); err == nil {
 		t.Fatalf("expected error")
 	}
 }
@@ -0,0 +1,111 @@
+package managedblocks
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Marker metadata is written as space-separated key=value fields. A value that would not
+// survive that form (whitespace, quotes, "=", non-printable runes, or a sequence that closes
+// the surrounding comment) is written as a Go-style double-quoted string:
+//
+//	sourceRepo="/Users/x/My Repos/gov" sourceRef=main
+//
+// Unquoted values are read verbatim up to the next whitespace, so markers written before
+// quoting was introduced parse exactly as they did.
+
+// formatMetaField renders one key=value field, quoting the value when needed.
+func formatMetaField(key, value string) string {
+	if !needsQuoting(value) {
+		return key + "=" + value
+	}
+	q := strconv.Quote(value)
+	// Keep comment terminators out of the marker line; Unquote reads the escapes back.
+	q = strings.ReplaceAll(q, "-->", `--\x3e`)
+	q = strings.ReplaceAll(q, "*/", `*\x2f`)
+	return key + "=" + q
+}
+
+func needsQuoting(value string) bool {
+	if strings.ContainsAny(value, `"=`) || strings.Contains(value, "-->") || strings.Contains(value, "*/") {
+		return true
+	}
+	for _, r := range value {
+		if r == utf8.RuneError || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMetaFields splits a marker body into its leading word (e.g. "GOV:BEGIN") and its
+// key=value fields. Tokens without "=" are ignored.
+func parseMetaFields(body string) (string, map[string]string) {
+	tokens := metaTokens(body)
+	if len(tokens) == 0 {
+		return "", nil
+	}
+	meta := map[string]string{}
+	for _, tok := range tokens[1:] {
+		k, v, ok := strings.Cut(tok, "=")
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(v, `"`) {
+			if u, err := strconv.Unquote(v); err == nil {
+				v = u
+			}
+		}
+		meta[k] = v
+	}
+	return tokens[0], meta
+}
+
+// metaTokens splits on whitespace, except inside a double-quoted value that directly
+// follows a token's first "=".
+func metaTokens(s string) []string {
+	var tokens []string
+	i := 0
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if unicode.IsSpace(r) {
+			i += size
+			continue
+		}
+		start, seenEq := i, false
+		for i < len(s) {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if unicode.IsSpace(r) {
+				break
+			}
+			i += size
+			if r == '=' && !seenEq {
+				seenEq = true
+				if end := quotedEnd(s, i); end > 0 {
+					i = end
+				}
+			}
+		}
+		tokens = append(tokens, s[start:i])
+	}
+	return tokens
+}
+
+// quotedEnd returns the index just past the quoted string starting at s[i], or -1 when
+// s[i] does not open a terminated quoted string.
+func quotedEnd(s string, i int) int {
+	if i >= len(s) || s[i] != '"' {
+		return -1
+	}
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+	return -1
+}
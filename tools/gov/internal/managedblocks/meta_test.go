@@ -0,0 +1,104 @@
+package managedblocks
+
+import (
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestBeginMarker_QuotesValuesThatNeedIt(t *testing.T) {
+	got := HTML.BeginMarker("GOV", map[string]string{
+		"id":         "doc",
+		"sourceRepo": "/Users/x/My Repos/gov",
+		"sourceRef":  "refs/heads/a=b",
+		"version":    "v1.2.0",
+	})
+	want := `<!-- GOV:BEGIN id=doc version=v1.2.0 sourceRepo="/Users/x/My Repos/gov" sourceRef="refs/heads/a=b" -->`
+	if got != want {
+		t.Fatalf("unexpected marker:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMarkerMeta_RoundTripsSpecialValues(t *testing.T) {
+	values := []string{
+		"plain",
+		"/Users/x/My Repos/gov",
+		"a=b=c",
+		`say "hi"`,
+		`C:\governance\repo`,
+		`"leading quote`,
+		`back\slash"quote`,
+		"ends with -->",
+		"ends with */",
+		"tab\there",
+		"line\nbreak",
+		"non\u00a0breaking\u2028separator",
+		"Ünïcödé ✓ 日本語 🚀",
+		"zero\u200bwidth",
+	}
+	for _, syntax := range Syntaxes {
+		for _, v := range values {
+			line := syntax.BeginMarker("GOV", map[string]string{"id": "doc", "sourceRepo": v, "note": v})
+			if strings.Contains(line, "\n") {
+				t.Fatalf("%s: marker spans lines for %q: %q", syntax.Name, v, line)
+			}
+			meta, got, ok := parseMarker(strings.TrimSpace(line), Syntax{}, "GOV", "BEGIN")
+			if !ok || got != syntax {
+				t.Fatalf("%s: marker for %q did not parse: %q", syntax.Name, v, line)
+			}
+			if meta["id"] != "doc" || meta["sourceRepo"] != v || meta["note"] != v {
+				t.Fatalf("%s: round trip of %q failed: %q -> %#v", syntax.Name, v, line, meta)
+			}
+		}
+	}
+}
+
+func TestMarkerMeta_RoundTripsArbitraryUnicode(t *testing.T) {
+	roundTrip := func(v string) bool {
+		if strings.TrimSpace(v) == "" {
+			// Blank values are dropped from markers.
+			return true
+		}
+		line := BlockComment.BeginMarker("GOV", map[string]string{"id": "doc", "value": v})
+		meta, _, ok := parseMarker(line, Syntax{}, "GOV", "BEGIN")
+		return ok && meta["value"] == v && !strings.ContainsAny(line, "\n\r")
+	}
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEndMarker_QuotesIDWhenNeeded(t *testing.T) {
+	line := Hash.EndMarker("GOV", "my block")
+	if line != `# GOV:END id="my block"` {
+		t.Fatalf("unexpected end marker: %q", line)
+	}
+	meta, _, ok := parseMarker(line, Syntax{}, "GOV", "END")
+	if !ok || meta["id"] != "my block" {
+		t.Fatalf("unexpected parse: %#v", meta)
+	}
+}
+
+func TestParseMarker_AcceptsUnquotedLegacyValues(t *testing.T) {
+	// Markers written before quoting: values are raw up to the next whitespace.
+	line := `<!-- GOV:BEGIN id=doc sourceRepo=C:\gov\repo sourceRef=feature=x fragments=a.md,b.md stray -->`
+	meta, _, ok := parseMarker(line, Syntax{}, "GOV", "BEGIN")
+	if !ok {
+		t.Fatalf("expected legacy marker to parse")
+	}
+	want := map[string]string{"id": "doc", "sourceRepo": `C:\gov\repo`, "sourceRef": "feature=x", "fragments": "a.md,b.md"}
+	if len(meta) != len(want) {
+		t.Fatalf("unexpected meta: %#v", meta)
+	}
+	for k, v := range want {
+		if meta[k] != v {
+			t.Fatalf("meta[%s] = %q, want %q", k, meta[k], v)
+		}
+	}
+
+	// An unterminated quote falls back to the legacy reading instead of swallowing the line.
+	meta, _, ok = parseMarker(`# GOV:BEGIN id=doc note="open version=v1`, Syntax{}, "GOV", "BEGIN")
+	if !ok || meta["note"] != `"open` || meta["version"] != "v1" {
+		t.Fatalf("unexpected meta: %#v", meta)
+	}
+}
@@ -0,0 +1,84 @@
+package managedblocks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Normalization selects how block content is canonicalised before hashing. Modes other
+// than Exact are recorded in the BEGIN marker's "hash" field, so verification uses the
+// mode the block was written with.
+type Normalization string
+
+const (
+	// Exact hashes the content lines as they are (the only mode before normalisation existed).
+	Exact Normalization = "exact"
+	// NormalizeEOL treats CRLF and CR line endings as LF.
+	NormalizeEOL Normalization = "normalize-eol"
+	// NormalizeWhitespace also ignores trailing whitespace, runs of blank lines and
+	// leading/trailing blank lines, as changed by editors and Markdown formatters.
+	NormalizeWhitespace Normalization = "normalize-whitespace"
+)
+
+// HashMetaKey is the BEGIN marker field recording a non-exact Normalization.
+const HashMetaKey = "hash"
+
+// Normalizations lists every supported mode.
+var Normalizations = []Normalization{Exact, NormalizeEOL, NormalizeWhitespace}
+
+// ParseNormalization accepts a mode name; empty means Exact.
+func ParseNormalization(s string) (Normalization, error) {
+	if strings.TrimSpace(s) == "" {
+		return Exact, nil
+	}
+	for _, n := range Normalizations {
+		if string(n) == strings.TrimSpace(s) {
+			return n, nil
+		}
+	}
+	return "", fmt.Errorf("unknown hash normalization %q", s)
+}
+
+// ContentHash returns the sha256 of content after applying n.
+func ContentHash(content string, n Normalization) string {
+	return SHA256Hex(n.apply(content))
+}
+
+// Tag is the value recorded under HashMetaKey; empty (the field is omitted) for Exact.
+func (n Normalization) Tag() string {
+	if n == Exact || n == "" {
+		return ""
+	}
+	return string(n)
+}
+
+func (n Normalization) apply(content string) string {
+	switch n {
+	case NormalizeEOL, NormalizeWhitespace:
+		// Block content is the marker-delimited lines joined with LF, so in a CRLF file
+		// every line (including the last) still ends in CR.
+		lines := strings.Split(content, "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimSuffix(l, "\r")
+		}
+		content = strings.ReplaceAll(strings.Join(lines, "\n"), "\r", "\n")
+	}
+	if n != NormalizeWhitespace {
+		return content
+	}
+	var out []string
+	blank := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			blank = len(out) > 0
+			continue
+		}
+		if blank {
+			out = append(out, "")
+			blank = false
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
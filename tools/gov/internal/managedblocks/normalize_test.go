@@ -0,0 +1,81 @@
+package managedblocks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContentHash_Normalizations(t *testing.T) {
+	base := "# Rules\n\n- one\n- two"
+	cases := []struct {
+		variant string
+		eol     bool
+		ws      bool
+	}{
+		{variant: base, eol: true, ws: true},
+		{variant: "# Rules\r\n\r\n- one\r\n- two", eol: true, ws: true},
+		{variant: "# Rules\r\r- one\r- two", eol: true, ws: true},
+		{variant: "# Rules  \n\n- one\t\n- two", eol: false, ws: true},
+		{variant: "\n# Rules\n\n\n\n- one\n- two\n\n", eol: false, ws: true},
+		{variant: "# Rules\n- one\n- two", eol: false, ws: false},
+		{variant: "  # Rules\n\n- one\n- two", eol: false, ws: false},
+	}
+	for _, c := range cases {
+		if got := ContentHash(c.variant, NormalizeEOL) == ContentHash(base, NormalizeEOL); got != c.eol {
+			t.Fatalf("normalize-eol match for %q = %v, want %v", c.variant, got, c.eol)
+		}
+		if got := ContentHash(c.variant, NormalizeWhitespace) == ContentHash(base, NormalizeWhitespace); got != c.ws {
+			t.Fatalf("normalize-whitespace match for %q = %v, want %v", c.variant, got, c.ws)
+		}
+	}
+	if ContentHash(base, Exact) != SHA256Hex(base) || ContentHash(base, "") != SHA256Hex(base) {
+		t.Fatalf("exact hashing must match SHA256Hex")
+	}
+}
+
+func TestParseNormalization(t *testing.T) {
+	for in, want := range map[string]Normalization{"": Exact, "exact": Exact, "normalize-eol": NormalizeEOL, " normalize-whitespace ": NormalizeWhitespace} {
+		got, err := ParseNormalization(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseNormalization(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseNormalization("crc32"); err == nil || !strings.Contains(err.Error(), `unknown hash normalization "crc32"`) {
+		t.Fatalf("expected unknown normalization error, got %v", err)
+	}
+}
+
+func TestVerifyBlockSHA256_UsesRecordedNormalization(t *testing.T) {
+	doc := "intro\n" + FormatBeginMarker("GOV", map[string]string{"id": "b"}) + "\nx\n" + FormatEndMarker("GOV", "b") + "\n"
+	out, err := ReplaceBlock(doc, HTML, ReplaceOptions{Prefix: "GOV", BlockID: "b", NewContent: "# Rules\n\n- one", Normalization: NormalizeEOL})
+	if err != nil {
+		t.Fatalf("ReplaceBlock: %v", err)
+	}
+	if !strings.Contains(out, " hash=normalize-eol ") {
+		t.Fatalf("expected hash tag in marker:\n%s", out)
+	}
+	crlf := strings.ReplaceAll(out, "\n", "\r\n")
+	if err := VerifyBlockSHA256(crlf, HTML, "GOV", "b"); err != nil {
+		t.Fatalf("CRLF checkout should verify with normalize-eol: %v", err)
+	}
+	if err := VerifyBlockSHA256(strings.Replace(crlf, "- one", "- one ", 1), HTML, "GOV", "b"); err == nil {
+		t.Fatalf("expected trailing whitespace to matter under normalize-eol")
+	}
+
+	// Switching back to exact drops the tag.
+	exact, err := ReplaceBlock(out, HTML, ReplaceOptions{Prefix: "GOV", BlockID: "b", NewContent: "# Rules\n\n- one"})
+	if err != nil {
+		t.Fatalf("ReplaceBlock: %v", err)
+	}
+	if strings.Contains(exact, "hash=") {
+		t.Fatalf("exact markers must not carry a hash tag:\n%s", exact)
+	}
+	if err := VerifyBlockSHA256(strings.ReplaceAll(exact, "\n", "\r\n"), HTML, "GOV", "b"); err == nil {
+		t.Fatalf("expected CRLF to fail exact verification")
+	}
+
+	bad := strings.Replace(out, "hash=normalize-eol", "hash=md5", 1)
+	if err := VerifyBlockSHA256(bad, HTML, "GOV", "b"); err == nil || !strings.Contains(err.Error(), `unknown hash normalization "md5"`) {
+		t.Fatalf("expected unknown normalization error, got %v", err)
+	}
+}
@@ -0,0 +1,330 @@
+package managedblocks
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Marker is a BEGIN or END marker line as found by ScanMarkers.
+type Marker struct {
+	// Kind is "BEGIN" or "END".
+	Kind    string
+	ID      string
+	LineIdx int
+	Meta    map[string]string
+	Syntax  Syntax
+}
+
+// ScanMarkers returns every marker line for prefix written in syntax (any syntax for the
+// zero Syntax) in document order, without checking that they pair up (unlike FindBlocks).
+func ScanMarkers(lines []string, syntax Syntax, prefix string) []Marker {
+	var out []Marker
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		for _, kind := range []string{"BEGIN", "END"} {
+			if meta, found, ok := parseMarker(trimmed, syntax, prefix, kind); ok {
+				out = append(out, Marker{Kind: kind, ID: strings.TrimSpace(meta["id"]), LineIdx: i, Meta: meta, Syntax: found})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// RepairOptions controls RepairBlock.
+type RepairOptions struct {
+	Prefix  string
+	BlockID string
+	// Expected is the block's upstream content; it locates where the block lives.
+	Expected string
+	// Syntax is the document's comment syntax: only markers in it are recognised, and
+	// new markers are written in it when none of the block's markers survive.
+	Syntax Syntax
+	// Meta is the BEGIN metadata used when no BEGIN marker survives.
+	Meta map[string]string
+}
+
+// Repair is the outcome of RepairBlock.
+type Repair struct {
+	Doc string
+	// Changed is false when the block's markers were already intact.
+	Changed bool
+	// Description explains the damage and the fix, e.g.
+	// "missing END marker; block now spans lines 6-12".
+	Description string
+}
+
+// ErrContentNotFound is returned when a block without usable markers cannot be located.
+var ErrContentNotFound = errors.New("could not locate the block's content")
+
+// RepairBlock rewrites the markers of one block so that it is well-formed again. Surviving
+// BEGIN and END markers anchor the block; a missing boundary is placed where the window
+// best matches Expected (line-based longest common subsequence). Only marker lines are
+// removed or inserted, so text outside the block is kept as is.
+func RepairBlock(doc string, opts RepairOptions) (Repair, error) {
+	if strings.TrimSpace(opts.Prefix) == "" {
+		return Repair{}, errors.New("prefix is required")
+	}
+	if strings.TrimSpace(opts.BlockID) == "" {
+		return Repair{}, errors.New("block id is required")
+	}
+	lines, trailingNewline := splitLines(doc)
+	var mine, others []Marker
+	for _, m := range ScanMarkers(lines, opts.Syntax, opts.Prefix) {
+		if m.ID == opts.BlockID {
+			mine = append(mine, m)
+		} else {
+			others = append(others, m)
+		}
+	}
+	var begins, ends []Marker
+	for _, m := range mine {
+		if m.Kind == "BEGIN" {
+			begins = append(begins, m)
+		} else {
+			ends = append(ends, m)
+		}
+	}
+	if len(begins) == 1 && len(ends) == 1 && begins[0].LineIdx < ends[0].LineIdx {
+		return Repair{Doc: doc}, nil
+	}
+
+	// Drop this block's markers; positions below refer to the remaining lines.
+	removed := map[int]bool{}
+	for _, m := range mine {
+		removed[m.LineIdx] = true
+	}
+	var cleaned []string
+	for i, l := range lines {
+		if !removed[i] {
+			cleaned = append(cleaned, l)
+		}
+	}
+	pos := func(orig int) int {
+		n := 0
+		for idx := range removed {
+			if idx < orig {
+				n++
+			}
+		}
+		return orig - n
+	}
+	var boundaries []int
+	for _, m := range others {
+		boundaries = append(boundaries, pos(m.LineIdx))
+	}
+	want, _ := splitLines(opts.Expected)
+	if strings.TrimSpace(opts.Expected) == "" {
+		want = nil
+	}
+
+	var starts, stops []int
+	for _, m := range begins {
+		starts = append(starts, pos(m.LineIdx))
+	}
+	for _, m := range ends {
+		stops = append(stops, pos(m.LineIdx))
+	}
+
+	var best window
+	switch {
+	case len(starts) > 0 && len(stops) > 0:
+		for _, s := range starts {
+			for _, e := range stops {
+				if e >= s && !crosses(boundaries, s, e) {
+					best = best.better(scoreWindow(cleaned[s:e], want), s, e)
+				}
+			}
+		}
+		if best.found() {
+			break
+		}
+		fallthrough
+	case len(starts) > 0:
+		for _, s := range starts {
+			e, sc := bestEnd(cleaned, s, nextBoundary(boundaries, s, len(cleaned)), want)
+			best = best.better(sc, s, e)
+		}
+	case len(stops) > 0:
+		for _, e := range stops {
+			s, sc := bestStart(cleaned, prevBoundary(boundaries, e), e, want)
+			best = best.better(sc, s, e)
+		}
+	default:
+		for s := range cleaned {
+			if !matchesAny(cleaned[s], want) {
+				continue
+			}
+			e, sc := bestEnd(cleaned, s, nextBoundary(boundaries, s, len(cleaned)), want)
+			best = best.better(sc, s, e)
+		}
+	}
+	if !best.found() || (len(want) > 0 && best.score <= 0) {
+		return Repair{}, fmt.Errorf("block %q: %s: %w", opts.BlockID, describeDamage(begins, ends), ErrContentNotFound)
+	}
+
+	syntax := opts.Syntax
+	if len(mine) > 0 {
+		syntax = mine[0].Syntax
+	}
+	meta := map[string]string{}
+	if len(begins) > 0 {
+		for k, v := range begins[0].Meta {
+			meta[k] = v
+		}
+	} else {
+		for k, v := range opts.Meta {
+			meta[k] = v
+		}
+		meta["sha256"] = ContentHash(strings.Join(want, "\n"), Normalization(meta[HashMetaKey]))
+	}
+	meta["id"] = opts.BlockID
+
+	out := append([]string{}, cleaned[:best.start]...)
+	out = append(out, syntax.BeginMarker(opts.Prefix, meta))
+	out = append(out, cleaned[best.start:best.end]...)
+	out = append(out, syntax.EndMarker(opts.Prefix, opts.BlockID))
+	out = append(out, cleaned[best.end:]...)
+	return Repair{
+		Doc:         joinLines(out, trailingNewline),
+		Changed:     true,
+		Description: fmt.Sprintf("%s; block now spans lines %d-%d", describeDamage(begins, ends), best.start+1, best.end+2),
+	}, nil
+}
+
+type window struct {
+	start, end, score int
+	ok                bool
+}
+
+func (w window) found() bool { return w.ok }
+
+func (w window) better(score, start, end int) window {
+	if !w.ok || score > w.score {
+		return window{start: start, end: end, score: score, ok: true}
+	}
+	return w
+}
+
+// scoreWindow rewards matched lines and penalises unmatched ones: 2*LCS - len(window).
+func scoreWindow(doc, want []string) int {
+	return 2*lcs(doc, want) - len(doc)
+}
+
+// bestEnd returns the end (exclusive, in [s, limit]) whose window doc[s:end] best matches want.
+func bestEnd(doc []string, s, limit int, want []string) (int, int) {
+	row := make([]int, len(want)+1)
+	end, score := s, 0
+	for e := s; e < limit; e++ {
+		row = lcsRow(row, doc[e], want)
+		if sc := 2*row[len(want)] - (e + 1 - s); sc > score {
+			end, score = e+1, sc
+		}
+	}
+	return end, score
+}
+
+// bestStart is bestEnd in reverse: the start in [lower, e] whose window doc[start:e] best matches want.
+func bestStart(doc []string, lower, e int, want []string) (int, int) {
+	rdoc := reverse(doc[lower:e])
+	n, score := bestEnd(rdoc, 0, len(rdoc), reverse(want))
+	return e - n, score
+}
+
+func lcs(a, b []string) int {
+	row := make([]int, len(b)+1)
+	for _, line := range a {
+		row = lcsRow(row, line, b)
+	}
+	return row[len(b)]
+}
+
+// lcsRow extends a longest-common-subsequence DP row (over want) by one document line.
+func lcsRow(prev []int, line string, want []string) []int {
+	cur := make([]int, len(want)+1)
+	for j := 1; j <= len(want); j++ {
+		switch {
+		case sameLine(line, want[j-1]):
+			cur[j] = prev[j-1] + 1
+		case prev[j] >= cur[j-1]:
+			cur[j] = prev[j]
+		default:
+			cur[j] = cur[j-1]
+		}
+	}
+	return cur
+}
+
+func sameLine(a, b string) bool {
+	return strings.TrimSpace(a) == strings.TrimSpace(b)
+}
+
+func matchesAny(line string, want []string) bool {
+	if strings.TrimSpace(line) == "" {
+		return false
+	}
+	for _, w := range want {
+		if sameLine(line, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func reverse(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[len(lines)-1-i] = l
+	}
+	return out
+}
+
+// crosses reports whether another block's marker lies inside [s, e).
+func crosses(boundaries []int, s, e int) bool {
+	for _, b := range boundaries {
+		if b >= s && b < e {
+			return true
+		}
+	}
+	return false
+}
+
+func nextBoundary(boundaries []int, s, fallback int) int {
+	limit := fallback
+	for _, b := range boundaries {
+		if b >= s && b < limit {
+			limit = b
+		}
+	}
+	return limit
+}
+
+func prevBoundary(boundaries []int, e int) int {
+	lower := 0
+	for _, b := range boundaries {
+		if b < e && b+1 > lower {
+			lower = b + 1
+		}
+	}
+	return lower
+}
+
+func describeDamage(begins, ends []Marker) string {
+	switch {
+	case len(begins) == 0 && len(ends) == 0:
+		return "missing BEGIN and END markers"
+	case len(begins) == 0:
+		return "missing BEGIN marker"
+	case len(ends) == 0 && len(begins) > 1:
+		return fmt.Sprintf("duplicate BEGIN markers (%d) and missing END marker", len(begins))
+	case len(ends) == 0:
+		return "missing END marker"
+	case len(begins) > 1:
+		return fmt.Sprintf("duplicate BEGIN markers (%d)", len(begins))
+	case len(ends) > 1:
+		return fmt.Sprintf("duplicate END markers (%d)", len(ends))
+	default:
+		return "END marker before BEGIN marker"
+	}
+}
@@ -0,0 +1,132 @@
+package managedblocks
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func repairFixture() (string, string) {
+	begin := FormatBeginMarker("GOV", map[string]string{"id": "doc", "version": "v1", "sha256": SHA256Hex("# Rules\n\n- one\n- two")})
+	end := FormatEndMarker("GOV", "doc")
+	return begin, end
+}
+
+func TestRepairBlock_IntactBlockIsUnchanged(t *testing.T) {
+	begin, end := repairFixture()
+	doc := strings.Join([]string{begin, "# Rules", "", "- one", "- two", end, "", "addendum"}, "\n") + "\n"
+	r, err := RepairBlock(doc, RepairOptions{Prefix: "GOV", BlockID: "doc", Expected: "# Rules\n\n- one\n- two"})
+	if err != nil {
+		t.Fatalf("RepairBlock: %v", err)
+	}
+	if r.Changed || r.Doc != doc {
+		t.Fatalf("expected no change, got %+v", r)
+	}
+}
+
+func TestRepairBlock_MissingEndKeepsAddendaOutside(t *testing.T) {
+	begin, end := repairFixture()
+	doc := strings.Join([]string{"intro", begin, "# Rules", "", "- one", "- two", "", "## Addenda", "local rule"}, "\n") + "\n"
+	r, err := RepairBlock(doc, RepairOptions{Prefix: "GOV", BlockID: "doc", Expected: "# Rules\n\n- one\n- two"})
+	if err != nil {
+		t.Fatalf("RepairBlock: %v", err)
+	}
+	want := strings.Join([]string{"intro", begin, "# Rules", "", "- one", "- two", end, "", "## Addenda", "local rule"}, "\n") + "\n"
+	if !r.Changed || r.Doc != want {
+		t.Fatalf("unexpected repair:\n%s\nwant:\n%s", r.Doc, want)
+	}
+	if r.Description != "missing END marker; block now spans lines 2-7" {
+		t.Fatalf("unexpected description: %q", r.Description)
+	}
+	if err := VerifyBlockSHA256(r.Doc, HTML, "GOV", "doc"); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+}
+
+func TestRepairBlock_DuplicateBeginKeepsTheOneMatchingContent(t *testing.T) {
+	begin, end := repairFixture()
+	doc := strings.Join([]string{begin, "stray text", begin, "# Rules", "", "- one", "- two", end, "addendum"}, "\n") + "\n"
+	r, err := RepairBlock(doc, RepairOptions{Prefix: "GOV", BlockID: "doc", Expected: "# Rules\n\n- one\n- two"})
+	if err != nil {
+		t.Fatalf("RepairBlock: %v", err)
+	}
+	want := strings.Join([]string{"stray text", begin, "# Rules", "", "- one", "- two", end, "addendum"}, "\n") + "\n"
+	if r.Doc != want {
+		t.Fatalf("unexpected repair:\n%s\nwant:\n%s", r.Doc, want)
+	}
+	if !strings.HasPrefix(r.Description, "duplicate BEGIN markers (2)") {
+		t.Fatalf("unexpected description: %q", r.Description)
+	}
+}
+
+func TestRepairBlock_MissingBeginAndEditedContent(t *testing.T) {
+	_, end := repairFixture()
+	// The block was edited locally (one line changed), so matching has to be fuzzy.
+	doc := strings.Join([]string{"project preamble", "", "# Rules", "", "- one (edited)", "- two", end}, "\n") + "\n"
+	r, err := RepairBlock(doc, RepairOptions{
+		Prefix:   "GOV",
+		BlockID:  "doc",
+		Expected: "# Rules\n\n- one\n- two",
+		Syntax:   HTML,
+		Meta:     map[string]string{"version": "v1"},
+	})
+	if err != nil {
+		t.Fatalf("RepairBlock: %v", err)
+	}
+	lines := strings.Split(r.Doc, "\n")
+	if lines[0] != "project preamble" || lines[1] != "" || !strings.HasPrefix(lines[2], "<!-- GOV:BEGIN id=doc version=v1 sha256=") || lines[3] != "# Rules" {
+		t.Fatalf("unexpected repair:\n%s", r.Doc)
+	}
+	if r.Description != "missing BEGIN marker; block now spans lines 3-8" {
+		t.Fatalf("unexpected description: %q", r.Description)
+	}
+}
+
+func TestRepairBlock_RecreatesBothMarkersInFileSyntax(t *testing.T) {
+	doc := "# project settings\nkey: value\nmanaged: true\nlint: strict\n"
+	r, err := RepairBlock(doc, RepairOptions{Prefix: "GOV", BlockID: "cfg", Expected: "managed: true\nlint: strict", Syntax: Hash})
+	if err != nil {
+		t.Fatalf("RepairBlock: %v", err)
+	}
+	want := "# project settings\nkey: value\n" + Hash.BeginMarker("GOV", map[string]string{"id": "cfg", "sha256": SHA256Hex("managed: true\nlint: strict")}) +
+		"\nmanaged: true\nlint: strict\n" + Hash.EndMarker("GOV", "cfg") + "\n"
+	if r.Doc != want {
+		t.Fatalf("unexpected repair:\n%s\nwant:\n%s", r.Doc, want)
+	}
+}
+
+func TestRepairBlock_DoesNotCrossOtherBlocks(t *testing.T) {
+	begin, _ := repairFixture()
+	other := []string{FormatBeginMarker("GOV", map[string]string{"id": "other"}), "- one", FormatEndMarker("GOV", "other")}
+	doc := strings.Join(append([]string{begin, "# Rules", ""}, other...), "\n") + "\n"
+	r, err := RepairBlock(doc, RepairOptions{Prefix: "GOV", BlockID: "doc", Expected: "# Rules\n\n- one\n- two"})
+	if err != nil {
+		t.Fatalf("RepairBlock: %v", err)
+	}
+	lines, _ := splitLines(r.Doc)
+	if _, err := FindBlocks(lines, HTML, "GOV"); err != nil {
+		t.Fatalf("repaired document is still malformed: %v\n%s", err, r.Doc)
+	}
+	if lines[3] != FormatEndMarker("GOV", "doc") {
+		t.Fatalf("expected END before the other block:\n%s", r.Doc)
+	}
+}
+
+func TestRepairBlock_ContentNotFound(t *testing.T) {
+	doc := "nothing\nto see\n"
+	_, err := RepairBlock(doc, RepairOptions{Prefix: "GOV", BlockID: "doc", Expected: "# Rules\n- one"})
+	if !errors.Is(err, ErrContentNotFound) {
+		t.Fatalf("expected ErrContentNotFound, got %v", err)
+	}
+}
+
+func TestScanMarkers_ReportsUnpairedMarkers(t *testing.T) {
+	lines := []string{"<!-- GOV:BEGIN id=a -->", "# GOV:BEGIN id=a", "x", "// GOV:END id=b"}
+	got := ScanMarkers(lines, Syntax{}, "GOV")
+	if len(got) != 3 {
+		t.Fatalf("expected 3 markers, got %+v", got)
+	}
+	if got[0].Kind != "BEGIN" || got[0].Syntax != HTML || got[1].Syntax != Hash || got[2].Kind != "END" || got[2].ID != "b" || got[2].LineIdx != 3 {
+		t.Fatalf("unexpected markers: %+v", got)
+	}
+}
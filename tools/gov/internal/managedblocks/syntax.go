@@ -0,0 +1,104 @@
+package managedblocks
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Syntax is the comment style marker lines are written in.
+type Syntax struct {
+	Name string
+	// Open and Close delimit the marker comment; Close is empty for line comments.
+	Open  string
+	Close string
+}
+
+var (
+	// HTML markers (`<!-- GOV:BEGIN ... -->`) are used for Markdown and HTML documents.
+	HTML = Syntax{Name: "html", Open: "<!--", Close: "-->"}
+	// Hash markers (`# GOV:BEGIN ...`) suit YAML, TOML, Makefiles, shell and ignore files.
+	Hash = Syntax{Name: "hash", Open: "#"}
+	// Slash markers (`// GOV:BEGIN ...`) suit Go and other C-family sources.
+	Slash = Syntax{Name: "slash", Open: "//"}
+	// BlockComment markers (`/* GOV:BEGIN ... */`) suit CSS and other block-comment-only formats.
+	BlockComment = Syntax{Name: "block", Open: "/*", Close: "*/"}
+)
+
+// Syntaxes lists every supported comment syntax. Parsing with the zero Syntax tries them
+// in this order.
+var Syntaxes = []Syntax{HTML, BlockComment, Slash, Hash}
+
+var syntaxByExt = map[string]Syntax{
+	".md": HTML, ".markdown": HTML, ".html": HTML, ".htm": HTML, ".xml": HTML,
+
+	".yml": Hash, ".yaml": Hash, ".toml": Hash, ".sh": Hash, ".bash": Hash, ".py": Hash,
+	".rb": Hash, ".mk": Hash, ".cfg": Hash, ".conf": Hash, ".tf": Hash, ".gitignore": Hash,
+	".dockerignore": Hash, ".gitattributes": Hash, ".editorconfig": Hash, ".env": Hash,
+
+	".go": Slash, ".js": Slash, ".ts": Slash, ".jsx": Slash, ".tsx": Slash, ".swift": Slash,
+	".kt": Slash, ".java": Slash, ".c": Slash, ".h": Slash, ".cc": Slash, ".cpp": Slash,
+	".rs": Slash, ".proto": Slash, ".jsonc": Slash,
+
+	".css": BlockComment,
+}
+
+var syntaxByBase = map[string]Syntax{
+	"Makefile":    Hash,
+	"GNUmakefile": Hash,
+	"Dockerfile":  Hash,
+	"CODEOWNERS":  Hash,
+}
+
+// SyntaxByName returns the syntax named html, hash, slash or block.
+func SyntaxByName(name string) (Syntax, bool) {
+	for _, s := range Syntaxes {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Syntax{}, false
+}
+
+// SyntaxForPath selects a syntax from the file name or extension, defaulting to HTML.
+func SyntaxForPath(path string) Syntax {
+	base := filepath.Base(path)
+	if s, ok := syntaxByBase[base]; ok {
+		return s
+	}
+	if s, ok := syntaxByExt[strings.ToLower(filepath.Ext(base))]; ok {
+		return s
+	}
+	return HTML
+}
+
+// BeginMarker produces a deterministic BEGIN marker line in this syntax.
+func (s Syntax) BeginMarker(prefix string, meta map[string]string) string {
+	return s.wrap(prefix + ":BEGIN " + strings.Join(canonicalizeMeta(meta), " "))
+}
+
+// EndMarker produces the END marker line for blockID in this syntax.
+func (s Syntax) EndMarker(prefix, blockID string) string {
+	return s.wrap(prefix + ":END " + formatMetaField("id", blockID))
+}
+
+func (s Syntax) wrap(body string) string {
+	if s.Close == "" {
+		return s.Open + " " + body
+	}
+	return s.Open + " " + body + " " + s.Close
+}
+
+// unwrap returns the comment body of a trimmed marker line written in this syntax.
+func (s Syntax) unwrap(trimmedLine string) (string, bool) {
+	if !strings.HasPrefix(trimmedLine, s.Open) {
+		return "", false
+	}
+	body := strings.TrimPrefix(trimmedLine, s.Open)
+	if s.Close != "" {
+		if !strings.HasSuffix(body, s.Close) {
+			return "", false
+		}
+		body = strings.TrimSuffix(body, s.Close)
+	}
+	return strings.TrimSpace(body), true
+}
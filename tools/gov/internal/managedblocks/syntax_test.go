@@ -0,0 +1,137 @@
+package managedblocks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSyntaxForPath_SelectsByNameAndExtension(t *testing.T) {
+	cases := map[string]string{
+		"Constitution.md":   "html",
+		".golangci.yml":     "hash",
+		"ci/.gitignore":     "hash",
+		".editorconfig":     "hash",
+		"Makefile":          "hash",
+		"internal/doc.go":   "slash",
+		"web/site.css":      "block",
+		"NOTICE":            "html",
+		"config/app.TOML":   "hash",
+		"services/Makefile": "hash",
+	}
+	for path, want := range cases {
+		if got := SyntaxForPath(path).Name; got != want {
+			t.Fatalf("SyntaxForPath(%q) = %s, want %s", path, got, want)
+		}
+	}
+	if _, ok := SyntaxByName("semicolon"); ok {
+		t.Fatalf("expected unknown syntax")
+	}
+}
+
+func TestUpsertBlock_AppendsThenReplacesInEachSyntax(t *testing.T) {
+	for _, syntax := range Syntaxes {
+		t.Run(syntax.Name, func(t *testing.T) {
+			doc := "project line 1\nproject line 2\n"
+			out, err := UpsertBlock(doc, syntax, ReplaceOptions{Prefix: "GOV", BlockID: "cfg", NewContent: "managed v1", MetaUpdates: map[string]string{"version": "v1"}})
+			if err != nil {
+				t.Fatalf("UpsertBlock append: %v", err)
+			}
+			wantBegin := syntax.BeginMarker("GOV", map[string]string{"id": "cfg", "version": "v1", "sha256": SHA256Hex("managed v1")})
+			want := doc + "\n" + wantBegin + "\nmanaged v1\n" + syntax.EndMarker("GOV", "cfg") + "\n"
+			if out != want {
+				t.Fatalf("unexpected append result:\n%s\nwant:\n%s", out, want)
+			}
+			if err := VerifyBlockSHA256(out, syntax, "GOV", "cfg"); err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+
+			out, err = UpsertBlock(out+"trailing project line\n", syntax, ReplaceOptions{Prefix: "GOV", BlockID: "cfg", NewContent: "managed v2", MetaUpdates: map[string]string{"version": "v2"}})
+			if err != nil {
+				t.Fatalf("UpsertBlock replace: %v", err)
+			}
+			// The existing block keeps its position.
+			if !strings.HasPrefix(out, doc+"\n"+syntax.Open+" GOV:BEGIN id=cfg version=v2 ") || !strings.HasSuffix(out, "\nmanaged v2\n"+syntax.EndMarker("GOV", "cfg")+"\ntrailing project line\n") {
+				t.Fatalf("unexpected replace result:\n%s", out)
+			}
+			if err := VerifyBlockSHA256(out, syntax, "GOV", "cfg"); err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+		})
+	}
+}
+
+func TestFindBlocks_IgnoresOtherCommentsAndPrefixes(t *testing.T) {
+	lines := []string{
+		"# regular comment",
+		"// OTHER:BEGIN id=x",
+		"#GOV:BEGIN id=a sha256=1",
+		"a",
+		"#GOV:END id=a",
+		"/* GOV:BEGIN id=b */",
+		"/* GOV:END id=b */",
+	}
+	blocks, err := FindBlocks(lines, Syntax{}, "GOV")
+	if err != nil {
+		t.Fatalf("FindBlocks: %v", err)
+	}
+	if len(blocks) != 2 || blocks[0].ID != "a" || blocks[0].Syntax != Hash || blocks[1].ID != "b" || blocks[1].Syntax != BlockComment {
+		t.Fatalf("unexpected blocks: %+v", blocks)
+	}
+}
+
+func TestFindBlocks_OnlyRecognisesTheDocumentSyntax(t *testing.T) {
+	doc := strings.Join([]string{
+		"<!-- GOV:BEGIN id=doc sha256=x -->",
+		"Example config:",
+		"",
+		"```yaml",
+		"# GOV:BEGIN id=cfg",
+		"lint: strict",
+		"```",
+		"// GOV:END id=other",
+		"<!-- GOV:END id=doc -->",
+	}, "\n") + "\n"
+	lines, _ := splitLines(doc)
+	blocks, err := FindBlocks(lines, HTML, "GOV")
+	if err != nil {
+		t.Fatalf("FindBlocks: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].ID != "doc" || blocks[0].EndLineIdx != 8 {
+		t.Fatalf("unexpected blocks: %+v", blocks)
+	}
+	if _, err := FindBlocks(lines, Syntax{}, "GOV"); err == nil {
+		t.Fatalf("expected the zero Syntax to see the code sample's markers")
+	}
+
+	out, err := ReplaceBlock(doc, HTML, ReplaceOptions{Prefix: "GOV", BlockID: "doc", NewContent: "replaced"})
+	if err != nil {
+		t.Fatalf("ReplaceBlock: %v", err)
+	}
+	if err := VerifyBlockSHA256(out, HTML, "GOV", "doc"); err != nil || strings.Contains(out, "lint: strict") {
+		t.Fatalf("unexpected replace result (%v):\n%s", err, out)
+	}
+}
+
+func TestUpsertBlock_PlaceTop(t *testing.T) {
+	opts := ReplaceOptions{Prefix: "GOV", BlockID: "hdr", NewContent: "Copyright Example", Placement: PlaceTop}
+	block := Slash.BeginMarker("GOV", map[string]string{"id": "hdr", "sha256": SHA256Hex("Copyright Example")}) + "\nCopyright Example\n" + Slash.EndMarker("GOV", "hdr") + "\n"
+	for _, c := range []struct{ doc, want string }{
+		{"package main\n", block + "\npackage main\n"},
+		{"\n\npackage main\n", block + "\npackage main\n"},
+		{"#!/bin/sh\necho hi\n", "#!/bin/sh\n" + block + "\necho hi\n"},
+		{"", block},
+	} {
+		out, err := UpsertBlock(c.doc, Slash, opts)
+		if err != nil {
+			t.Fatalf("UpsertBlock(%q): %v", c.doc, err)
+		}
+		if out != c.want {
+			t.Fatalf("UpsertBlock(%q):\n%s\nwant:\n%s", c.doc, out, c.want)
+		}
+	}
+	// An existing block is updated where it is.
+	out, err := UpsertBlock("package main\n\n"+block, Slash, opts)
+	if err != nil || !strings.HasPrefix(out, "package main\n\n// GOV:BEGIN id=hdr ") {
+		t.Fatalf("unexpected update (%v):\n%s", err, out)
+	}
+}
@@ -0,0 +1,40 @@
+package pathglob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"**/testdata/**", "internal/x/testdata/a.json", true},
+		{"**/testdata/**", "testdata/a.json", true},
+		{"**/*.golden", "a/b/c.golden", true},
+		{"**/*.golden", "c.golden", true},
+		{"api/*.proto", "api/v1/x.proto", false},
+		{"api/**/*.proto", "api/v1/x.proto", true},
+		{"api/**/*.proto", "api/x.proto", true},
+		{"internal/openapi/**", "internal/openapi", true},
+		{"*.md", "Docs/x.md", false},
+		{"./Docs/*.md", "Docs/x.md", true},
+		{"[", "[", false},
+	}
+	for _, tc := range cases {
+		if got := Match(tc.pattern, tc.name); got != tc.want {
+			t.Fatalf("Match(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMatchAnyAndHasMeta(t *testing.T) {
+	if !MatchAny([]string{"x", "**/*.go"}, "a/b.go") {
+		t.Fatalf("expected match")
+	}
+	if MatchAny(nil, "a") {
+		t.Fatalf("expected no match")
+	}
+	if !HasMeta("a/*.md") || HasMeta("a/b.md") {
+		t.Fatalf("unexpected HasMeta result")
+	}
+}
@@ -0,0 +1,88 @@
+package profile
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Source is a governance repository checkout that a cross-repository extends resolved to.
+type Source struct {
+	Repo   string
+	Ref    string
+	Commit string
+	// Dir is the local checkout the base manifest is read from.
+	Dir string
+	// FS serves Dir's files; nil reads them from disk.
+	FS fs.FS
+}
+
+// Resolver fetches repo at ref (e.g. via source.Fetch into the cache) and returns the checkout.
+type Resolver func(repo, ref string) (Source, error)
+
+// LoadOptions configures LoadManifestWith.
+type LoadOptions struct {
+	// Resolve fetches cross-repository extends entries; without it they are an error.
+	Resolve Resolver
+	// FS serves the files under Root (the governance checkout): manifests, fragment
+	// directories and globs, and `order:` frontmatter are read through it. Without it,
+	// and for paths outside Root, files are read from disk.
+	Root string
+	FS   fs.FS
+
+	files files
+}
+
+// ParseRemoteExtends splits an extends entry of the form `repo@ref#path/to/profile`.
+// ok is false for plain (manifest-relative) paths. The repo may itself contain `@`
+// (e.g. `git@host:org/repo.git`); the ref is taken after the last `@`.
+func ParseRemoteExtends(entry string) (repo, ref, profilePath string, ok bool) {
+	hash := strings.Index(entry, "#")
+	if hash < 0 {
+		return "", "", "", false
+	}
+	left, profilePath := entry[:hash], entry[hash+1:]
+	at := strings.LastIndex(left, "@")
+	if at < 0 {
+		return "", "", "", false
+	}
+	repo, ref = left[:at], left[at+1:]
+	if strings.TrimSpace(repo) == "" || strings.TrimSpace(ref) == "" {
+		return "", "", "", false
+	}
+	return repo, ref, profilePath, true
+}
+
+// resolveRemoteExtends fetches the repository named by a remote extends entry and returns
+// the base manifest path inside its checkout.
+func resolveRemoteExtends(entry string, opts LoadOptions) (string, Source, error) {
+	repo, ref, rel, _ := ParseRemoteExtends(entry)
+	if opts.Resolve == nil {
+		return "", Source{}, fmt.Errorf("extends %q: cross-repository extends are not supported here", entry)
+	}
+	clean := path.Clean(strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(rel)), "/"))
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", Source{}, fmt.Errorf("extends %q: profile path must be inside the repository", entry)
+	}
+	src, err := opts.Resolve(repo, ref)
+	if err != nil {
+		return "", Source{}, fmt.Errorf("extends %q: %w", entry, err)
+	}
+	manifest := filepath.Join(src.Dir, filepath.FromSlash(clean))
+	if ext := filepath.Ext(manifest); ext != ".yaml" && ext != ".yml" {
+		manifest = filepath.Join(manifest, "profile.yaml")
+	}
+	return manifest, src, nil
+}
+
+// addSource appends s unless a source with the same repo and commit is already present.
+func addSource(sources []Source, s Source) []Source {
+	for _, existing := range sources {
+		if existing.Repo == s.Repo && existing.Commit == s.Commit {
+			return sources
+		}
+	}
+	return append(sources, s)
+}
@@ -0,0 +1,83 @@
+package profile
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseRemoteExtends(t *testing.T) {
+	cases := []struct {
+		entry           string
+		repo, ref, path string
+		ok              bool
+	}{
+		{entry: "../base/profile.yaml"},
+		{entry: "https://example.com/gov.git@v1.2.0#Governance/Profiles/backend-go-hex", repo: "https://example.com/gov.git", ref: "v1.2.0", path: "Governance/Profiles/backend-go-hex", ok: true},
+		{entry: "git@github.com:org/gov.git@main#Governance/Profiles/x/profile.yaml", repo: "git@github.com:org/gov.git", ref: "main", path: "Governance/Profiles/x/profile.yaml", ok: true},
+		{entry: "repo-without-ref#Governance/Profiles/x"},
+		{entry: "@v1#Governance/Profiles/x"},
+	}
+	for _, c := range cases {
+		repo, ref, path, ok := ParseRemoteExtends(c.entry)
+		if ok != c.ok || repo != c.repo || ref != c.ref || path != c.path {
+			t.Fatalf("ParseRemoteExtends(%q) = %q %q %q %v", c.entry, repo, ref, path, ok)
+		}
+	}
+}
+
+func TestLoadManifestWith_ResolvesRemoteExtends(t *testing.T) {
+	tmp := t.TempDir()
+	remote := filepath.Join(tmp, "checkout")
+	mkdirAll(t, filepath.Join(remote, "Governance", "Profiles", "base"), filepath.Join(tmp, "org"))
+	writeFile(t, filepath.Join(remote, "Governance", "Profiles", "base", "profile.yaml"), "schemaVersion: 1\nid: base\ndocuments:\n  - output: A.md\n    fragments: [a.md]\n")
+	path := filepath.Join(tmp, "org", "profile.yaml")
+	writeFile(t, path, strings.TrimSpace(`
+schemaVersion: 1
+id: org
+extends: ["https://example.com/gov.git@v1#Governance/Profiles/base"]
+documents:
+  - output: A.md
+    merge: append-fragments
+    fragments: [org.md]
+`)+"\n")
+
+	var fetched []string
+	m, err := LoadManifestWith(path, LoadOptions{Resolve: func(repo, ref string) (Source, error) {
+		fetched = append(fetched, repo+"@"+ref)
+		return Source{Repo: repo, Ref: ref, Commit: "abc123", Dir: remote}, nil
+	}})
+	if err != nil {
+		t.Fatalf("LoadManifestWith: %v", err)
+	}
+	if strings.Join(fetched, ",") != "https://example.com/gov.git@v1" {
+		t.Fatalf("unexpected fetches: %v", fetched)
+	}
+	want := []string{filepath.Join(remote, "Governance", "Profiles", "base", "a.md"), filepath.Join(tmp, "org", "org.md")}
+	if len(m.Documents) != 1 || strings.Join(m.Documents[0].FragmentPaths(), ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected documents: %+v", m.Documents)
+	}
+	if strings.Join(m.Lineage, ",") != "base,org" {
+		t.Fatalf("unexpected lineage: %v", m.Lineage)
+	}
+	if len(m.Sources) != 1 || m.Sources[0].Commit != "abc123" || m.Sources[0].Repo != "https://example.com/gov.git" {
+		t.Fatalf("unexpected sources: %+v", m.Sources)
+	}
+}
+
+func TestLoadManifest_RejectsRemoteExtendsWithoutResolver(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "profile.yaml")
+	writeFile(t, path, "schemaVersion: 1\nid: org\nextends: [\"https://example.com/gov.git@v1#Governance/Profiles/base\"]\n")
+	if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), "cross-repository extends are not supported") {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+
+	_, err := LoadManifestWith(path, LoadOptions{Resolve: func(repo, ref string) (Source, error) {
+		return Source{}, errors.New("ref not found")
+	}})
+	if err == nil || !strings.Contains(err.Error(), "ref not found") {
+		t.Fatalf("expected resolver error, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package profile
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// files reads profile sources by absolute path: a path under one of the roots is served
+// from that root's fs.FS. Without roots (a plain LoadManifest) every path is read from
+// disk; with roots, a path outside all of them is an error rather than a disk read.
+type files []fileRoot
+
+type fileRoot struct {
+	dir  string
+	fsys fs.FS
+}
+
+// errOutsideSource reports a path that none of a load's filesystems serve.
+var errOutsideSource = errors.New("outside the profile source")
+
+// with returns f plus dir served from fsys (from disk when fsys is nil).
+func (f files) with(dir string, fsys fs.FS) files {
+	if dir == "" {
+		return f
+	}
+	if fsys == nil {
+		fsys = os.DirFS(dir)
+	}
+	// Copy so that sibling extends never share an appended root.
+	return append(append(files{}, f...), fileRoot{dir: dir, fsys: fsys})
+}
+
+// locate returns the filesystem serving name and name's path within it. The most
+// recently added root wins, so a base cached below the checkout uses its own FS.
+func (f files) locate(name string) (fs.FS, string, error) {
+	for i := len(f) - 1; i >= 0; i-- {
+		rel, err := filepath.Rel(f[i].dir, name)
+		if err == nil && filepath.IsLocal(rel) {
+			return f[i].fsys, filepath.ToSlash(rel), nil
+		}
+	}
+	if len(f) > 0 {
+		return nil, "", &fs.PathError{Op: "open", Path: name, Err: errOutsideSource}
+	}
+	dir := filepath.Dir(name)
+	if dir == name {
+		return os.DirFS(name), ".", nil
+	}
+	return os.DirFS(dir), filepath.Base(name), nil
+}
+
+func (f files) ReadFile(name string) ([]byte, error) {
+	fsys, rel, err := f.locate(name)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(fsys, rel)
+}
+
+func (f files) Stat(name string) (fs.FileInfo, error) {
+	fsys, rel, err := f.locate(name)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Stat(fsys, rel)
+}
+
+func (f files) isDir(name string) bool {
+	info, err := f.Stat(name)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,160 @@
+package profile
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/pathglob"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SplitFrontmatter separates a leading `---` YAML frontmatter block from text.
+// ok is false (and body is text) when there is no frontmatter.
+func SplitFrontmatter(text string) (front, body string, ok bool) {
+	normalized := strings.ReplaceAll(text, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return "", text, false
+	}
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---\n")
+	if end < 0 {
+		if strings.HasSuffix(rest, "\n---") {
+			return rest[:len(rest)-len("\n---")], "", true
+		}
+		return "", text, false
+	}
+	return rest[:end], rest[end+len("\n---\n"):], true
+}
+
+// expandFragments replaces glob patterns and directories with the files they match.
+// Matches are ordered by their `order:` frontmatter key (default 0), then lexically by path.
+// Expanded fragments keep the pattern's condition and record it in From.
+func expandFragments(src files, docs []DocumentSpec) error {
+	for di := range docs {
+		var err error
+		if docs[di].Fragments, err = expandList(src, docs[di].Output, docs[di].Fragments); err != nil {
+			return err
+		}
+		for si := range docs[di].Sections {
+			if docs[di].Sections[si].Fragments, err = expandList(src, docs[di].Output, docs[di].Sections[si].Fragments); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func expandList(src files, output string, fragments []Fragment) ([]Fragment, error) {
+	var out []Fragment
+	for _, f := range fragments {
+		if f.From != "" || strings.TrimSpace(f.Path) == "" {
+			out = append(out, f)
+			continue
+		}
+		var matches []string
+		var err error
+		switch {
+		case pathglob.HasMeta(f.Path):
+			matches, err = globFiles(src, f.Path)
+		case src.isDir(f.Path):
+			matches, err = globFiles(src, filepath.Join(f.Path, "**", "*.md"))
+		default:
+			out = append(out, f)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %q fragment %s: %w", output, f.Path, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("document %q fragment %s: matched no files", output, f.Path)
+		}
+		ordered, err := orderFragments(src, matches)
+		if err != nil {
+			return nil, fmt.Errorf("document %q: %w", output, err)
+		}
+		for _, p := range ordered {
+			out = append(out, Fragment{Path: p, When: f.When, From: f.Path})
+		}
+	}
+	return out, nil
+}
+
+// globFiles walks the static prefix of an absolute pattern and returns matching regular files.
+func globFiles(src files, pattern string) ([]string, error) {
+	pattern = filepath.ToSlash(pattern)
+	segments := strings.Split(pattern, "/")
+	static := 0
+	for static < len(segments) && !pathglob.HasMeta(segments[static]) && segments[static] != "**" {
+		static++
+	}
+	root := strings.Join(segments[:static], "/")
+	if root == "" {
+		root = "/"
+	}
+	rel := strings.Join(segments[static:], "/")
+	rootDir := filepath.FromSlash(root)
+	if !src.isDir(rootDir) {
+		return nil, nil
+	}
+	fsys, name, err := src.locate(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	err = fs.WalkDir(fsys, name, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != name && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		r := strings.TrimPrefix(p, name+"/")
+		if name == "." {
+			r = p
+		}
+		if pathglob.Match(rel, r) {
+			matches = append(matches, filepath.Join(rootDir, filepath.FromSlash(r)))
+		}
+		return nil
+	})
+	return matches, err
+}
+
+func orderFragments(src files, paths []string) ([]string, error) {
+	orders := map[string]int{}
+	for _, p := range paths {
+		b, err := src.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		front, _, ok := SplitFrontmatter(string(b))
+		if !ok {
+			continue
+		}
+		var meta struct {
+			Order int `yaml:"order"`
+		}
+		if err := yaml.Unmarshal([]byte(front), &meta); err != nil {
+			return nil, fmt.Errorf("%s: frontmatter: %w", p, err)
+		}
+		orders[p] = meta.Order
+	}
+	sorted := append([]string{}, paths...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if orders[sorted[i]] != orders[sorted[j]] {
+			return orders[sorted[i]] < orders[sorted[j]]
+		}
+		return sorted[i] < sorted[j]
+	})
+	return sorted, nil
+}
@@ -0,0 +1,140 @@
+package profile
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitFrontmatter(t *testing.T) {
+	cases := []struct {
+		in, front, body string
+		ok              bool
+	}{
+		{"---\norder: 2\n---\nBody\n", "order: 2", "Body\n", true},
+		{"---\r\norder: 2\r\n---\r\nBody\r\n", "order: 2", "Body\n", true},
+		{"---\norder: 2\n---", "order: 2", "", true},
+		{"Body\n---\n", "", "Body\n---\n", false},
+		{"---\nunterminated\n", "", "---\nunterminated\n", false},
+	}
+	for _, tc := range cases {
+		front, body, ok := SplitFrontmatter(tc.in)
+		if front != tc.front || body != tc.body || ok != tc.ok {
+			t.Fatalf("%q: got (%q, %q, %v)", tc.in, front, body, ok)
+		}
+	}
+}
+
+func TestLoadManifest_ExpandsGlobsAndDirectoriesDeterministically(t *testing.T) {
+	tmp := t.TempDir()
+	core := filepath.Join(tmp, "Core")
+	profileDir := filepath.Join(tmp, "Profiles", "p")
+	mkdirAll(t, filepath.Join(core, "Rules", "nested"), filepath.Join(core, "Rules", ".hidden"), profileDir)
+	writeFile(t, filepath.Join(core, "Rules", "b.md"), "B\n")
+	writeFile(t, filepath.Join(core, "Rules", "a.md"), "A\n")
+	writeFile(t, filepath.Join(core, "Rules", "first.md"), "---\norder: -1\n---\nFIRST\n")
+	writeFile(t, filepath.Join(core, "Rules", "notes.txt"), "ignored\n")
+	writeFile(t, filepath.Join(core, "Rules", "nested", "c.md"), "C\n")
+	writeFile(t, filepath.Join(core, "Rules", ".hidden", "h.md"), "H\n")
+	path := filepath.Join(profileDir, "profile.yaml")
+	writeFile(t, path, strings.TrimSpace(`
+schemaVersion: 1
+id: p
+documents:
+  - output: Rules.md
+    fragments:
+      - path: ../../Core/Rules/*.md
+        when: go
+  - output: AllRules.md
+    fragments:
+      - ../../Core/Rules
+`)+"\n")
+
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	rel := func(d DocumentSpec) string {
+		var out []string
+		for _, f := range d.Fragments {
+			r, _ := filepath.Rel(core, f.Path)
+			out = append(out, filepath.ToSlash(r))
+		}
+		return strings.Join(out, ",")
+	}
+	if got := rel(m.Documents[0]); got != "Rules/first.md,Rules/a.md,Rules/b.md" {
+		t.Fatalf("unexpected glob expansion: %s", got)
+	}
+	if f := m.Documents[0].Fragments[1]; f.When != "go" || f.From != filepath.Join(core, "Rules", "*.md") {
+		t.Fatalf("expected condition and provenance on expanded fragment, got %+v", f)
+	}
+	if got := rel(m.Documents[1]); got != "Rules/first.md,Rules/a.md,Rules/b.md,Rules/nested/c.md" {
+		t.Fatalf("unexpected directory expansion: %s", got)
+	}
+
+	writeFile(t, path, "schemaVersion: 1\nid: p\ndocuments:\n  - output: A.md\n    fragments: [../../Core/Missing/*.md]\n")
+	if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), "matched no files") {
+		t.Fatalf("expected no-match error, got %v", err)
+	}
+	mkdirAll(t, filepath.Join(core, "Bad"))
+	writeFile(t, filepath.Join(core, "Bad", "bad.md"), "---\norder: [x\n---\n")
+	writeFile(t, path, "schemaVersion: 1\nid: p\ndocuments:\n  - output: A.md\n    fragments: [../../Core/Bad/*.md]\n")
+	if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), "frontmatter") {
+		t.Fatalf("expected frontmatter error, got %v", err)
+	}
+}
+
+func TestLoadManifestWith_ReadsSourcesThroughFS(t *testing.T) {
+	// The checkout only exists in memory.
+	root := filepath.Join(t.TempDir(), "checkout")
+	files := fstest.MapFS{
+		"Profiles/base/profile.yaml": {Data: []byte("schemaVersion: 1\nid: base\ndocuments:\n  - output: Base.md\n    fragments: [./Base.md]\n")},
+		"Profiles/p/profile.yaml": {Data: []byte(strings.TrimSpace(`
+schemaVersion: 1
+id: p
+extends: [../base/profile.yaml]
+documents:
+  - output: Rules.md
+    fragments:
+      - ../../Core/Rules
+  - output: Go.md
+    fragments:
+      - ../../Core/Rules/*.md
+`) + "\n")},
+		"Core/Rules/b.md":        {Data: []byte("B\n")},
+		"Core/Rules/first.md":    {Data: []byte("---\norder: -1\n---\nFIRST\n")},
+		"Core/Rules/nested/c.md": {Data: []byte("C\n")},
+	}
+	m, err := LoadManifestWith(filepath.Join(root, "Profiles", "p", "profile.yaml"), LoadOptions{Root: root, FS: files})
+	if err != nil {
+		t.Fatalf("LoadManifestWith: %v", err)
+	}
+	rel := func(d DocumentSpec) string {
+		var out []string
+		for _, f := range d.Fragments {
+			r, _ := filepath.Rel(root, f.Path)
+			out = append(out, filepath.ToSlash(r))
+		}
+		return strings.Join(out, ",")
+	}
+	got := []string{}
+	for _, d := range m.Documents {
+		got = append(got, d.Output+"="+rel(d))
+	}
+	want := "Base.md=Profiles/base/Base.md;Rules.md=Core/Rules/first.md,Core/Rules/b.md,Core/Rules/nested/c.md;Go.md=Core/Rules/first.md,Core/Rules/b.md"
+	if strings.Join(got, ";") != want {
+		t.Fatalf("unexpected documents:\n%s", strings.Join(got, ";"))
+	}
+	if _, err := LoadManifestWith(filepath.Join(root, "Profiles", "missing", "profile.yaml"), LoadOptions{Root: root, FS: files}); err == nil {
+		t.Fatalf("expected missing manifest error")
+	}
+
+	// A path escaping the checkout is never read from the host disk, even if it exists there.
+	writeFile(t, filepath.Join(filepath.Dir(root), "host.yaml"), "schemaVersion: 1\nid: host\n")
+	files["Profiles/escape/profile.yaml"] = &fstest.MapFile{Data: []byte("schemaVersion: 1\nid: escape\nextends: [../../../host.yaml]\n")}
+	_, err = LoadManifestWith(filepath.Join(root, "Profiles", "escape", "profile.yaml"), LoadOptions{Root: root, FS: files})
+	if err == nil || !strings.Contains(err.Error(), "outside the profile source") {
+		t.Fatalf("expected outside-source error, got %v", err)
+	}
+}
@@ -2,9 +2,15 @@ package profile
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
 	"strings"
+	"time"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/conditions"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/managedblocks"
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/render"
 
 	"gopkg.in/yaml.v3"
 )
@@ -18,21 +24,214 @@ type Manifest struct {
 	Documents []DocumentSpec `yaml:"documents"`
 	Templates []FileSpec     `yaml:"templates"`
 	Playbooks []FileSpec     `yaml:"playbooks"`
+
+	Coverage CoverageSpec `yaml:"coverage"`
+	Gates    []GateSpec   `yaml:"gates"`
+
+	// Variables are default values for fragment placeholders (e.g. `{{ .Project.Name }}`).
+	// Config `variables` override them.
+	Variables map[string]any `yaml:"variables"`
+
+	// Parameters declare typed inputs supplied via config `parameters` (exposed to fragments as `.Params`).
+	Parameters map[string]ParameterSpec `yaml:"parameters"`
+
+	// Lineage lists the IDs of every resolved manifest, bases first, ending with ID (set by LoadManifest).
+	Lineage []string `yaml:"-"`
+
+	// Sources lists the repositories fetched to resolve cross-repository extends (set by LoadManifestWith).
+	Sources []Source `yaml:"-"`
 }
 
 type DocumentSpec struct {
-	Output    string   `yaml:"output"`
-	Fragments []string `yaml:"fragments"`
+	Output    string     `yaml:"output"`
+	Fragments []Fragment `yaml:"fragments"`
+	// Sections split the document into several managed blocks, each with its own fragments,
+	// so project-owned text can sit between them. A document declares fragments or sections.
+	Sections []SectionSpec `yaml:"sections"`
+	// Merge controls how this spec combines with a base profile's spec for the same output.
+	Merge string `yaml:"merge"`
+	// Comment selects the marker comment syntax (html, hash, slash or block); empty selects
+	// it from the output's file extension.
+	Comment string `yaml:"comment"`
+	// Placement is where a block is added to an existing non-Markdown file: end (default)
+	// or top, for license and source headers.
+	Placement string `yaml:"placement"`
+	// Origin is the manifest path that declared this output (set by LoadManifest).
+	Origin string `yaml:"-"`
+}
+
+// CommentSyntax returns the marker comment syntax for the document.
+func (d DocumentSpec) CommentSyntax() managedblocks.Syntax {
+	if s, ok := managedblocks.SyntaxByName(d.Comment); ok {
+		return s
+	}
+	return managedblocks.SyntaxForPath(d.Output)
+}
+
+// BlockPlacement returns where new blocks go in an existing non-Markdown output.
+func (d DocumentSpec) BlockPlacement() managedblocks.Placement {
+	if d.Placement == "" {
+		return managedblocks.PlaceEnd
+	}
+	return managedblocks.Placement(d.Placement)
+}
+
+// SectionSpec is a named managed block of a document.
+type SectionSpec struct {
+	Name      string     `yaml:"name"`
+	Fragments []Fragment `yaml:"fragments"`
+}
+
+// Blocks returns the document's managed blocks in order: its sections, or a single
+// unnamed section holding Fragments.
+func (d DocumentSpec) Blocks() []SectionSpec {
+	if len(d.Sections) > 0 {
+		return d.Sections
+	}
+	return []SectionSpec{{Fragments: d.Fragments}}
+}
+
+// FragmentPaths returns the paths of all fragments (of every section), regardless of conditions.
+func (d DocumentSpec) FragmentPaths() []string {
+	var paths []string
+	for _, b := range d.Blocks() {
+		for _, f := range b.Fragments {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// Fragment is a document fragment. In YAML it is either a plain path or a mapping
+// with `path` and an optional `when` condition.
+type Fragment struct {
+	Path string `yaml:"path"`
+	// When is a condition over repo facts (see package conditions); empty means always.
+	When string `yaml:"when"`
+	// From is the glob or directory this fragment was expanded from (set by LoadManifest).
+	From string `yaml:"-"`
+}
+
+func (f *Fragment) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		*f = Fragment{Path: n.Value}
+		return nil
+	}
+	var p struct {
+		Path string `yaml:"path"`
+		When string `yaml:"when"`
+	}
+	if err := n.Decode(&p); err != nil {
+		return err
+	}
+	*f = Fragment{Path: p.Path, When: p.When}
+	return nil
 }
 
 type FileSpec struct {
 	Source string `yaml:"source"`
 	Output string `yaml:"output"`
+	// When is a condition over repo facts; the file is only emitted when it holds.
+	When string `yaml:"when"`
+	// Merge controls how this spec combines with a base profile's spec for the same output.
+	Merge string `yaml:"merge"`
+	// Origin is the manifest path that declared this output (set by LoadManifest).
+	Origin string `yaml:"-"`
+}
+
+// Merge rules for specs in a profile that extends another. An empty rule adds a new output.
+const (
+	// MergeReplace replaces the base spec's fragments (documents) or source (templates/playbooks).
+	MergeReplace = "replace"
+	// MergeAppendFragments adds fragments after the base document's fragments.
+	MergeAppendFragments = "append-fragments"
+	// MergePrependFragments adds fragments before the base document's fragments.
+	MergePrependFragments = "prepend-fragments"
+	// MergeRemove drops the base spec so the output is not emitted.
+	MergeRemove = "remove"
+)
+
+// CoverageSpec declares the coverage quality gate enforced by `agent-gov gate coverage`.
+type CoverageSpec struct {
+	// Threshold is the minimum total coverage percentage (0 means not declared).
+	Threshold float64 `yaml:"threshold"`
+	// Exclude are globs for code excluded from coverage (e.g. generated openapi code).
+	Exclude []string `yaml:"exclude"`
+}
+
+// GateSpec declares a named quality gate run by `agent-gov gates run`.
+type GateSpec struct {
+	Name string `yaml:"name"`
+	// Command is the argv to execute from the repo root (no shell).
+	Command []string `yaml:"command"`
+	// Timeout is a Go duration (e.g. "10m"); empty means no timeout.
+	Timeout string `yaml:"timeout"`
+	// Optional gates are reported but do not fail the run.
+	Optional bool `yaml:"optional"`
+}
+
+// TimeoutDuration returns the parsed timeout (0 when unset or invalid).
+func (g GateSpec) TimeoutDuration() time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(g.Timeout))
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+func validateGates(gates []GateSpec) error {
+	seen := map[string]bool{}
+	for i, g := range gates {
+		name := strings.TrimSpace(g.Name)
+		if name == "" {
+			return fmt.Errorf("gates[%d]: name is required", i)
+		}
+		if seen[name] {
+			return fmt.Errorf("gates[%d]: duplicate gate name %q", i, name)
+		}
+		seen[name] = true
+		if len(g.Command) == 0 || strings.TrimSpace(g.Command[0]) == "" {
+			return fmt.Errorf("gate %q: command is required", name)
+		}
+		if strings.TrimSpace(g.Timeout) != "" {
+			d, err := time.ParseDuration(strings.TrimSpace(g.Timeout))
+			if err != nil || d <= 0 {
+				return fmt.Errorf("gate %q: invalid timeout %q", name, g.Timeout)
+			}
+		}
+	}
+	return nil
 }
 
 func LoadManifest(path string) (Manifest, error) {
+	return loadManifest(path, nil, LoadOptions{})
+}
+
+// LoadManifestWith is LoadManifest with cross-repository extends (`repo@ref#path`) resolved via opts.
+func LoadManifestWith(path string, opts LoadOptions) (Manifest, error) {
+	if opts.FS != nil {
+		if root, err := filepath.Abs(opts.Root); err == nil {
+			opts.files = opts.files.with(root, opts.FS)
+		}
+	}
+	return loadManifest(path, nil, opts)
+}
+
+// loadManifest loads path; chain holds the manifests currently being resolved so extends cycles are reported.
+func loadManifest(path string, chain []string, opts LoadOptions) (Manifest, error) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	for i, p := range chain {
+		if p == path {
+			cycle := append(append([]string{}, chain[i:]...), path)
+			return Manifest{}, fmt.Errorf("profile extends cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	chain = append(chain, path)
+
 	baseDir := filepath.Dir(path)
-	raw, err := os.ReadFile(path)
+	raw, err := opts.files.ReadFile(path)
 	if err != nil {
 		return Manifest{}, err
 	}
@@ -46,51 +245,415 @@ func LoadManifest(path string) (Manifest, error) {
 	if strings.TrimSpace(m.ID) == "" {
 		return Manifest{}, fmt.Errorf("profile id is required: %s", path)
 	}
+	if m.Coverage.Threshold < 0 || m.Coverage.Threshold > 100 {
+		return Manifest{}, fmt.Errorf("profile coverage.threshold must be between 0 and 100: %s", path)
+	}
+	if err := validateGates(m.Gates); err != nil {
+		return Manifest{}, fmt.Errorf("profile %s: %w", path, err)
+	}
+	if err := validateMergeRules(m); err != nil {
+		return Manifest{}, fmt.Errorf("profile %s: %w", path, err)
+	}
+	if err := validateConditions(m); err != nil {
+		return Manifest{}, fmt.Errorf("profile %s: %w", path, err)
+	}
+	for _, d := range m.Documents {
+		if _, ok := managedblocks.SyntaxByName(d.Comment); d.Comment != "" && !ok {
+			return Manifest{}, fmt.Errorf("profile %s: document %q: unknown comment syntax %q (want html, hash, slash, or block)", path, d.Output, d.Comment)
+		}
+		if p := managedblocks.Placement(d.Placement); p != "" && p != managedblocks.PlaceEnd && p != managedblocks.PlaceTop {
+			return Manifest{}, fmt.Errorf("profile %s: document %q: unknown placement %q (want end or top)", path, d.Output, d.Placement)
+		}
+		if err := validateSections(d); err != nil {
+			return Manifest{}, fmt.Errorf("profile %s: %w", path, err)
+		}
+	}
+	if err := validateParameters(m.Parameters); err != nil {
+		return Manifest{}, fmt.Errorf("profile %s: %w", path, err)
+	}
+	for i := range m.Documents {
+		m.Documents[i].Origin = path
+	}
+	for i := range m.Templates {
+		m.Templates[i].Origin = path
+	}
+	for i := range m.Playbooks {
+		m.Playbooks[i].Origin = path
+	}
 
-	// Merge any base manifests first.
-	for _, ext := range m.Extends {
-		extPath := ext
-		if !filepath.IsAbs(extPath) {
-			extPath = filepath.Clean(filepath.Join(baseDir, extPath))
+	lineage := []string{}
+	var sources []Source
+	// Combine base manifests in order, then apply this manifest (and its merge rules) on top.
+	if len(m.Extends) > 0 {
+		var base Manifest
+		for i, ext := range m.Extends {
+			extPath := ext
+			var remote *Source
+			if _, _, _, ok := ParseRemoteExtends(ext); ok {
+				p, src, err := resolveRemoteExtends(ext, opts)
+				if err != nil {
+					return Manifest{}, fmt.Errorf("profile %s: %w", path, err)
+				}
+				extPath, remote = p, &src
+			} else if !filepath.IsAbs(extPath) {
+				extPath = filepath.Clean(filepath.Join(baseDir, extPath))
+			}
+			nextOpts := opts
+			if remote != nil {
+				nextOpts.files = opts.files.with(remote.Dir, remote.FS)
+			}
+			next, err := loadManifest(extPath, chain, nextOpts)
+			if err != nil {
+				return Manifest{}, err
+			}
+			for _, id := range next.Lineage {
+				if !containsString(lineage, id) {
+					lineage = append(lineage, id)
+				}
+			}
+			if remote != nil {
+				sources = addSource(sources, *remote)
+			}
+			for _, s := range next.Sources {
+				sources = addSource(sources, s)
+			}
+			if i == 0 {
+				base = next
+				continue
+			}
+			if base, err = merge(base, dropShared(base, next)); err != nil {
+				return Manifest{}, fmt.Errorf("profile %s: %w", path, err)
+			}
 		}
-		base, err := LoadManifest(extPath)
-		if err != nil {
-			return Manifest{}, err
+		if m, err = merge(base, m); err != nil {
+			return Manifest{}, fmt.Errorf("profile %s: %w", path, err)
 		}
-		m = merge(base, m)
 	}
+	if err := checkDuplicateOutputs(m); err != nil {
+		return Manifest{}, fmt.Errorf("profile %s: %w", path, err)
+	}
+	m.Lineage = append(lineage, m.ID)
+	m.Sources = sources
 	m = normalizePaths(m, baseDir)
+	if err := expandFragments(opts.files, m.Documents); err != nil {
+		return Manifest{}, fmt.Errorf("profile %s: %w", path, err)
+	}
 	return m, nil
 }
 
-func merge(base, overlay Manifest) Manifest {
+// dropShared removes from next the specs it shares with base: in diamond inheritance (A
+// extends B and C, both extending D) D's specs reach A through both bases. Only specs from
+// the same manifest that are still identical are dropped; a common base's output that one
+// side changed remains a duplicate.
+func dropShared(base, next Manifest) Manifest {
+	next.Documents = dropSharedSpecs(base.Documents, next.Documents, func(d DocumentSpec) (string, string) { return d.Origin, d.Output })
+	next.Templates = dropSharedSpecs(base.Templates, next.Templates, func(f FileSpec) (string, string) { return f.Origin, f.Output })
+	next.Playbooks = dropSharedSpecs(base.Playbooks, next.Playbooks, func(f FileSpec) (string, string) { return f.Origin, f.Output })
+	return next
+}
+
+func dropSharedSpecs[T any](base, next []T, key func(T) (origin, output string)) []T {
+	var out []T
+	for _, n := range next {
+		origin, output := key(n)
+		shared := false
+		for _, b := range base {
+			if bo, bout := key(b); bo == origin && bout == output && reflect.DeepEqual(b, n) {
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// checkDuplicateOutputs rejects two specs (of any kind) emitting the same output path.
+func checkDuplicateOutputs(m Manifest) error {
+	type decl struct{ kind, origin string }
+	seen := map[string]decl{}
+	check := func(kind, output, origin string) error {
+		key := filepath.ToSlash(filepath.Clean(output))
+		if prev, ok := seen[key]; ok {
+			if prev.origin == origin {
+				return fmt.Errorf("duplicate output %q (%s and %s in %s)", output, prev.kind, kind, origin)
+			}
+			return fmt.Errorf("duplicate output %q: %s in %s and %s in %s (use a merge rule to override a base output)", output, prev.kind, prev.origin, kind, origin)
+		}
+		seen[key] = decl{kind: kind, origin: origin}
+		return nil
+	}
+	for _, d := range m.Documents {
+		if err := check("document", d.Output, d.Origin); err != nil {
+			return err
+		}
+	}
+	for _, f := range m.Templates {
+		if err := check("template", f.Output, f.Origin); err != nil {
+			return err
+		}
+	}
+	for _, f := range m.Playbooks {
+		if err := check("playbook", f.Output, f.Origin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var sectionNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+func validateSections(d DocumentSpec) error {
+	if len(d.Sections) == 0 {
+		return nil
+	}
+	if len(d.Fragments) > 0 {
+		return fmt.Errorf("document %q: declare fragments or sections, not both", d.Output)
+	}
+	seen := map[string]bool{}
+	for i, sec := range d.Sections {
+		if !sectionNamePattern.MatchString(sec.Name) {
+			return fmt.Errorf("document %q: sections[%d]: name %q must be lowercase letters, digits and dashes", d.Output, i, sec.Name)
+		}
+		if seen[sec.Name] {
+			return fmt.Errorf("document %q: duplicate section %q", d.Output, sec.Name)
+		}
+		seen[sec.Name] = true
+	}
+	return nil
+}
+
+func validateConditions(m Manifest) error {
+	for _, d := range m.Documents {
+		for _, b := range d.Blocks() {
+			for _, f := range b.Fragments {
+				if err := conditions.Validate(f.When); err != nil {
+					return fmt.Errorf("document %q fragment %s: %w", d.Output, f.Path, err)
+				}
+			}
+		}
+	}
+	for _, f := range append(append([]FileSpec{}, m.Templates...), m.Playbooks...) {
+		if err := conditions.Validate(f.When); err != nil {
+			return fmt.Errorf("%s: %w", f.Output, err)
+		}
+	}
+	return nil
+}
+
+func validateMergeRules(m Manifest) error {
+	hasRule := false
+	for _, d := range m.Documents {
+		switch d.Merge {
+		case "":
+		case MergeReplace, MergeAppendFragments, MergePrependFragments, MergeRemove:
+			hasRule = true
+		default:
+			return fmt.Errorf("document %q: unknown merge rule %q", d.Output, d.Merge)
+		}
+	}
+	for _, kind := range []struct {
+		name  string
+		specs []FileSpec
+	}{{"template", m.Templates}, {"playbook", m.Playbooks}} {
+		for _, f := range kind.specs {
+			switch f.Merge {
+			case "":
+			case MergeReplace, MergeRemove:
+				hasRule = true
+			case MergeAppendFragments, MergePrependFragments:
+				return fmt.Errorf("%s %q: merge rule %q only applies to documents", kind.name, f.Output, f.Merge)
+			default:
+				return fmt.Errorf("%s %q: unknown merge rule %q", kind.name, f.Output, f.Merge)
+			}
+		}
+	}
+	if hasRule && len(m.Extends) == 0 {
+		return fmt.Errorf("merge rules require extends")
+	}
+	return nil
+}
+
+func merge(base, overlay Manifest) (Manifest, error) {
 	out := base
 
 	// Overlay identity fields.
 	out.SchemaVersion = overlay.SchemaVersion
 	out.ID = overlay.ID
+	out.Extends = overlay.Extends
 	if strings.TrimSpace(overlay.Description) != "" {
 		out.Description = overlay.Description
 	}
 
-	// Append specs.
-	out.Documents = append(out.Documents, overlay.Documents...)
-	out.Templates = append(out.Templates, overlay.Templates...)
-	out.Playbooks = append(out.Playbooks, overlay.Playbooks...)
+	// Specs without a merge rule are appended; rules are keyed by output.
+	var err error
+	if out.Documents, err = mergeDocuments(base.Documents, overlay.Documents); err != nil {
+		return Manifest{}, err
+	}
+	if out.Templates, err = mergeFiles("template", base.Templates, overlay.Templates); err != nil {
+		return Manifest{}, err
+	}
+	if out.Playbooks, err = mergeFiles("playbook", base.Playbooks, overlay.Playbooks); err != nil {
+		return Manifest{}, err
+	}
+
+	if overlay.Coverage.Threshold != 0 {
+		out.Coverage.Threshold = overlay.Coverage.Threshold
+	}
+	out.Coverage.Exclude = append(out.Coverage.Exclude, overlay.Coverage.Exclude...)
+
+	out.Variables = render.Merge(base.Variables, overlay.Variables)
+	// Parameters are keyed by name: an overlay spec replaces the base spec.
+	if len(base.Parameters)+len(overlay.Parameters) > 0 {
+		out.Parameters = map[string]ParameterSpec{}
+		for name, p := range base.Parameters {
+			out.Parameters[name] = p
+		}
+		for name, p := range overlay.Parameters {
+			out.Parameters[name] = p
+		}
+	}
+
+	// Gates are keyed by name: an overlay gate replaces the base gate of the same name.
+	out.Gates = append([]GateSpec{}, base.Gates...)
+	for _, g := range overlay.Gates {
+		replaced := false
+		for i := range out.Gates {
+			if out.Gates[i].Name == g.Name {
+				out.Gates[i] = g
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out.Gates = append(out.Gates, g)
+		}
+	}
+
+	return out, nil
+}
 
+func mergeDocuments(base, overlay []DocumentSpec) ([]DocumentSpec, error) {
+	out := append([]DocumentSpec{}, base...)
+	for _, d := range overlay {
+		rule := d.Merge
+		d.Merge = ""
+		if rule == "" {
+			out = append(out, d)
+			continue
+		}
+		i := -1
+		for j := range out {
+			if out[j].Output == d.Output {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("document %q: merge %q but no base profile declares this output", d.Output, rule)
+		}
+		switch rule {
+		case MergeReplace:
+			out[i].Fragments = d.Fragments
+			out[i].Sections = d.Sections
+			if d.Comment != "" {
+				out[i].Comment = d.Comment
+			}
+			if d.Placement != "" {
+				out[i].Placement = d.Placement
+			}
+		case MergeAppendFragments, MergePrependFragments:
+			if (len(d.Sections) > 0) != (len(out[i].Sections) > 0) {
+				return nil, fmt.Errorf("document %q: merge %q must use sections exactly when the base document does", d.Output, rule)
+			}
+			prepend := rule == MergePrependFragments
+			out[i].Fragments = combineFragments(out[i].Fragments, d.Fragments, prepend)
+			out[i].Sections = mergeSections(out[i].Sections, d.Sections, prepend)
+		case MergeRemove:
+			out = append(out[:i:i], out[i+1:]...)
+		}
+	}
+	return out, nil
+}
+
+// mergeSections combines overlay sections into base sections of the same name; new
+// sections are added after the base sections.
+func mergeSections(base, overlay []SectionSpec, prepend bool) []SectionSpec {
+	if len(overlay) == 0 {
+		return base
+	}
+	out := append([]SectionSpec{}, base...)
+	for _, sec := range overlay {
+		merged := false
+		for j := range out {
+			if out[j].Name == sec.Name {
+				out[j].Fragments = combineFragments(out[j].Fragments, sec.Fragments, prepend)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			out = append(out, sec)
+		}
+	}
 	return out
 }
 
+func combineFragments(base, overlay []Fragment, prepend bool) []Fragment {
+	if prepend {
+		return append(append([]Fragment{}, overlay...), base...)
+	}
+	return append(append([]Fragment{}, base...), overlay...)
+}
+
+func mergeFiles(kind string, base, overlay []FileSpec) ([]FileSpec, error) {
+	out := append([]FileSpec{}, base...)
+	for _, f := range overlay {
+		rule := f.Merge
+		f.Merge = ""
+		if rule == "" {
+			out = append(out, f)
+			continue
+		}
+		i := -1
+		for j := range out {
+			if out[j].Output == f.Output {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("%s %q: merge %q but no base profile declares this output", kind, f.Output, rule)
+		}
+		switch rule {
+		case MergeReplace:
+			out[i].Source = f.Source
+			out[i].When = f.When
+		case MergeRemove:
+			out = append(out[:i:i], out[i+1:]...)
+		}
+	}
+	return out, nil
+}
+
 func normalizePaths(m Manifest, baseDir string) Manifest {
-	for di := range m.Documents {
-		for fi, frag := range m.Documents[di].Fragments {
-			if strings.TrimSpace(frag) == "" {
+	normalize := func(frags []Fragment) {
+		for fi, frag := range frags {
+			if strings.TrimSpace(frag.Path) == "" {
 				continue
 			}
-			if filepath.IsAbs(frag) {
+			if filepath.IsAbs(frag.Path) {
 				continue
 			}
-			m.Documents[di].Fragments[fi] = filepath.Clean(filepath.Join(baseDir, frag))
+			frags[fi].Path = filepath.Clean(filepath.Join(baseDir, frag.Path))
+		}
+	}
+	for di := range m.Documents {
+		normalize(m.Documents[di].Fragments)
+		for si := range m.Documents[di].Sections {
+			normalize(m.Documents[di].Sections[si].Fragments)
 		}
 	}
 	for i := range m.Templates {
@@ -111,3 +674,12 @@ func normalizePaths(m Manifest, baseDir string) Manifest {
 	}
 	return m
 }
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -61,7 +61,7 @@ playbooks:
 	if len(m.Documents[0].Fragments) != 2 {
 		t.Fatalf("expected 2 fragments, got %d", len(m.Documents[0].Fragments))
 	}
-	for _, p := range m.Documents[0].Fragments {
+	for _, p := range m.Documents[0].FragmentPaths() {
 		if !filepath.IsAbs(p) {
 			t.Fatalf("expected absolute fragment path, got %q", p)
 		}
@@ -112,3 +112,501 @@ func writeFile(t *testing.T, path, content string) {
 		t.Fatalf("write %s: %v", path, err)
 	}
 }
+
+func TestLoadManifest_MergesCoverageSpec(t *testing.T) {
+	tmp := t.TempDir()
+	baseDir := filepath.Join(tmp, "base")
+	childDir := filepath.Join(tmp, "child")
+	mkdirAll(t, baseDir, childDir)
+	writeFile(t, filepath.Join(baseDir, "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: base
+coverage:
+  threshold: 85
+  exclude:
+    - "**/openapi/**"
+`)+"\n")
+	childPath := filepath.Join(childDir, "profile.yaml")
+	writeFile(t, childPath, strings.TrimSpace(`
+schemaVersion: 1
+id: child
+extends:
+  - ../base/profile.yaml
+coverage:
+  exclude:
+    - "**/*.pb.go"
+`)+"\n")
+
+	m, err := LoadManifest(childPath)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if m.Coverage.Threshold != 85 {
+		t.Fatalf("expected inherited threshold 85, got %v", m.Coverage.Threshold)
+	}
+	if strings.Join(m.Coverage.Exclude, ",") != "**/openapi/**,**/*.pb.go" {
+		t.Fatalf("unexpected excludes: %v", m.Coverage.Exclude)
+	}
+}
+
+func TestLoadManifest_RejectsOutOfRangeCoverageThreshold(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "profile.yaml")
+	writeFile(t, path, "schemaVersion: 1\nid: x\ncoverage:\n  threshold: 120\n")
+	if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), "coverage.threshold") {
+		t.Fatalf("expected threshold error, got %v", err)
+	}
+}
+
+func TestLoadManifest_MergesGatesByName(t *testing.T) {
+	tmp := t.TempDir()
+	baseDir := filepath.Join(tmp, "base")
+	childDir := filepath.Join(tmp, "child")
+	mkdirAll(t, baseDir, childDir)
+	writeFile(t, filepath.Join(baseDir, "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: base
+gates:
+  - name: ci
+    command: [make, ci]
+    timeout: 10m
+  - name: lint
+    command: [make, lint]
+    optional: true
+`)+"\n")
+	childPath := filepath.Join(childDir, "profile.yaml")
+	writeFile(t, childPath, strings.TrimSpace(`
+schemaVersion: 1
+id: child
+extends:
+  - ../base/profile.yaml
+gates:
+  - name: ci
+    command: [make, ci-ios]
+  - name: ui-tests
+    command: [make, ui-test]
+`)+"\n")
+
+	m, err := LoadManifest(childPath)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	var names []string
+	for _, g := range m.Gates {
+		names = append(names, g.Name+"="+strings.Join(g.Command, " "))
+	}
+	if strings.Join(names, ",") != "ci=make ci-ios,lint=make lint,ui-tests=make ui-test" {
+		t.Fatalf("unexpected gates: %v", names)
+	}
+	if m.Gates[1].TimeoutDuration() != 0 || !m.Gates[1].Optional {
+		t.Fatalf("unexpected lint gate: %+v", m.Gates[1])
+	}
+}
+
+func TestLoadManifest_RejectsInvalidGates(t *testing.T) {
+	cases := map[string]string{
+		"name is required": "gates:\n  - command: [make]\n",
+		"duplicate":        "gates:\n  - name: a\n    command: [make]\n  - name: a\n    command: [make]\n",
+		"command is":       "gates:\n  - name: a\n",
+		"invalid timeout":  "gates:\n  - name: a\n    command: [make]\n    timeout: soon\n",
+	}
+	for want, gates := range cases {
+		path := filepath.Join(t.TempDir(), "profile.yaml")
+		writeFile(t, path, "schemaVersion: 1\nid: x\n"+gates)
+		if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
+
+func TestLoadManifest_AppliesMergeRulesByOutput(t *testing.T) {
+	tmp := t.TempDir()
+	baseDir := filepath.Join(tmp, "mobile-clean")
+	iosDir := filepath.Join(tmp, "mobile-clean-ios")
+	mkdirAll(t, baseDir, iosDir)
+	writeFile(t, filepath.Join(baseDir, "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: mobile-clean
+documents:
+  - output: Constitution.md
+    fragments: [./Constitution.Core.md]
+  - output: Architecture.md
+    fragments: [./Architecture.Profile.md]
+  - output: Notes.md
+    fragments: [./Notes.md]
+  - output: Legacy.md
+    fragments: [./Legacy.md]
+templates:
+  - source: ./UseCase.Template.md
+    output: Docs/UseCase.Template.md
+playbooks:
+  - source: ./GitLab.md
+    output: Docs/Playbooks/GitLab.md
+  - source: ./GitHub.md
+    output: Docs/Playbooks/GitHub.md
+`)+"\n")
+	iosPath := filepath.Join(iosDir, "profile.yaml")
+	writeFile(t, iosPath, strings.TrimSpace(`
+schemaVersion: 1
+id: mobile-clean-ios
+extends:
+  - ../mobile-clean/profile.yaml
+documents:
+  - output: Architecture.md
+    merge: replace
+    fragments: [./Architecture.iOS.md]
+  - output: Constitution.md
+    merge: append-fragments
+    fragments: [./Constitution.iOS.md]
+  - output: Notes.md
+    merge: prepend-fragments
+    fragments: [./Notes.iOS.md]
+  - output: Legacy.md
+    merge: remove
+  - output: iOS.md
+    fragments: [./iOS.md]
+templates:
+  - source: ./UseCase.iOS.md
+    output: Docs/UseCase.Template.md
+    merge: replace
+playbooks:
+  - output: Docs/Playbooks/GitLab.md
+    merge: remove
+`)+"\n")
+
+	m, err := LoadManifest(iosPath)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	rel := func(paths []string) string {
+		var out []string
+		for _, p := range paths {
+			r, _ := filepath.Rel(tmp, p)
+			out = append(out, filepath.ToSlash(r))
+		}
+		return strings.Join(out, ",")
+	}
+	got := map[string]string{}
+	var order []string
+	for _, d := range m.Documents {
+		order = append(order, d.Output)
+		got[d.Output] = rel(d.FragmentPaths())
+		if d.Merge != "" {
+			t.Fatalf("merge rule should be consumed, got %+v", d)
+		}
+	}
+	if strings.Join(order, ",") != "Constitution.md,Architecture.md,Notes.md,iOS.md" {
+		t.Fatalf("unexpected documents: %v", order)
+	}
+	want := map[string]string{
+		"Constitution.md": "mobile-clean/Constitution.Core.md,mobile-clean-ios/Constitution.iOS.md",
+		"Architecture.md": "mobile-clean-ios/Architecture.iOS.md",
+		"Notes.md":        "mobile-clean-ios/Notes.iOS.md,mobile-clean/Notes.md",
+		"iOS.md":          "mobile-clean-ios/iOS.md",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("%s: expected %s, got %s", k, v, got[k])
+		}
+	}
+	if len(m.Templates) != 1 || rel([]string{m.Templates[0].Source}) != "mobile-clean-ios/UseCase.iOS.md" {
+		t.Fatalf("unexpected templates: %+v", m.Templates)
+	}
+	if len(m.Playbooks) != 1 || m.Playbooks[0].Output != "Docs/Playbooks/GitHub.md" {
+		t.Fatalf("unexpected playbooks: %+v", m.Playbooks)
+	}
+}
+
+func TestLoadManifest_RejectsInvalidMergeRules(t *testing.T) {
+	tmp := t.TempDir()
+	mkdirAll(t, filepath.Join(tmp, "base"), filepath.Join(tmp, "child"))
+	writeFile(t, filepath.Join(tmp, "base", "profile.yaml"), "schemaVersion: 1\nid: base\ndocuments:\n  - output: A.md\n    fragments: [a.md]\n")
+	cases := map[string]string{
+		"unknown merge rule":        "extends: [../base/profile.yaml]\ndocuments:\n  - output: A.md\n    merge: override\n",
+		"only applies to documents": "extends: [../base/profile.yaml]\nplaybooks:\n  - output: P.md\n    merge: append-fragments\n",
+		"require extends":           "documents:\n  - output: A.md\n    merge: remove\n",
+		"no base profile declares":  "extends: [../base/profile.yaml]\ndocuments:\n  - output: B.md\n    merge: replace\n    fragments: [b.md]\n",
+	}
+	for want, body := range cases {
+		path := filepath.Join(tmp, "child", "profile.yaml")
+		writeFile(t, path, "schemaVersion: 1\nid: child\n"+body)
+		if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
+
+func TestLoadManifest_MultipleExtendsCombineBasesInOrder(t *testing.T) {
+	tmp := t.TempDir()
+	mkdirAll(t, filepath.Join(tmp, "a"), filepath.Join(tmp, "b"), filepath.Join(tmp, "c"))
+	writeFile(t, filepath.Join(tmp, "a", "profile.yaml"), "schemaVersion: 1\nid: a\ndocuments:\n  - output: A.md\n    fragments: [a.md]\n")
+	writeFile(t, filepath.Join(tmp, "b", "profile.yaml"), "schemaVersion: 1\nid: b\ndocuments:\n  - output: B.md\n    fragments: [b.md]\n")
+	path := filepath.Join(tmp, "c", "profile.yaml")
+	writeFile(t, path, strings.TrimSpace(`
+schemaVersion: 1
+id: c
+extends: [../a/profile.yaml, ../b/profile.yaml]
+documents:
+  - output: A.md
+    merge: append-fragments
+    fragments: [c.md]
+  - output: C.md
+    fragments: [c.md]
+`)+"\n")
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	var got []string
+	for _, d := range m.Documents {
+		got = append(got, d.Output+":"+strings.Join(d.FragmentPaths(), "|"))
+	}
+	want := []string{
+		"A.md:" + filepath.Join(tmp, "a", "a.md") + "|" + filepath.Join(tmp, "c", "c.md"),
+		"B.md:" + filepath.Join(tmp, "b", "b.md"),
+		"C.md:" + filepath.Join(tmp, "c", "c.md"),
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") || m.ID != "c" {
+		t.Fatalf("unexpected manifest %q: %v", m.ID, got)
+	}
+}
+
+func TestLoadManifest_ReportsExtendsCycleChain(t *testing.T) {
+	tmp := t.TempDir()
+	mkdirAll(t, filepath.Join(tmp, "a"), filepath.Join(tmp, "b"), filepath.Join(tmp, "c"))
+	writeFile(t, filepath.Join(tmp, "a", "profile.yaml"), "schemaVersion: 1\nid: a\nextends: [../b/profile.yaml]\n")
+	writeFile(t, filepath.Join(tmp, "b", "profile.yaml"), "schemaVersion: 1\nid: b\nextends: [../c/profile.yaml]\n")
+	writeFile(t, filepath.Join(tmp, "c", "profile.yaml"), "schemaVersion: 1\nid: c\nextends: [../a/profile.yaml]\n")
+
+	_, err := LoadManifest(filepath.Join(tmp, "a", "profile.yaml"))
+	if err == nil {
+		t.Fatalf("expected cycle error")
+	}
+	p := func(id string) string { return filepath.Join(tmp, id, "profile.yaml") }
+	want := "profile extends cycle: " + strings.Join([]string{p("a"), p("b"), p("c"), p("a")}, " -> ")
+	if err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestLoadManifest_RejectsDuplicateOutputsNamingSources(t *testing.T) {
+	tmp := t.TempDir()
+	mkdirAll(t, filepath.Join(tmp, "base"), filepath.Join(tmp, "child"))
+	basePath := filepath.Join(tmp, "base", "profile.yaml")
+	childPath := filepath.Join(tmp, "child", "profile.yaml")
+	writeFile(t, basePath, "schemaVersion: 1\nid: base\nplaybooks:\n  - source: p.md\n    output: Docs/P.md\n")
+
+	writeFile(t, childPath, "schemaVersion: 1\nid: child\nextends: [../base/profile.yaml]\ndocuments:\n  - output: Docs/P.md\n    fragments: [x.md]\n")
+	_, err := LoadManifest(childPath)
+	if err == nil || !strings.Contains(err.Error(), `duplicate output "Docs/P.md": document in `+childPath+` and playbook in `+basePath) {
+		t.Fatalf("expected cross-manifest duplicate error, got %v", err)
+	}
+
+	writeFile(t, childPath, "schemaVersion: 1\nid: child\ndocuments:\n  - output: A.md\n  - output: ./A.md\n")
+	_, err = LoadManifest(childPath)
+	if err == nil || !strings.Contains(err.Error(), `duplicate output "./A.md" (document and document in `+childPath+`)`) {
+		t.Fatalf("expected same-manifest duplicate error, got %v", err)
+	}
+}
+
+func TestLoadManifest_DiamondExtendsIncludesCommonBaseOnce(t *testing.T) {
+	tmp := t.TempDir()
+	mkdirAll(t, filepath.Join(tmp, "a"), filepath.Join(tmp, "b"), filepath.Join(tmp, "c"), filepath.Join(tmp, "d"))
+	writeFile(t, filepath.Join(tmp, "d", "profile.yaml"), "schemaVersion: 1\nid: d\ndocuments:\n  - output: D.md\n    fragments: [d.md]\ntemplates:\n  - source: t.md\n    output: T.md\n")
+	writeFile(t, filepath.Join(tmp, "b", "profile.yaml"), "schemaVersion: 1\nid: b\nextends: [../d/profile.yaml]\ndocuments:\n  - output: B.md\n    fragments: [b.md]\n")
+	writeFile(t, filepath.Join(tmp, "c", "profile.yaml"), "schemaVersion: 1\nid: c\nextends: [../d/profile.yaml]\n")
+	path := filepath.Join(tmp, "a", "profile.yaml")
+	writeFile(t, path, "schemaVersion: 1\nid: a\nextends: [../b/profile.yaml, ../c/profile.yaml]\n")
+
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	var got []string
+	for _, d := range m.Documents {
+		got = append(got, d.Output)
+	}
+	if strings.Join(got, ",") != "D.md,B.md" || len(m.Templates) != 1 {
+		t.Fatalf("unexpected outputs: %v templates=%v", got, m.Templates)
+	}
+	if strings.Join(m.Lineage, ",") != "d,b,c,a" {
+		t.Fatalf("unexpected lineage: %v", m.Lineage)
+	}
+
+	// A base that changed the common output conflicts with the other side's copy.
+	writeFile(t, filepath.Join(tmp, "c", "profile.yaml"), "schemaVersion: 1\nid: c\nextends: [../d/profile.yaml]\ndocuments:\n  - output: D.md\n    merge: append-fragments\n    fragments: [c.md]\n")
+	if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), `duplicate output "D.md"`) {
+		t.Fatalf("expected duplicate output error, got %v", err)
+	}
+}
+
+func TestLoadManifest_RecordsLineageAndOwnExtends(t *testing.T) {
+	tmp := t.TempDir()
+	for _, id := range []string{"root", "mid", "leaf"} {
+		mkdirAll(t, filepath.Join(tmp, id))
+	}
+	writeFile(t, filepath.Join(tmp, "root", "profile.yaml"), "schemaVersion: 1\nid: root\n")
+	writeFile(t, filepath.Join(tmp, "mid", "profile.yaml"), "schemaVersion: 1\nid: mid\nextends: [../root/profile.yaml]\n")
+	writeFile(t, filepath.Join(tmp, "leaf", "profile.yaml"), "schemaVersion: 1\nid: leaf\nextends: [../mid/profile.yaml, ../root/profile.yaml]\n")
+
+	m, err := LoadManifest(filepath.Join(tmp, "leaf", "profile.yaml"))
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if strings.Join(m.Lineage, ",") != "root,mid,leaf" {
+		t.Fatalf("unexpected lineage: %v", m.Lineage)
+	}
+	if strings.Join(m.Extends, ",") != "../mid/profile.yaml,../root/profile.yaml" {
+		t.Fatalf("expected leaf extends, got %v", m.Extends)
+	}
+}
+
+func TestLoadManifest_MergesVariableDefaults(t *testing.T) {
+	tmp := t.TempDir()
+	mkdirAll(t, filepath.Join(tmp, "base"), filepath.Join(tmp, "child"))
+	writeFile(t, filepath.Join(tmp, "base", "profile.yaml"), "schemaVersion: 1\nid: base\nvariables:\n  Project:\n    DefaultBranch: main\n    Name: base\n")
+	path := filepath.Join(tmp, "child", "profile.yaml")
+	writeFile(t, path, "schemaVersion: 1\nid: child\nextends: [../base/profile.yaml]\nvariables:\n  Project:\n    Name: child\n")
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	project, ok := m.Variables["Project"].(map[string]any)
+	if !ok || project["Name"] != "child" || project["DefaultBranch"] != "main" {
+		t.Fatalf("unexpected variables: %#v", m.Variables)
+	}
+}
+
+func TestLoadManifest_ParsesConditionalFragmentsAndFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "profile.yaml")
+	writeFile(t, path, strings.TrimSpace(`
+schemaVersion: 1
+id: p
+documents:
+  - output: Quality.md
+    fragments:
+      - ./Quality.md
+      - path: ./OpenAPI.md
+        when: openapi
+playbooks:
+  - source: ./GitLab.md
+    output: Docs/GitLab.md
+    when: gitlab
+`)+"\n")
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	frags := m.Documents[0].Fragments
+	if len(frags) != 2 || frags[0].When != "" || frags[1].Path != filepath.Join(dir, "OpenAPI.md") || frags[1].When != "openapi" {
+		t.Fatalf("unexpected fragments: %+v", frags)
+	}
+	if m.Playbooks[0].When != "gitlab" {
+		t.Fatalf("unexpected playbook: %+v", m.Playbooks[0])
+	}
+
+	writeFile(t, path, "schemaVersion: 1\nid: p\ndocuments:\n  - output: A.md\n    fragments:\n      - path: a.md\n        when: \"go &&\"\n")
+	if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), "invalid term") {
+		t.Fatalf("expected invalid condition error, got %v", err)
+	}
+	writeFile(t, path, "schemaVersion: 1\nid: p\ntemplates:\n  - source: a.md\n    output: A.md\n    when: \"a b\"\n")
+	if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), "A.md") {
+		t.Fatalf("expected invalid condition error, got %v", err)
+	}
+	writeFile(t, path, "schemaVersion: 1\nid: p\ndocuments:\n  - output: A.md\n    fragments:\n      - [a.md]\n")
+	if _, err := LoadManifest(path); err == nil {
+		t.Fatalf("expected decode error for sequence fragment")
+	}
+}
+
+func TestLoadManifest_CommentSyntaxSetting(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "profile.yaml")
+	writeFile(t, path, "schemaVersion: 1\nid: p\ndocuments:\n  - output: NOTICE\n    comment: hash\n    fragments: [n.txt]\n  - output: .golangci.yml\n    fragments: [l.yml]\n")
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if m.Documents[0].CommentSyntax().Name != "hash" || m.Documents[1].CommentSyntax().Name != "hash" {
+		t.Fatalf("unexpected syntaxes: %+v", m.Documents)
+	}
+
+	writeFile(t, path, "schemaVersion: 1\nid: p\ndocuments:\n  - output: NOTICE\n    comment: semicolon\n    fragments: [n.txt]\n")
+	if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), `document "NOTICE": unknown comment syntax "semicolon"`) {
+		t.Fatalf("expected comment syntax error, got %v", err)
+	}
+
+	writeFile(t, path, "schemaVersion: 1\nid: p\ndocuments:\n  - output: main.go\n    placement: top\n    fragments: [h.txt]\n  - output: Makefile\n    fragments: [m.mk]\n")
+	m, err = LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if m.Documents[0].BlockPlacement() != "top" || m.Documents[1].BlockPlacement() != "end" {
+		t.Fatalf("unexpected placements: %+v", m.Documents)
+	}
+	writeFile(t, path, "schemaVersion: 1\nid: p\ndocuments:\n  - output: main.go\n    placement: middle\n    fragments: [h.txt]\n")
+	if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), `document "main.go": unknown placement "middle"`) {
+		t.Fatalf("expected placement error, got %v", err)
+	}
+}
+
+func TestLoadManifest_SectionsMergeByName(t *testing.T) {
+	tmp := t.TempDir()
+	mkdirAll(t, filepath.Join(tmp, "base"), filepath.Join(tmp, "child"))
+	writeFile(t, filepath.Join(tmp, "base", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: base
+documents:
+  - output: Constitution.md
+    sections:
+      - name: quality-gates
+        fragments: [gates.md]
+      - name: agents
+        fragments: [agents.md]
+`)+"\n")
+	path := filepath.Join(tmp, "child", "profile.yaml")
+	writeFile(t, path, strings.TrimSpace(`
+schemaVersion: 1
+id: child
+extends: [../base/profile.yaml]
+documents:
+  - output: Constitution.md
+    merge: append-fragments
+    sections:
+      - name: agents
+        fragments: [agents.child.md]
+      - name: releases
+        fragments: [releases.md]
+`)+"\n")
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	var got []string
+	for _, sec := range m.Documents[0].Blocks() {
+		var frags []string
+		for _, f := range sec.Fragments {
+			frags = append(frags, filepath.Base(f.Path))
+		}
+		got = append(got, sec.Name+":"+strings.Join(frags, "|"))
+	}
+	if strings.Join(got, ",") != "quality-gates:gates.md,agents:agents.md|agents.child.md,releases:releases.md" {
+		t.Fatalf("unexpected sections: %v", got)
+	}
+}
+
+func TestLoadManifest_RejectsInvalidSections(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "profile.yaml")
+	for body, want := range map[string]string{
+		"    fragments: [a.md]\n    sections:\n      - name: a\n        fragments: [b.md]\n": "declare fragments or sections, not both",
+		"    sections:\n      - name: Agents\n        fragments: [b.md]\n":                   `name "Agents" must be lowercase`,
+		"    sections:\n      - name: a\n      - name: a\n":                                  `duplicate section "a"`,
+	} {
+		writeFile(t, path, "schemaVersion: 1\nid: p\ndocuments:\n  - output: A.md\n"+body)
+		if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q, got %v", want, err)
+		}
+	}
+}
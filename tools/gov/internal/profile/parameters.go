@@ -0,0 +1,192 @@
+package profile
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Parameter types accepted in a manifest `parameters` schema.
+const (
+	ParamString = "string"
+	ParamInt    = "int"
+	ParamBool   = "bool"
+	ParamEnum   = "enum"
+)
+
+// ParameterSpec declares a typed profile input supplied via config `parameters`.
+type ParameterSpec struct {
+	Type        string `yaml:"type"`
+	Description string `yaml:"description"`
+	// Default is used when config does not set the parameter; without one the parameter is required.
+	Default any `yaml:"default"`
+	// Min and Max bound int parameters (inclusive).
+	Min *int `yaml:"min"`
+	Max *int `yaml:"max"`
+	// Values are the allowed values of an enum parameter.
+	Values []string `yaml:"values"`
+}
+
+// Constraints describes the accepted values, e.g. "int, 0..100" or "enum [github, gitlab]".
+func (p ParameterSpec) Constraints() string {
+	switch p.Type {
+	case ParamInt:
+		switch {
+		case p.Min != nil && p.Max != nil:
+			return fmt.Sprintf("int, %d..%d", *p.Min, *p.Max)
+		case p.Min != nil:
+			return fmt.Sprintf("int, >= %d", *p.Min)
+		case p.Max != nil:
+			return fmt.Sprintf("int, <= %d", *p.Max)
+		}
+	case ParamEnum:
+		return "enum [" + strings.Join(p.Values, ", ") + "]"
+	}
+	return p.Type
+}
+
+// ParameterNames returns the declared parameter names, sorted.
+func (m Manifest) ParameterNames() []string {
+	names := make([]string, 0, len(m.Parameters))
+	for name := range m.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveParameters validates config values against the schema and fills in defaults.
+func (m Manifest) ResolveParameters(values map[string]any) (map[string]any, error) {
+	var unknown []string
+	for name := range values {
+		if _, ok := m.Parameters[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		declared := "none"
+		if names := m.ParameterNames(); len(names) > 0 {
+			declared = strings.Join(names, ", ")
+		}
+		return nil, fmt.Errorf("unknown parameter(s) %s (profile %s declares: %s)", strings.Join(unknown, ", "), m.ID, declared)
+	}
+
+	out := map[string]any{}
+	for _, name := range m.ParameterNames() {
+		spec := m.Parameters[name]
+		v, ok := values[name]
+		if !ok {
+			if spec.Default == nil {
+				return nil, fmt.Errorf("parameter %s is required (%s)", name, spec.Constraints())
+			}
+			v = spec.Default
+		}
+		coerced, err := spec.check(v)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", name, err)
+		}
+		out[name] = coerced
+	}
+	return out, nil
+}
+
+func validateParameters(params map[string]ParameterSpec) error {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		spec := params[name]
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("parameters: name is required")
+		}
+		switch spec.Type {
+		case ParamString, ParamBool:
+		case ParamInt:
+			if spec.Min != nil && spec.Max != nil && *spec.Min > *spec.Max {
+				return fmt.Errorf("parameter %s: min %d is greater than max %d", name, *spec.Min, *spec.Max)
+			}
+		case ParamEnum:
+			if len(spec.Values) == 0 {
+				return fmt.Errorf("parameter %s: enum requires values", name)
+			}
+		default:
+			return fmt.Errorf("parameter %s: unknown type %q (want string, int, bool, or enum)", name, spec.Type)
+		}
+		if spec.Type != ParamInt && (spec.Min != nil || spec.Max != nil) {
+			return fmt.Errorf("parameter %s: min/max only apply to int parameters", name)
+		}
+		if spec.Type != ParamEnum && len(spec.Values) > 0 {
+			return fmt.Errorf("parameter %s: values only apply to enum parameters", name)
+		}
+		if spec.Default != nil {
+			if _, err := spec.check(spec.Default); err != nil {
+				return fmt.Errorf("parameter %s: invalid default: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// check validates v against the spec and returns it in canonical form (int, bool or string).
+func (p ParameterSpec) check(v any) (any, error) {
+	switch p.Type {
+	case ParamInt:
+		var n int
+		switch x := v.(type) {
+		case int:
+			n = x
+		case float64:
+			if x != math.Trunc(x) {
+				return nil, fmt.Errorf("expected int, got %v", x)
+			}
+			n = int(x)
+		default:
+			return nil, fmt.Errorf("expected int, got %s", describe(v))
+		}
+		if p.Min != nil && n < *p.Min {
+			return nil, fmt.Errorf("%d is below min %d", n, *p.Min)
+		}
+		if p.Max != nil && n > *p.Max {
+			return nil, fmt.Errorf("%d is above max %d", n, *p.Max)
+		}
+		return n, nil
+	case ParamBool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected bool, got %s", describe(v))
+		}
+		return b, nil
+	case ParamEnum:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected one of [%s], got %s", strings.Join(p.Values, ", "), describe(v))
+		}
+		for _, allowed := range p.Values {
+			if s == allowed {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not one of [%s]", s, strings.Join(p.Values, ", "))
+	default:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %s", describe(v))
+		}
+		return s, nil
+	}
+}
+
+func describe(v any) string {
+	switch x := v.(type) {
+	case string:
+		return fmt.Sprintf("string %q", x)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T %v", v, v)
+	}
+}
@@ -0,0 +1,109 @@
+package profile
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const parametersManifest = `
+schemaVersion: 1
+id: p
+parameters:
+  coverageThreshold:
+    type: int
+    min: 0
+    max: 100
+    default: 85
+    description: Minimum total coverage.
+  ciProvider:
+    type: enum
+    values: [github, gitlab]
+  strictLint:
+    type: bool
+    default: false
+  team:
+    type: string
+    default: platform
+`
+
+func TestResolveParameters_ValidatesValuesAndAppliesDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.yaml")
+	writeFile(t, path, strings.TrimSpace(parametersManifest)+"\n")
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if strings.Join(m.ParameterNames(), ",") != "ciProvider,coverageThreshold,strictLint,team" {
+		t.Fatalf("unexpected names: %v", m.ParameterNames())
+	}
+	if got := m.Parameters["coverageThreshold"].Constraints(); got != "int, 0..100" {
+		t.Fatalf("unexpected constraints: %q", got)
+	}
+	if got := m.Parameters["ciProvider"].Constraints(); got != "enum [github, gitlab]" {
+		t.Fatalf("unexpected constraints: %q", got)
+	}
+
+	got, err := m.ResolveParameters(map[string]any{"ciProvider": "gitlab", "coverageThreshold": 90.0})
+	if err != nil {
+		t.Fatalf("ResolveParameters: %v", err)
+	}
+	if got["ciProvider"] != "gitlab" || got["coverageThreshold"] != 90 || got["strictLint"] != false || got["team"] != "platform" {
+		t.Fatalf("unexpected values: %#v", got)
+	}
+
+	cases := []struct {
+		values map[string]any
+		want   string
+	}{
+		{map[string]any{}, "parameter ciProvider is required (enum [github, gitlab])"},
+		{map[string]any{"ciProvider": "bitbucket"}, `parameter ciProvider: "bitbucket" is not one of [github, gitlab]`},
+		{map[string]any{"ciProvider": 3}, "parameter ciProvider: expected one of [github, gitlab], got int 3"},
+		{map[string]any{"ciProvider": "github", "coverageThreshold": 120}, "parameter coverageThreshold: 120 is above max 100"},
+		{map[string]any{"ciProvider": "github", "coverageThreshold": -1}, "parameter coverageThreshold: -1 is below min 0"},
+		{map[string]any{"ciProvider": "github", "coverageThreshold": "ninety"}, `parameter coverageThreshold: expected int, got string "ninety"`},
+		{map[string]any{"ciProvider": "github", "coverageThreshold": 85.5}, "parameter coverageThreshold: expected int, got 85.5"},
+		{map[string]any{"ciProvider": "github", "strictLint": "yes"}, `parameter strictLint: expected bool, got string "yes"`},
+		{map[string]any{"ciProvider": "github", "team": true}, "parameter team: expected string, got bool true"},
+		{map[string]any{"ciProvider": "github", "colour": "x", "age": 1}, "unknown parameter(s) age, colour (profile p declares: ciProvider, coverageThreshold, strictLint, team)"},
+	}
+	for _, tc := range cases {
+		_, err := m.ResolveParameters(tc.values)
+		if err == nil || err.Error() != tc.want {
+			t.Fatalf("values %v: expected %q, got %v", tc.values, tc.want, err)
+		}
+	}
+}
+
+func TestLoadManifest_ValidatesParameterSchema(t *testing.T) {
+	cases := map[string]string{
+		"unknown type":                "parameters:\n  a:\n    type: float\n",
+		"min 5 is greater than max 1": "parameters:\n  a:\n    type: int\n    min: 5\n    max: 1\n",
+		"enum requires values":        "parameters:\n  a:\n    type: enum\n",
+		"min/max only apply":          "parameters:\n  a:\n    type: string\n    min: 1\n",
+		"values only apply":           "parameters:\n  a:\n    type: int\n    values: [x]\n",
+		"invalid default: 7 is above": "parameters:\n  a:\n    type: int\n    max: 5\n    default: 7\n",
+	}
+	for want, body := range cases {
+		path := filepath.Join(t.TempDir(), "profile.yaml")
+		writeFile(t, path, "schemaVersion: 1\nid: x\n"+body)
+		if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
+
+func TestLoadManifest_OverlayReplacesParameterSpecs(t *testing.T) {
+	tmp := t.TempDir()
+	mkdirAll(t, filepath.Join(tmp, "base"), filepath.Join(tmp, "child"))
+	writeFile(t, filepath.Join(tmp, "base", "profile.yaml"), "schemaVersion: 1\nid: base\nparameters:\n  a:\n    type: string\n    default: x\n  b:\n    type: bool\n    default: true\n")
+	path := filepath.Join(tmp, "child", "profile.yaml")
+	writeFile(t, path, "schemaVersion: 1\nid: child\nextends: [../base/profile.yaml]\nparameters:\n  a:\n    type: int\n    default: 1\n")
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if m.Parameters["a"].Type != "int" || m.Parameters["b"].Type != "bool" {
+		t.Fatalf("unexpected parameters: %+v", m.Parameters)
+	}
+}
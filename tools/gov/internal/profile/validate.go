@@ -0,0 +1,190 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProfilesDir is the location of profile manifests relative to a governance source root.
+const ProfilesDir = "Governance/Profiles"
+
+// SharedDirs are source directories whose files are only useful when a profile references them.
+var SharedDirs = []string{"Governance/Core", "Governance/Templates"}
+
+// Problem is a validation finding. Profile is empty for findings about the source tree itself.
+type Problem struct {
+	Profile string
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Profile == "" {
+		return p.Message
+	}
+	return p.Profile + ": " + p.Message
+}
+
+// ListIDs returns the IDs of all profiles (directories with a profile.yaml) in the
+// governance source fsys, sorted.
+func ListIDs(fsys fs.FS) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, ProfilesDir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := fs.Stat(fsys, path.Join(ProfilesDir, e.Name(), "profile.yaml")); err == nil {
+			ids = append(ids, e.Name())
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// ManifestPath returns the manifest path for profile id under root.
+func ManifestPath(root, id string) string {
+	return filepath.Join(root, filepath.FromSlash(ProfilesDir), id, "profile.yaml")
+}
+
+type ValidateOptions struct {
+	// Root is the governance source root (the directory containing Governance/).
+	Root string
+	// FS serves Root's files; nil reads them from disk.
+	FS fs.FS
+	// IDs are the profiles to validate.
+	IDs []string
+	// CheckUnused reports files under SharedDirs that none of IDs reference.
+	// Only meaningful when IDs covers every profile.
+	CheckUnused bool
+	// Resolve fetches cross-repository extends; files from those checkouts are accepted
+	// without counting towards Root's usage.
+	Resolve Resolver
+}
+
+// Validate resolves each profile and checks that every referenced file exists inside Root.
+func Validate(opts ValidateOptions) ([]Problem, error) {
+	root, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	if opts.FS == nil {
+		opts.FS = os.DirFS(root)
+	}
+	var problems []Problem
+	used := map[string]bool{}
+	for _, id := range opts.IDs {
+		m, err := LoadManifestWith(ManifestPath(root, id), LoadOptions{Resolve: opts.Resolve, Root: root, FS: opts.FS})
+		if err != nil {
+			problems = append(problems, Problem{Profile: id, Message: err.Error()})
+			continue
+		}
+		src := files{}.with(root, opts.FS)
+		for _, s := range m.Sources {
+			src = src.with(s.Dir, s.FS)
+		}
+		check := func(kind, output, path string) {
+			if strings.TrimSpace(path) == "" {
+				problems = append(problems, Problem{Profile: id, Message: fmt.Sprintf("%s %s: empty source path", kind, output)})
+				return
+			}
+			rel, ok := relInside(root, path)
+			if ok {
+				used[rel] = true
+			} else {
+				for _, s := range m.Sources {
+					if rel, ok = relInside(s.Dir, path); ok {
+						break
+					}
+				}
+			}
+			if !ok {
+				problems = append(problems, Problem{Profile: id, Message: fmt.Sprintf("%s %s: %s is outside the source root", kind, output, path)})
+				return
+			}
+			info, err := src.Stat(path)
+			if err != nil {
+				problems = append(problems, Problem{Profile: id, Message: fmt.Sprintf("%s %s: missing %s", kind, output, rel)})
+				return
+			}
+			if info.IsDir() {
+				problems = append(problems, Problem{Profile: id, Message: fmt.Sprintf("%s %s: %s is a directory", kind, output, rel)})
+			}
+		}
+		for _, d := range m.Documents {
+			for _, b := range d.Blocks() {
+				if len(b.Fragments) > 0 {
+					continue
+				}
+				if b.Name != "" {
+					problems = append(problems, Problem{Profile: id, Message: fmt.Sprintf("document %s section %s: no fragments", d.Output, b.Name)})
+					continue
+				}
+				problems = append(problems, Problem{Profile: id, Message: fmt.Sprintf("document %s: no fragments", d.Output)})
+			}
+			for _, frag := range d.FragmentPaths() {
+				check("document", d.Output, frag)
+			}
+		}
+		for _, t := range m.Templates {
+			check("template", t.Output, t.Source)
+		}
+		for _, p := range m.Playbooks {
+			check("playbook", p.Output, p.Source)
+		}
+	}
+
+	if opts.CheckUnused {
+		unused, err := unusedFiles(opts.FS, used)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range unused {
+			problems = append(problems, Problem{Message: fmt.Sprintf("unused file %s (not referenced by any profile)", rel)})
+		}
+	}
+	return problems, nil
+}
+
+// relInside returns path relative to dir (slash-separated) when path is inside dir.
+func relInside(dir, path string) (string, bool) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+func unusedFiles(fsys fs.FS, used map[string]bool) ([]string, error) {
+	var unused []string
+	for _, dir := range SharedDirs {
+		if _, err := fs.Stat(fsys, dir); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		err := fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || d.Name() == "README.md" || strings.HasPrefix(d.Name(), ".") {
+				return nil
+			}
+			if !used[p] {
+				unused = append(unused, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(unused)
+	return unused, nil
+}
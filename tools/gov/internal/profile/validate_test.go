@@ -0,0 +1,134 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestValidate_ReportsMissingEscapingAndUnusedFiles(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	profiles := filepath.Join(root, "Governance", "Profiles")
+	mkdirAll(t,
+		filepath.Join(root, "Governance", "Core"),
+		filepath.Join(root, "Governance", "Templates", "Plans"),
+		filepath.Join(profiles, "good"),
+		filepath.Join(profiles, "bad"),
+		filepath.Join(profiles, "broken"),
+		filepath.Join(profiles, "not-a-profile"),
+	)
+	writeFile(t, filepath.Join(root, "Governance", "Core", "Core.md"), "core\n")
+	writeFile(t, filepath.Join(root, "Governance", "Core", "README.md"), "ignored\n")
+	writeFile(t, filepath.Join(root, "Governance", "Templates", "Plans", "Plan.md"), "plan\n")
+	writeFile(t, filepath.Join(root, "Governance", "Templates", "Orphan.md"), "orphan\n")
+	writeFile(t, filepath.Join(outside, "x.md"), "x\n")
+
+	writeFile(t, filepath.Join(profiles, "good", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: good
+documents:
+  - output: A.md
+    fragments: [../../Core/Core.md]
+templates:
+  - source: ../../Templates/Plans/Plan.md
+    output: Docs/Plan.md
+`)+"\n")
+	writeFile(t, filepath.Join(profiles, "bad", "profile.yaml"), strings.TrimSpace(`
+schemaVersion: 1
+id: bad
+documents:
+  - output: B.md
+    fragments: [./Missing.md, `+filepath.Join(outside, "x.md")+`]
+  - output: Empty.md
+playbooks:
+  - source: ../../Core
+    output: Docs/P.md
+`)+"\n")
+	writeFile(t, filepath.Join(profiles, "broken", "profile.yaml"), "schemaVersion: 2\nid: broken\n")
+
+	ids, err := ListIDs(os.DirFS(root))
+	if err != nil {
+		t.Fatalf("ListIDs: %v", err)
+	}
+	if strings.Join(ids, ",") != "bad,broken,good" {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+
+	problems, err := Validate(ValidateOptions{Root: root, IDs: ids, CheckUnused: true})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"bad: document B.md: missing Governance/Profiles/bad/Missing.md",
+		"bad: document B.md: " + filepath.Join(outside, "x.md") + " is outside the source root",
+		"bad: document Empty.md: no fragments",
+		"bad: playbook Docs/P.md: Governance/Core is a directory",
+		"broken: profile schemaVersion must be 1",
+		"unused file Governance/Templates/Orphan.md (not referenced by any profile)",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d problems, got %d:\n%s", len(want), len(got), strings.Join(got, "\n"))
+	}
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Fatalf("problem %d: expected prefix %q, got %q", i, want[i], got[i])
+		}
+	}
+
+	problems, err = Validate(ValidateOptions{Root: root, IDs: []string{"good"}})
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("expected good profile to validate, got %v %v", problems, err)
+	}
+}
+
+func TestValidate_AcceptsFilesFromCrossRepoBases(t *testing.T) {
+	root := t.TempDir()
+	remote := t.TempDir()
+	mkdirAll(t, filepath.Join(root, "Governance", "Profiles", "org"), filepath.Join(remote, "Governance", "Profiles", "base"))
+	writeFile(t, filepath.Join(remote, "Governance", "Profiles", "base", "profile.yaml"), "schemaVersion: 1\nid: base\ndocuments:\n  - output: A.md\n    fragments: [a.md, gone.md]\n")
+	writeFile(t, filepath.Join(remote, "Governance", "Profiles", "base", "a.md"), "a\n")
+	writeFile(t, filepath.Join(root, "Governance", "Profiles", "org", "profile.yaml"), "schemaVersion: 1\nid: org\nextends: [\"gov.git@v1#Governance/Profiles/base\"]\n")
+
+	problems, err := Validate(ValidateOptions{Root: root, IDs: []string{"org"}, Resolve: func(repo, ref string) (Source, error) {
+		return Source{Repo: repo, Ref: ref, Commit: "abc", Dir: remote}, nil
+	}})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if len(problems) != 1 || problems[0].String() != "org: document A.md: missing Governance/Profiles/base/gone.md" {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+}
+
+func TestValidate_ReadsSourceThroughFS(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "checkout")
+	files := fstest.MapFS{
+		"Governance/Profiles/p/profile.yaml":  {Data: []byte("schemaVersion: 1\nid: p\ndocuments:\n  - output: A.md\n    fragments: [../../Core/a.md, ../../Core/gone.md]\n")},
+		"Governance/Profiles/notes/README.md": {Data: []byte("not a profile\n")},
+		"Governance/Core/a.md":                {Data: []byte("a\n")},
+		"Governance/Core/orphan.md":           {Data: []byte("o\n")},
+	}
+	ids, err := ListIDs(files)
+	if err != nil || strings.Join(ids, ",") != "p" {
+		t.Fatalf("ListIDs: %v %v", ids, err)
+	}
+	problems, err := Validate(ValidateOptions{Root: root, FS: files, IDs: ids, CheckUnused: true})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	want := "p: document A.md: missing Governance/Core/gone.md;unused file Governance/Core/orphan.md (not referenced by any profile)"
+	if strings.Join(got, ";") != want {
+		t.Fatalf("unexpected problems:\n%s", strings.Join(got, "\n"))
+	}
+}
@@ -0,0 +1,120 @@
+package refactorcheck
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BennettSmith/agent-governance-strategy/tools/gov/internal/pathglob"
+)
+
+// DefaultExpectations are paths that hold recorded behavior (golden/snapshot files and fixtures).
+var DefaultExpectations = []string{
+	"**/testdata/**",
+	"**/*.golden",
+	"**/__snapshots__/**",
+	"**/*.snap",
+}
+
+// Change is one entry of `git diff --name-status`.
+type Change struct {
+	// Status is the single-letter git status (A, M, D, R, C, T).
+	Status string
+	Path   string
+	// OldPath is set for renames and copies.
+	OldPath string
+}
+
+// Rules describe which paths a refactor must not change.
+type Rules struct {
+	// Expectations may gain new files (new characterization tests) but existing
+	// files must not be modified, deleted, or renamed.
+	Expectations []string
+	// Boundaries are public API surfaces; any change to them is a behavior change.
+	Boundaries []string
+}
+
+type Finding struct {
+	Path   string
+	Reason string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s (%s)", f.Path, f.Reason)
+}
+
+// ParseNameStatus parses `git diff --name-status -z` output: NUL-terminated fields, with
+// renames and copies carrying the old path before the new one. Paths are taken verbatim,
+// so names with spaces, tabs or non-ASCII characters need no unquoting.
+func ParseNameStatus(out string) []Change {
+	var changes []Change
+	fields := strings.Split(out, "\x00")
+	for i := 0; i < len(fields); i++ {
+		status := strings.TrimSpace(fields[i])
+		if status == "" {
+			continue
+		}
+		c := Change{Status: status[:1]}
+		if (c.Status == "R" || c.Status == "C") && i+2 < len(fields) {
+			c.OldPath, c.Path = fields[i+1], fields[i+2]
+			i += 2
+		} else if i+1 < len(fields) {
+			c.Path = fields[i+1]
+			i++
+		} else {
+			break
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// Check returns the changes that look like behavior changes under rules.
+func Check(changes []Change, rules Rules) []Finding {
+	var findings []Finding
+	for _, c := range changes {
+		if f, ok := checkChange(c, rules); ok {
+			findings = append(findings, f)
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Path < findings[j].Path })
+	return findings
+}
+
+func checkChange(c Change, rules Rules) (Finding, bool) {
+	// A rename removes the old path, so both sides count; a copy leaves its source
+	// untouched and only adds the new path.
+	paths := []string{c.Path}
+	if c.Status == "R" && c.OldPath != "" {
+		paths = append(paths, c.OldPath)
+	}
+	for _, p := range paths {
+		if pathglob.MatchAny(rules.Boundaries, p) {
+			return Finding{Path: c.Path, Reason: fmt.Sprintf("%s public API boundary", statusVerb(c.Status))}, true
+		}
+	}
+	if c.Status == "A" || c.Status == "C" {
+		return Finding{}, false
+	}
+	for _, p := range paths {
+		if pathglob.MatchAny(rules.Expectations, p) {
+			return Finding{Path: c.Path, Reason: fmt.Sprintf("%s test expectation", statusVerb(c.Status))}, true
+		}
+	}
+	return Finding{}, false
+}
+
+func statusVerb(status string) string {
+	switch status {
+	case "A":
+		return "adds"
+	case "D":
+		return "deletes"
+	case "R":
+		return "renames"
+	case "C":
+		return "copies"
+	default:
+		return "modifies"
+	}
+}
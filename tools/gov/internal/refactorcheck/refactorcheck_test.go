@@ -0,0 +1,77 @@
+package refactorcheck
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseNameStatus(t *testing.T) {
+	out := "M\x00internal/a.go\x00R087\x00old/x.golden\x00new/x.golden\x00A\x00b.go\x00"
+	changes := ParseNameStatus(out)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+	if changes[1].Status != "R" || changes[1].OldPath != "old/x.golden" || changes[1].Path != "new/x.golden" {
+		t.Fatalf("unexpected rename: %+v", changes[1])
+	}
+	if changes[2].Status != "A" || changes[2].Path != "b.go" {
+		t.Fatalf("unexpected add: %+v", changes[2])
+	}
+}
+
+func TestParseNameStatus_PathsNeedingQuotes(t *testing.T) {
+	out := "M\x00testdata/café out.golden\x00C100\x00api/a\tb.proto\x00api/\"c\".proto\x00"
+	changes := ParseNameStatus(out)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Path != "testdata/café out.golden" {
+		t.Fatalf("unexpected path: %q", changes[0].Path)
+	}
+	if changes[1].Status != "C" || changes[1].OldPath != "api/a\tb.proto" || changes[1].Path != `api/"c".proto` {
+		t.Fatalf("unexpected copy: %+v", changes[1])
+	}
+}
+
+func TestCheck_FlagsExpectationsAndBoundaries(t *testing.T) {
+	changes := []Change{
+		{Status: "M", Path: "internal/service/service.go"},
+		{Status: "M", Path: "internal/service/testdata/out.json"},
+		{Status: "A", Path: "internal/service/testdata/new.json"},
+		{Status: "D", Path: "render/page.golden"},
+		{Status: "A", Path: "api/v1/new.proto"},
+	}
+	findings := Check(changes, Rules{Expectations: DefaultExpectations, Boundaries: []string{"api/**"}})
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+	want := []string{
+		"api/v1/new.proto (adds public API boundary)",
+		"internal/service/testdata/out.json (modifies test expectation)",
+		"render/page.golden (deletes test expectation)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected findings:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestCheck_RenameOutOfBoundaryIsFlagged(t *testing.T) {
+	changes := []Change{{Status: "R", OldPath: "api/v1/x.proto", Path: "internal/x.proto"}}
+	if findings := Check(changes, Rules{Boundaries: []string{"api/**"}}); len(findings) != 1 {
+		t.Fatalf("expected rename out of boundary to be flagged, got %+v", findings)
+	}
+}
+
+func TestCheck_CopiesAreJudgedByTheirNewPath(t *testing.T) {
+	rules := Rules{Expectations: DefaultExpectations, Boundaries: []string{"api/**"}}
+	changes := []Change{
+		{Status: "C", OldPath: "api/v1/x.proto", Path: "internal/x.proto"},
+		{Status: "C", OldPath: "svc/testdata/a.json", Path: "svc/testdata/b.json"},
+		{Status: "C", OldPath: "internal/y.proto", Path: "api/v1/y.proto"},
+	}
+	findings := Check(changes, rules)
+	if len(findings) != 1 || findings[0].String() != "api/v1/y.proto (copies public API boundary)" {
+		t.Fatalf("unexpected findings: %+v", findings)
+	}
+}
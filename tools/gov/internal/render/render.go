@@ -0,0 +1,181 @@
+package render
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Merge deep-merges overlay onto base. Nested maps are merged key by key; other values replace.
+func Merge(base, overlay map[string]any) map[string]any {
+	if len(base) == 0 && len(overlay) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(base)+len(overlay))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range overlay {
+		if bm, ok := out[k].(map[string]any); ok {
+			if om, ok := v.(map[string]any); ok {
+				out[k] = Merge(bm, om)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// IsTemplate reports whether text contains template actions.
+func IsTemplate(text string) bool {
+	return strings.Contains(text, "{{")
+}
+
+// Fragment renders text with vars. Referencing an undefined variable is an error.
+func Fragment(name, text string, vars map[string]any) (string, error) {
+	if !IsTemplate(text) {
+		return text, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+	if vars == nil {
+		vars = map[string]any{}
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// Digest returns a stable sha256 of vars (map keys are sorted by encoding/json).
+func Digest(vars map[string]any) (string, error) {
+	if vars == nil {
+		vars = map[string]any{}
+	}
+	b, err := json.Marshal(vars)
+	if err != nil {
+		return "", fmt.Errorf("encode variables: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Used returns the part of vars that text's template actions reference: every `.A.B`
+// (or `$.A.B`) chain selects that path, so changing any other variable leaves the result
+// unchanged. A bare root `.` references everything.
+func Used(name, text string, vars map[string]any) (map[string]any, error) {
+	if !IsTemplate(text) {
+		return nil, nil
+	}
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	var paths [][]string
+	all := false
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			walkRefs(t.Tree.Root, true, &paths, &all)
+		}
+	}
+	if all {
+		return vars, nil
+	}
+	var out map[string]any
+	for _, p := range paths {
+		out = Merge(out, project(vars, p))
+	}
+	return out, nil
+}
+
+// walkRefs collects the variable paths n references. rooted reports whether dot is
+// still the root variables; inside range and with it is rebound to a value whose path
+// the pipeline already selected, so only `$` chains count there.
+func walkRefs(n parse.Node, rooted bool, paths *[][]string, all *bool) {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			walkRefs(c, rooted, paths, all)
+		}
+	case *parse.ActionNode:
+		walkRefs(n.Pipe, rooted, paths, all)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Cmds {
+			walkRefs(c, rooted, paths, all)
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			walkRefs(a, rooted, paths, all)
+		}
+	case *parse.FieldNode:
+		if rooted {
+			*paths = append(*paths, n.Ident)
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" {
+			if len(n.Ident) == 1 {
+				*all = true
+			} else {
+				*paths = append(*paths, n.Ident[1:])
+			}
+		}
+	case *parse.ChainNode:
+		if _, ok := n.Node.(*parse.DotNode); ok {
+			if rooted {
+				*paths = append(*paths, n.Field)
+			}
+		} else {
+			walkRefs(n.Node, rooted, paths, all)
+		}
+	case *parse.DotNode:
+		if rooted {
+			*all = true
+		}
+	case *parse.IfNode:
+		walkRefs(n.Pipe, rooted, paths, all)
+		walkRefs(n.List, rooted, paths, all)
+		walkRefs(n.ElseList, rooted, paths, all)
+	case *parse.RangeNode:
+		walkRefs(n.Pipe, rooted, paths, all)
+		walkRefs(n.List, false, paths, all)
+		walkRefs(n.ElseList, rooted, paths, all)
+	case *parse.WithNode:
+		walkRefs(n.Pipe, rooted, paths, all)
+		walkRefs(n.List, false, paths, all)
+		walkRefs(n.ElseList, rooted, paths, all)
+	case *parse.TemplateNode:
+		// The invoked template sees the pipeline's value, which the pipeline selected.
+		walkRefs(n.Pipe, rooted, paths, all)
+	}
+}
+
+// project returns vars restricted to path; a path through a non-map value selects the
+// whole value.
+func project(vars map[string]any, path []string) map[string]any {
+	v, ok := vars[path[0]]
+	if !ok {
+		return nil
+	}
+	if sub, isMap := v.(map[string]any); isMap && len(path) > 1 {
+		if inner := project(sub, path[1:]); inner != nil {
+			return map[string]any{path[0]: inner}
+		}
+		return nil
+	}
+	return map[string]any{path[0]: v}
+}
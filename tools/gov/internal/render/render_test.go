@@ -0,0 +1,94 @@
+package render
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMerge_DeepMergesNestedMaps(t *testing.T) {
+	base := map[string]any{
+		"Project":  map[string]any{"Name": "base", "DefaultBranch": "main"},
+		"Coverage": 85,
+	}
+	overlay := map[string]any{
+		"Project":  map[string]any{"Name": "svc"},
+		"Coverage": 90,
+	}
+	got := Merge(base, overlay)
+	project := got["Project"].(map[string]any)
+	if project["Name"] != "svc" || project["DefaultBranch"] != "main" || got["Coverage"] != 90 {
+		t.Fatalf("unexpected merge: %#v", got)
+	}
+	if base["Project"].(map[string]any)["Name"] != "base" {
+		t.Fatalf("base mutated: %#v", base)
+	}
+	if Merge(nil, nil) != nil {
+		t.Fatalf("expected nil for empty inputs")
+	}
+}
+
+func TestFragment_RendersAndIsStrictOnUndefined(t *testing.T) {
+	vars := map[string]any{"Project": map[string]any{"Name": "svc"}}
+	got, err := Fragment("a.md", "Project {{ .Project.Name }}.\n", vars)
+	if err != nil || got != "Project svc.\n" {
+		t.Fatalf("unexpected render %q err=%v", got, err)
+	}
+	if got, err := Fragment("plain.md", "no placeholders\n", nil); err != nil || got != "no placeholders\n" {
+		t.Fatalf("expected passthrough, got %q err=%v", got, err)
+	}
+	if _, err := Fragment("a.md", "{{ .Project.Module }}", vars); err == nil || !strings.Contains(err.Error(), "Module") {
+		t.Fatalf("expected undefined variable error, got %v", err)
+	}
+	if _, err := Fragment("a.md", "{{ .Missing }}", nil); err == nil {
+		t.Fatalf("expected undefined variable error with no vars")
+	}
+	if _, err := Fragment("a.md", "{{ .Project.Name ", vars); err == nil || !strings.Contains(err.Error(), "parse template") {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+}
+
+func TestDigest_IsStableAcrossMapOrder(t *testing.T) {
+	a, err := Digest(map[string]any{"A": 1, "B": map[string]any{"X": "y", "Z": true}})
+	if err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+	b, _ := Digest(map[string]any{"B": map[string]any{"Z": true, "X": "y"}, "A": 1})
+	c, _ := Digest(map[string]any{"A": 2})
+	if a != b || a == c || len(a) != 64 {
+		t.Fatalf("unexpected digests a=%s b=%s c=%s", a, b, c)
+	}
+	if _, err := Digest(map[string]any{"F": func() {}}); err == nil {
+		t.Fatalf("expected encode error")
+	}
+}
+
+func TestUsed_SelectsReferencedVariables(t *testing.T) {
+	vars := map[string]any{
+		"Project":  map[string]any{"Name": "payments", "Owner": "team-a"},
+		"Coverage": map[string]any{"Threshold": 85},
+		"Teams":    []any{"a", "b"},
+		"Unused":   true,
+	}
+	got, err := Used("a.md", "{{ .Project.Name }} {{ if $.Coverage }}{{ .Coverage.Threshold }}{{ end }}{{ range .Teams }}{{ . }}{{ end }}", vars)
+	if err != nil {
+		t.Fatalf("Used: %v", err)
+	}
+	want := map[string]any{
+		"Project":  map[string]any{"Name": "payments"},
+		"Coverage": map[string]any{"Threshold": 85},
+		"Teams":    []any{"a", "b"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Used = %v, want %v", got, want)
+	}
+	if got, _ := Used("a.md", "{{ printf \"%v\" . }}", vars); !reflect.DeepEqual(got, vars) {
+		t.Fatalf("expected bare dot to use every variable, got %v", got)
+	}
+	if got, _ := Used("a.md", "plain", vars); got != nil {
+		t.Fatalf("expected nil for plain text, got %v", got)
+	}
+	if _, err := Used("a.md", "{{ .X ", vars); err == nil {
+		t.Fatalf("expected parse error")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,6 +19,9 @@ type ResolvedSource struct {
 	SourceRepo   string
 	SourceRef    string
 	SourceCommit string
+	// FS serves the checkout's fragments and templates (names relative to CheckoutDir);
+	// nil reads CheckoutDir on disk.
+	FS fs.FS
 }
 
 type FetchOptions struct {
@@ -26,6 +30,9 @@ type FetchOptions struct {
 	CacheDir string
 }
 
+// Fetcher resolves a governance source into a local checkout; Fetch is the default.
+type Fetcher func(ctx context.Context, opts FetchOptions) (ResolvedSource, error)
+
 func Fetch(ctx context.Context, opts FetchOptions) (ResolvedSource, error) {
 	if strings.TrimSpace(opts.RepoURL) == "" {
 		return ResolvedSource{}, errors.New("repo url is required")